@@ -0,0 +1,142 @@
+/*
+Package accesslog parses web server access logs in the Common Log Format
+and the Combined Log Format, as written by Apache httpd and by nginx's
+default "combined" format:
+
+	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 200 2326 "http://example.com/" "Mozilla/5.0"
+
+A *Entry implements encoding.TextUnmarshaler, so an access log line
+embedded in other text can be extracted with re.Scan:
+
+	var e accesslog.Entry
+	err := re.Scan(regexp.MustCompile(`^\S+ nginx: (.*)$`), line, &e)
+*/
+package accesslog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+// An Entry is one access log line.
+type Entry struct {
+	RemoteAddr string
+	Ident      string // Usually "-"
+	User       string // "-" if not authenticated
+	Time       time.Time
+	Request    string // Entire request line, e.g., "GET /a.gif HTTP/1.0"
+	Method     string // Empty if Request is malformed
+	Path       string // Empty if Request is malformed
+	Protocol   string // Empty if Request is malformed or lacks a protocol
+	Status     int
+	Bytes      int64  // Size of the response body; 0 if logged as "-"
+	Referer    string // Only in the Combined Log Format
+	UserAgent  string // Only in the Combined Log Format
+}
+
+// quoted matches a double-quoted field, allowing backslash escapes.
+const quoted = `"((?:[^"\\]|\\.)*)"`
+
+// Pattern matches a line in the Common Log Format, optionally followed by
+// the referer and user agent of the Combined Log Format.  Text after
+// those fields, such as extra fields of a custom nginx log_format, is
+// ignored.
+var Pattern = regexp.MustCompile(`^(?P<addr>\S+) (?P<ident>\S+) (?P<user>\S+) \[(?P<time>[^\]]+)\] ` +
+	`(?P<request>` + quoted + `) (?P<status>\d{3}) (?P<bytes>\d+|-)` +
+	`(?: (?P<referer>` + quoted + `) (?P<agent>` + quoted + `))?`)
+
+// requestPattern splits a request line.
+var requestPattern = regexp.MustCompile(`^([A-Za-z]+) (\S+)(?: (\S+))?$`)
+
+// TimeLayout is the layout of access log timestamps.
+const TimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// Parse parses an access log line.
+func Parse(line []byte) (Entry, error) {
+	var e Entry
+	err := re.Scan(Pattern, line,
+		re.Named("addr", &e.RemoteAddr),
+		re.Named("ident", &e.Ident),
+		re.Named("user", &e.User),
+		re.Named("time", re.Time(&e.Time, TimeLayout)),
+		re.Named("request", unescape(&e.Request)),
+		re.Named("status", &e.Status),
+		re.Named("bytes", func(b []byte) error {
+			if string(b) == "-" {
+				return nil
+			}
+			return re.Dec(&e.Bytes)(b)
+		}),
+		re.Named("referer", unescape(&e.Referer)),
+		re.Named("agent", unescape(&e.UserAgent)),
+	)
+	if errors.Is(err, re.NotFound) {
+		return Entry{}, fmt.Errorf("accesslog: malformed line %q", line)
+	} else if err != nil {
+		return Entry{}, fmt.Errorf("accesslog: %w", err)
+	}
+	re.Scan(requestPattern, []byte(e.Request), &e.Method, &e.Path, &e.Protocol)
+	return e, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by calling Parse.
+func (e *Entry) UnmarshalText(text []byte) error {
+	p, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*e = p
+	return nil
+}
+
+// unescape returns an output argument for re.Scan that removes the
+// quotes around a quoted field, decodes the escapes written by Apache
+// (\" and \\) and nginx (\xHH), and stores the result into *s.  A
+// missing (non-participating) field leaves *s empty.
+func unescape(s *string) func([]byte) error {
+	return func(b []byte) error {
+		if b == nil {
+			*s = ""
+			return nil
+		}
+		q := string(b[1 : len(b)-1])
+		if !strings.Contains(q, `\`) {
+			*s = q
+			return nil
+		}
+		var out strings.Builder
+		for i := 0; i < len(q); i++ {
+			c := q[i]
+			if c != '\\' || i+1 == len(q) {
+				out.WriteByte(c)
+				continue
+			}
+			i++
+			switch q[i] {
+			case 'x':
+				if i+2 < len(q) {
+					if v, err := strconv.ParseUint(q[i+1:i+3], 16, 8); err == nil {
+						out.WriteByte(byte(v))
+						i += 2
+						continue
+					}
+				}
+				out.WriteString(`\x`)
+			case 'n':
+				out.WriteByte('\n')
+			case 't':
+				out.WriteByte('\t')
+			default:
+				out.WriteByte(q[i])
+			}
+		}
+		*s = out.String()
+		return nil
+	}
+}
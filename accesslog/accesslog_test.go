@@ -0,0 +1,82 @@
+package accesslog_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/accesslog"
+)
+
+func TestParse(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  accesslog.Entry
+	}{
+		{
+			`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`,
+			accesslog.Entry{RemoteAddr: "127.0.0.1", Ident: "-", User: "frank", Request: "GET /apache_pb.gif HTTP/1.0",
+				Method: "GET", Path: "/apache_pb.gif", Protocol: "HTTP/1.0", Status: 200, Bytes: 2326},
+		},
+		{
+			`::1 - - [10/Oct/2000:13:55:36 -0700] "POST /api?q=\"x\" HTTP/1.1" 304 - "http://example.com/" "Mozilla/5.0 (X11; Linux)" rt=0.5`,
+			accesslog.Entry{RemoteAddr: "::1", Ident: "-", User: "-", Request: `POST /api?q="x" HTTP/1.1`,
+				Method: "POST", Path: `/api?q="x"`, Protocol: "HTTP/1.1", Status: 304,
+				Referer: "http://example.com/", UserAgent: "Mozilla/5.0 (X11; Linux)"},
+		},
+		{
+			// nginx escapes, a malformed request, and an empty referer.
+			`10.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "\x16\x03\x01" 400 157 "-" "say \x22hi\x22"`,
+			accesslog.Entry{RemoteAddr: "10.0.0.1", Ident: "-", User: "-", Request: "\x16\x03\x01",
+				Status: 400, Bytes: 157, Referer: "-", UserAgent: `say "hi"`},
+		},
+		{
+			`10.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /" 200 0`,
+			accesslog.Entry{RemoteAddr: "10.0.0.1", Ident: "-", User: "-", Request: "GET /",
+				Method: "GET", Path: "/", Status: 200},
+		},
+		{
+			// Some servers pad sizes with zeros, which are not octal.
+			`10.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /" 200 0010`,
+			accesslog.Entry{RemoteAddr: "10.0.0.1", Ident: "-", User: "-", Request: "GET /",
+				Method: "GET", Path: "/", Status: 200, Bytes: 10},
+		},
+	} {
+		got, err := accesslog.Parse([]byte(c.input))
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		want := time.Date(2000, 10, 10, 20, 55, 36, 0, time.UTC)
+		if !got.Time.Equal(want) {
+			t.Errorf("Parse(%q).Time = %v; expected %v", c.input, got.Time, want)
+		}
+		got.Time = time.Time{}
+		if got != c.want {
+			t.Errorf("Parse(%q) =\n%+v\nexpected\n%+v", c.input, got, c.want)
+		}
+	}
+	for _, bad := range []string{
+		"",
+		`127.0.0.1 - - [yesterday] "GET / HTTP/1.0" 200 1`,
+		`127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200`,
+		`127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200 99999999999999999999`,
+	} {
+		if e, err := accesslog.Parse([]byte(bad)); err == nil {
+			t.Errorf("Parse(%q) = %+v; expected an error", bad, e)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	var host string
+	var e accesslog.Entry
+	line := []byte(`web1 nginx: 1.2.3.4 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 200 5 "-" "curl"`)
+	if err := re.Scan(regexp.MustCompile(`^(\S+) nginx: (.*)$`), line, &host, &e); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if host != "web1" || e.RemoteAddr != "1.2.3.4" || e.UserAgent != "curl" || e.Bytes != 5 {
+		t.Errorf("got %q, %+v", host, e)
+	}
+}
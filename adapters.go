@@ -0,0 +1,88 @@
+//go:build !re_minimal
+
+package re
+
+import (
+	"encoding/json"
+	"flag"
+	"math/big"
+	"regexp"
+)
+
+// adapterAssigner returns the assigner for r if r points to one of the
+// numeric types of packages encoding/json and math/big.
+func adapterAssigner(r interface{}) (assigner, bool) {
+	switch v := r.(type) {
+	case *json.Number:
+		return func(b []byte, _ Span) error {
+			if !jsonNumber.Match(b) {
+				return parseError("invalid JSON number", b)
+			}
+			*v = json.Number(b)
+			return nil
+		}, true
+	case *big.Int:
+		return func(b []byte, _ Span) error {
+			if _, ok := v.SetString(string(b), 0); !ok {
+				return parseError("invalid integer", b)
+			}
+			return nil
+		}, true
+	case *big.Float:
+		return func(b []byte, _ Span) error {
+			if _, ok := v.SetString(string(b)); !ok {
+				return parseError("invalid floating-point number", b)
+			}
+			return nil
+		}, true
+	case *big.Rat:
+		return func(b []byte, _ Span) error {
+			if _, ok := v.SetString(string(b)); !ok {
+				return parseError("invalid rational number", b)
+			}
+			return nil
+		}, true
+	}
+	return nil, false
+}
+
+// jsonNumber matches a number in JSON syntax.
+var jsonNumber = regexp.MustCompile(`^-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][-+]?\d+)?$`)
+
+// flagAssigner returns the assigner for r if r implements flag.Value.
+func flagAssigner(r interface{}) (assigner, bool) {
+	f, ok := r.(flag.Value)
+	if !ok {
+		return nil, false
+	}
+	return func(b []byte, _ Span) error { return f.Set(string(b)) }, true
+}
+
+// jsonUnquote decodes b, a string literal in JSON syntax, reporting
+// whether it is valid.
+func jsonUnquote(b []byte) (string, bool) {
+	var s string
+	if len(b) == 0 || b[0] != '"' || json.Unmarshal(b, &s) != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// JSON returns an output argument for Scan that decodes the sub-match
+// with json.Unmarshal into v, which must be a non-nil pointer.  It suits
+// log lines that embed a JSON value after a textual prefix:
+//
+//	var event struct {
+//		User string `json:"user"`
+//		Code int    `json:"code"`
+//	}
+//	reg := regexp.MustCompile(`^\S+ \S+ event=(\{.*\})$`)
+//	err := re.Scan(reg, line, re.JSON(&event))
+func JSON(v interface{}) func([]byte) error {
+	return func(b []byte) error {
+		if err := json.Unmarshal(b, v); err != nil {
+			return parseError(err.Error(), b)
+		}
+		return nil
+	}
+}
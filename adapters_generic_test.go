@@ -0,0 +1,22 @@
+//go:build go1.18 && !re_minimal
+
+package re_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestDefaultSQLNull(t *testing.T) {
+	reg := regexp.MustCompile(`^(\w+)://([^/:]+)(?::(\d*))?`)
+	var np sql.NullInt64
+	if err := re.Scan(reg, []byte("http://h:81"), nil, nil, re.Default(&np, sql.NullInt64{})); err != nil || np != (sql.NullInt64{Int64: 81, Valid: true}) {
+		t.Errorf("Default into NullInt64 = %+v, %v; expected valid 81", np, err)
+	}
+	if err := re.Scan(reg, []byte("http://h"), nil, nil, re.Default(&np, sql.NullInt64{})); err != nil || np.Valid {
+		t.Errorf("Default into NullInt64 of absent port = %+v, %v; expected invalid", np, err)
+	}
+}
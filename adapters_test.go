@@ -0,0 +1,84 @@
+//go:build !re_minimal
+
+package re_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestJSON(t *testing.T) {
+	reg := regexp.MustCompile(`^\S+ event=(.*)$`)
+	var event struct {
+		User string `json:"user"`
+		Code int    `json:"code"`
+	}
+	if err := re.Scan(reg, []byte(`12:00 event={"user":"ann","code":7}`), re.JSON(&event)); err != nil {
+		t.Fatal(err)
+	}
+	if event.User != "ann" || event.Code != 7 {
+		t.Errorf("JSON = %+v; expected {User:ann Code:7}", event)
+	}
+	var list []int
+	if err := re.Scan(reg, []byte(`12:00 event=[1, 2]`), re.JSON(&list)); err != nil || len(list) != 2 {
+		t.Errorf("JSON = %v, %v; expected [1 2]", list, err)
+	}
+	if err := re.Scan(reg, []byte(`12:00 event={"user":`), re.JSON(&event)); err == nil {
+		t.Errorf("JSON of truncated object succeeded; expected error")
+	}
+}
+
+func TestUnquotedJSON(t *testing.T) {
+	reg := regexp.MustCompile(`^v=(.*)$`)
+	var s string
+	if err := re.Scan(reg, []byte(`v="é\/"`), re.Unquoted(&s)); err != nil || s != "é/" {
+		t.Errorf("Unquoted(v=\"é\\/\") = %q, %v; expected %q", s, err, "é/")
+	}
+}
+
+// levels implements flag.Value for comma-separated log levels.
+type levels []string
+
+func (l *levels) String() string { return strings.Join(*l, ",") }
+
+func (l *levels) Set(s string) error {
+	for _, v := range strings.Split(s, ",") {
+		if v != "info" && v != "warn" && v != "error" {
+			return fmt.Errorf("unknown level %q", v)
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
+
+func TestFlagValue(t *testing.T) {
+	reg := regexp.MustCompile(`^levels=(\S*)$`)
+	var l levels
+	if err := re.Scan(reg, []byte("levels=info,error"), &l); err != nil || l.String() != "info,error" {
+		t.Errorf("Scan = %v, %v; expected info,error", l, err)
+	}
+	if err := re.Scan(reg, []byte("levels=debug"), &l); err == nil {
+		t.Errorf("Scan(debug) = %v; expected error", l)
+	}
+}
+
+func TestJSONNumber(t *testing.T) {
+	all := regexp.MustCompile(`^(.*)$`)
+	for _, input := range []string{"0", "-12", "3.25", "1e400", "12345678901234567890", "6.02E+23"} {
+		var n json.Number
+		if err := re.Scan(all, []byte(input), &n); err != nil || string(n) != input {
+			t.Errorf("Scan(%q) = %q, %v", input, n, err)
+		}
+	}
+	for _, input := range []string{"", "+1", "0x1f", "01", "1.", ".5", "NaN"} {
+		n := json.Number("7")
+		if err := re.Scan(all, []byte(input), &n); err == nil || n != "7" {
+			t.Errorf("Scan(%q) = %q; expected error", input, n)
+		}
+	}
+}
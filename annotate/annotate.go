@@ -0,0 +1,204 @@
+/*
+Package annotate labels the regions of a text found by several regular
+expressions, resolving overlaps between them.  It is intended for
+text-labeling and entity-extraction preprocessing:
+
+	a, err := annotate.New(
+		annotate.Rule{Label: "date", Pattern: dateRE, Value: parseDate},
+		annotate.Rule{Label: "number", Pattern: numberRE, Value: parseNumber},
+	)
+	for _, x := range a.Annotate(text) {
+		fmt.Println(x.Span, x.Label, x.Value)
+	}
+
+Annotate returns non-overlapping annotations.  When the matches of
+different rules overlap, the Policy of the Annotator decides which one
+is kept; All returns every candidate instead.
+*/
+package annotate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/ghemawat/re"
+)
+
+// A Rule describes one kind of annotation.
+type Rule struct {
+	// Label identifies the rule in Annotations, e.g., "date".
+	Label string
+
+	// Pattern finds the annotated text.
+	Pattern *regexp.Regexp
+
+	// Group, if non-empty, names the group of Pattern whose sub-match
+	// is annotated; New fails if Pattern has no such group.  If empty,
+	// entire matches are annotated.  Matches in which the group does
+	// not participate are ignored.
+	Group string
+
+	// Value, if non-nil, is called with the annotated text and its result
+	// is stored in Annotation.Value.  Text for which Value returns an
+	// error is not annotated, which allows checks that regular
+	// expressions cannot express.
+	Value func(b []byte) (interface{}, error)
+}
+
+// An Annotation describes one labeled region of the input.
+type Annotation struct {
+	Label string      // Label of the Rule that found the region
+	Span  re.Span     // Extent of the region in the input
+	Value interface{} // Result of Rule.Value, or nil
+}
+
+// A Policy decides which of several overlapping candidates is kept.
+type Policy int
+
+const (
+	// ByRule keeps the candidate found by the rule listed first in New.
+	// Candidates found by the same rule are resolved as by Longest.
+	ByRule Policy = iota
+
+	// Longest keeps the longest candidate.  Ties are resolved in favor
+	// of the rule listed first in New, and then the leftmost candidate.
+	Longest
+
+	// Leftmost keeps the candidate that starts first.  Ties are
+	// resolved as by Longest.
+	Leftmost
+)
+
+// An Annotator labels the text found by a set of rules.
+type Annotator struct {
+	rules  []Rule
+	groups []int // Index of the annotated group of each rule
+
+	// Policy resolves overlapping candidates; the default is ByRule.
+	Policy Policy
+}
+
+// New returns an Annotator that applies the given rules.  It returns
+// an error if the Group of a rule names no group of its Pattern.
+func New(rules ...Rule) (*Annotator, error) {
+	a := &Annotator{rules: rules, groups: make([]int, len(rules))}
+	for i, rule := range rules {
+		g := groupIndex(rule)
+		if g < 0 {
+			return nil, fmt.Errorf("annotate: rule %q: no group named %q in %s", rule.Label, rule.Group, rule.Pattern)
+		}
+		a.groups[i] = g
+	}
+	return a, nil
+}
+
+// candidate is an Annotation along with the index of the rule that
+// produced it.
+type candidate struct {
+	Annotation
+	rule int
+}
+
+// All returns every candidate annotation of input, including ones that
+// overlap, in increasing order of position.  Candidates that start at
+// the same position are ordered by rule.
+func (a *Annotator) All(input []byte) []Annotation {
+	all := a.candidates(input)
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Span.Start != all[j].Span.Start {
+			return all[i].Span.Start < all[j].Span.Start
+		}
+		return all[i].rule < all[j].rule
+	})
+	return annotations(all)
+}
+
+// Annotate returns non-overlapping annotations of input, in increasing
+// order of position.  Overlaps are resolved according to a.Policy; a
+// candidate that is not kept does not exclude any others.  Empty
+// matches are never annotated.
+func (a *Annotator) Annotate(input []byte) []Annotation {
+	all := a.candidates(input)
+	sort.SliceStable(all, func(i, j int) bool {
+		x, y := all[i], all[j]
+		if a.Policy == ByRule && x.rule != y.rule {
+			return x.rule < y.rule
+		}
+		if a.Policy == Leftmost && x.Span.Start != y.Span.Start {
+			return x.Span.Start < y.Span.Start
+		}
+		if lx, ly := x.Span.End-x.Span.Start, y.Span.End-y.Span.Start; lx != ly {
+			return lx > ly
+		}
+		if x.rule != y.rule {
+			return x.rule < y.rule
+		}
+		return x.Span.Start < y.Span.Start
+	})
+
+	// Keep candidates in order of preference unless they overlap one
+	// that was already kept.  kept is ordered by position.
+	var kept []candidate
+	for _, c := range all {
+		i := sort.Search(len(kept), func(i int) bool {
+			return kept[i].Span.End > c.Span.Start
+		})
+		if i < len(kept) && kept[i].Span.Start < c.Span.End {
+			continue // Overlaps kept[i]
+		}
+		kept = append(kept, candidate{})
+		copy(kept[i+1:], kept[i:])
+		kept[i] = c
+	}
+	return annotations(kept)
+}
+
+// candidates returns the non-empty annotations found by each rule, in
+// rule order.
+func (a *Annotator) candidates(input []byte) []candidate {
+	var all []candidate
+	for i, rule := range a.rules {
+		g := a.groups[i]
+		for _, m := range rule.Pattern.FindAllSubmatchIndex(input, -1) {
+			s := re.Span{Start: m[2*g], End: m[2*g+1]}
+			if s.Start < 0 || s.Start == s.End {
+				continue
+			}
+			var v interface{}
+			if rule.Value != nil {
+				var err error
+				if v, err = rule.Value(input[s.Start:s.End]); err != nil {
+					continue
+				}
+			}
+			all = append(all, candidate{Annotation{rule.Label, s, v}, i})
+		}
+	}
+	return all
+}
+
+func annotations(cs []candidate) []Annotation {
+	if len(cs) == 0 {
+		return nil
+	}
+	result := make([]Annotation, len(cs))
+	for i, c := range cs {
+		result[i] = c.Annotation
+	}
+	return result
+}
+
+// groupIndex returns the index (into the result of FindSubmatchIndex)
+// of the group annotated by rule, or -1 if there is no such group.
+func groupIndex(rule Rule) int {
+	if rule.Group == "" {
+		return 0
+	}
+	for i, name := range rule.Pattern.SubexpNames() {
+		if i > 0 && name == rule.Group {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,126 @@
+package annotate_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re/annotate"
+)
+
+// format renders annotations of input compactly for comparison.
+func format(input string, as []annotate.Annotation) string {
+	var parts []string
+	for _, a := range as {
+		s := a.Label + ":" + input[a.Span.Start:a.Span.End]
+		if a.Value != nil {
+			s += fmt.Sprintf("=%v", a.Value)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
+
+var (
+	number = annotate.Rule{
+		Label:   "number",
+		Pattern: regexp.MustCompile(`\d+`),
+		Value: func(b []byte) (interface{}, error) {
+			return strconv.ParseUint(string(b), 10, 8)
+		},
+	}
+	date = annotate.Rule{
+		Label:   "date",
+		Pattern: regexp.MustCompile(`\d{4}-\d\d-\d\d`),
+	}
+	word = annotate.Rule{
+		Label:   "word",
+		Pattern: regexp.MustCompile(`[a-z]+(?:-[a-z0-9]+)*`),
+	}
+)
+
+func TestAnnotate(t *testing.T) {
+	const input = "on 2024-01-15 buy 12 items-99x"
+	for _, c := range []struct {
+		policy annotate.Policy
+		rules  []annotate.Rule
+		want   string
+	}{
+		// 2024 is out of range for number, so is not a candidate.
+		{annotate.ByRule, []annotate.Rule{number, date}, "number:01=1 number:15=15 number:12=12 number:99=99"},
+		{annotate.ByRule, []annotate.Rule{date, number, word}, "word:on date:2024-01-15 word:buy number:12=12 number:99=99"},
+		{annotate.Longest, []annotate.Rule{number, date, word}, "word:on date:2024-01-15 word:buy number:12=12 word:items-99x"},
+		{annotate.Longest, []annotate.Rule{number, word}, "word:on number:01=1 number:15=15 word:buy number:12=12 word:items-99x"},
+		{annotate.Leftmost, []annotate.Rule{number, word}, "word:on number:01=1 number:15=15 word:buy number:12=12 word:items-99x"},
+	} {
+		a, err := annotate.New(c.rules...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.Policy = c.policy
+		if got := format(input, a.Annotate([]byte(input))); got != c.want {
+			t.Errorf("policy %d: got %q, want %q", c.policy, got, c.want)
+		}
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	// "abcdef" is covered by a short early match, a long later match,
+	// and a match from a later rule.
+	const input = "abcdef"
+	short := annotate.Rule{Label: "short", Pattern: regexp.MustCompile(`ab`)}
+	long := annotate.Rule{Label: "long", Pattern: regexp.MustCompile(`bcdef`)}
+	tail := annotate.Rule{Label: "tail", Pattern: regexp.MustCompile(`ef`)}
+	for _, c := range []struct {
+		policy annotate.Policy
+		want   string
+	}{
+		{annotate.ByRule, "short:ab tail:ef"},
+		{annotate.Longest, "long:bcdef"},
+		{annotate.Leftmost, "short:ab tail:ef"},
+	} {
+		a, err := annotate.New(short, tail, long)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.Policy = c.policy
+		if got := format(input, a.Annotate([]byte(input))); got != c.want {
+			t.Errorf("policy %d: got %q, want %q", c.policy, got, c.want)
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	const input = "x 2024-01-15"
+	a, err := annotate.New(number, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "date:2024-01-15 number:01=1 number:15=15"
+	if got := format(input, a.All([]byte(input))); got != want {
+		t.Errorf("All got %q, want %q", got, want)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	const input = "id=42 name=bob id=7"
+	a, err := annotate.New(
+		annotate.Rule{Label: "id", Pattern: regexp.MustCompile(`id=(?P<v>\d+)`), Group: "v"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := format(input, a.Annotate([]byte(input))), "id:42 id:7"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// A group that does not exist is reported rather than ignored.
+	_, err = annotate.New(
+		annotate.Rule{Label: "missing", Pattern: regexp.MustCompile(`name=(\w+)`), Group: "nosuch"},
+	)
+	if err == nil || !strings.Contains(err.Error(), "nosuch") {
+		t.Errorf("New with unknown group: error %v", err)
+	}
+}
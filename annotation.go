@@ -0,0 +1,103 @@
+package re
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// GroupKey returns the name of a named group with its type annotation
+// (see GroupValue), if any, removed; e.g., "port" for (?P<port_int>\d+).
+func GroupKey(name string) string {
+	key, _ := parseGroupName(name)
+	return key
+}
+
+// GroupValue parses b, a sub-match of the group with the given name,
+// into a value of the type selected by the type annotation at the end
+// of the name, following the rules Scan uses for a pointer to that type.
+// Schema-less APIs such as ScanTyped use these annotations to produce
+// typed values instead of strings.  The supported annotations, and the
+// resulting value types, are:
+//
+//	name_int              int64
+//	name_uint             uint64
+//	name_float            float64
+//	name_bool             bool
+//	name_duration         time.Duration
+//	name_time_<layout>    time.Time
+//
+// where <layout> is the lower-cased name of one of the layout constants
+// in package time (e.g., rfc3339, rfc1123z, stamp, kitchen), or one of
+// datetime ("2006-01-02 15:04:05"), dateonly ("2006-01-02") and
+// timeonly ("15:04:05").  If name has no annotation, b is returned as a
+// string.  See GroupKey for the key under which such values are
+// reported.
+func GroupValue(name string, b []byte) (interface{}, error) {
+	_, parse := parseGroupName(name)
+	return parse(b, wrappedSpan(b))
+}
+
+// groupTypes maps a type annotation to the type of the value it produces.
+var groupTypes = map[string]reflect.Type{
+	"int":      reflect.TypeOf(int64(0)),
+	"uint":     reflect.TypeOf(uint64(0)),
+	"float":    reflect.TypeOf(float64(0)),
+	"bool":     reflect.TypeOf(false),
+	"duration": reflect.TypeOf(time.Duration(0)),
+}
+
+// timeLayouts maps the name used in a time annotation to a layout.
+var timeLayouts = map[string]string{
+	"ansic":       time.ANSIC,
+	"unixdate":    time.UnixDate,
+	"rubydate":    time.RubyDate,
+	"rfc822":      time.RFC822,
+	"rfc822z":     time.RFC822Z,
+	"rfc850":      time.RFC850,
+	"rfc1123":     time.RFC1123,
+	"rfc1123z":    time.RFC1123Z,
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"kitchen":     time.Kitchen,
+	"stamp":       time.Stamp,
+	"stampmilli":  time.StampMilli,
+	"stampmicro":  time.StampMicro,
+	"stampnano":   time.StampNano,
+	"datetime":    "2006-01-02 15:04:05",
+	"dateonly":    "2006-01-02",
+	"timeonly":    "15:04:05",
+}
+
+// A valueParser converts a sub-match into a value of some type.
+type valueParser func(b []byte, s Span) (interface{}, error)
+
+// parseGroupName splits an annotated group name into the key under
+// which its value is reported and the parser for its sub-matches.
+func parseGroupName(name string) (string, valueParser) {
+	if i := strings.LastIndex(name, "_time_"); i > 0 {
+		if layout, ok := timeLayouts[name[i+len("_time_"):]]; ok {
+			return name[:i], func(b []byte, _ Span) (interface{}, error) {
+				return time.Parse(layout, string(b))
+			}
+		}
+	}
+	if i := strings.LastIndex(name, "_"); i > 0 {
+		if t, ok := groupTypes[name[i+1:]]; ok {
+			return name[:i], parserFor(t)
+		}
+	}
+	return name, parserFor(reflect.TypeOf(""))
+}
+
+// parserFor returns a valueParser that parses sub-matches following the
+// rules Scan uses for a pointer to t.
+func parserFor(t reflect.Type) valueParser {
+	return func(b []byte, s Span) (interface{}, error) {
+		p := reflect.New(t)
+		if err := assign(p.Interface(), b, s); err != nil {
+			return nil, err
+		}
+		return p.Elem().Interface(), nil
+	}
+}
@@ -0,0 +1,41 @@
+package re_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+func TestGroupAnnotations(t *testing.T) {
+	for _, c := range []struct {
+		name, input string
+		key         string
+		want        interface{}
+	}{
+		{"port_int", "0x10", "port", int64(16)},
+		{"size_uint", "7", "size", uint64(7)},
+		{"load_float", "0.5", "load", 0.5},
+		{"ok_bool", "true", "ok", true},
+		{"took_duration", "1m", "took", time.Minute},
+		{"ts_time_rfc3339", "2020-01-02T03:04:05Z", "ts", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"day_time_dateonly", "2020-01-02", "day", time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"user_id", "u1", "user_id", "u1"},
+		{"at_time_bogus", "x", "at_time_bogus", "x"},
+		{"int", "5", "int", "5"},
+	} {
+		if key := re.GroupKey(c.name); key != c.key {
+			t.Errorf("GroupKey(%q) = %q; expected %q", c.name, key, c.key)
+		}
+		got, err := re.GroupValue(c.name, []byte(c.input))
+		if err != nil || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("GroupValue(%q, %q) = %#v, %v; expected %#v", c.name, c.input, got, err, c.want)
+		}
+	}
+	for _, c := range [][2]string{{"n_int", "x"}, {"ok_bool", "maybe"}, {"ts_time_kitchen", "25:00"}} {
+		if v, err := re.GroupValue(c[0], []byte(c[1])); err == nil {
+			t.Errorf("GroupValue(%q, %q) = %v; expected error", c[0], c[1], v)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package re
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// attrPattern matches one attribute of a start tag: a name, optionally
+// followed by a double-quoted, single-quoted, or unquoted value.
+var attrPattern = regexp.MustCompile(`([^\s"'>/=]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+)))?`)
+
+// Attr returns an output argument for Scan that expects the sub-match to
+// be a start tag such as <a href="/x" class='y'>, looks up the attribute
+// with the given name (ignoring case), decodes HTML entities such as
+// &amp; in its value, and stores the result into output following the
+// usual rules of Scan; e.g.
+//
+//	var href string
+//	err := re.Scan(regexp.MustCompile(`(<a\s[^>]*>)`), page, re.Attr("href", &href))
+//
+// Double-quoted, single-quoted, and unquoted values are supported; an
+// attribute without a value (e.g., "disabled") has an empty value.  It
+// is an error if the tag has no such attribute.
+//
+// Attr is a convenience for quick scraping of tag-like text.  It is not
+// an HTML or XML parser: it does not know about comments, CDATA,
+// namespaces, or the many ways real-world markup deviates from the
+// happy path.  Use golang.org/x/net/html or encoding/xml for those.
+func Attr(name string, output interface{}) func([]byte) error {
+	return func(b []byte) error {
+		value, ok := attr(b, name)
+		if !ok {
+			return fmt.Errorf(`re.Attr: no attribute "%s" in "%s"`, name, b)
+		}
+		v := []byte(html.UnescapeString(value))
+		// The attribute is present, so the span must not mark it absent.
+		return assign(output, v, Span{Start: 0, End: len(v)})
+	}
+}
+
+// attr returns the raw value of the named attribute of start tag b.
+func attr(b []byte, name string) (string, bool) {
+	s := strings.TrimPrefix(strings.TrimSpace(string(b)), "<")
+	// Skip the tag name.
+	if i := strings.IndexAny(s, " \t\n\r\f>/"); i >= 0 {
+		s = s[i:]
+	} else {
+		s = ""
+	}
+	for _, m := range attrPattern.FindAllStringSubmatch(s, -1) {
+		if !strings.EqualFold(m[1], name) {
+			continue
+		}
+		return m[2] + m[3] + m[4], true
+	}
+	return "", false
+}
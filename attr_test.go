@@ -0,0 +1,55 @@
+package re_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestAttr(t *testing.T) {
+	tag := regexp.MustCompile(`(<[^>]*>)`)
+	for _, c := range []struct {
+		input  string
+		name   string
+		result bool
+		want   string
+	}{
+		{`<a href="/x?a=1&amp;b=2">`, "href", true, "/x?a=1&b=2"},
+		{`<a class='c' HREF='/y'>`, "href", true, "/y"},
+		{`<a href=/z class=c>`, "href", true, "/z"},
+		{`<input disabled name="n">`, "disabled", true, ""},
+		{`<a title="x href=/bad" href="/good">`, "href", true, "/good"},
+		{`<a title="x href=/bad">`, "href", false, ""},
+		{`<img src = "p.png" />`, "src", true, "p.png"},
+		{`<href>`, "href", false, ""},
+	} {
+		var got string
+		err := re.Scan(tag, []byte(c.input), re.Attr(c.name, &got))
+		if !c.result {
+			if err == nil {
+				t.Errorf("Attr(%q, %q) succeeded unexpectedly", c.input, c.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Attr(%q, %q): unexpected error: %s", c.input, c.name, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("Attr(%q, %q) = %q; expected %q", c.input, c.name, got, c.want)
+		}
+	}
+
+	// Typed values.
+	var width int
+	if err := re.Scan(tag, []byte(`<img width="640">`), re.Attr("width", &width)); err != nil || width != 640 {
+		t.Errorf("Attr(width) = %d, %v; expected 640", width, err)
+	}
+
+	// Outputs that distinguish absent values see the attribute as present.
+	var p *string
+	if err := re.Scan(tag, []byte(`<a href="/x">`), re.Attr("href", &p)); err != nil || p == nil || *p != "/x" {
+		t.Errorf("Attr(href) into **string = %v, %v; expected /x", p, err)
+	}
+}
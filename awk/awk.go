@@ -0,0 +1,148 @@
+/*
+Package awk applies rules, each pairing a regular expression with an
+action, to the lines of a text in the manner of the awk tool.  Sub-matches
+are stored into outputs bound to each rule, as by re.Bind, and actions
+share state through the variables they close over:
+
+	var p awk.Program
+	var status int
+	var size, total int64
+	counts := map[int]int{}
+	p.Rule(regexp.MustCompile(`" (\d{3}) (\d+)$`), func(*awk.Record) error {
+		counts[status]++
+		total += size
+		return nil
+	}, &status, &size)
+	p.End = func() error {
+		fmt.Println(counts, total)
+		return nil
+	}
+	err := p.Run(os.Stdin)
+*/
+package awk
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/ghemawat/re"
+)
+
+var (
+	// Next, when returned by an action, skips the remaining rules for the
+	// current line, like awk's next statement.
+	Next = errors.New("awk: next")
+
+	// Exit, when returned by an action, stops processing input; End is
+	// still called, like awk's exit statement.
+	Exit = errors.New("awk: exit")
+)
+
+// A Record describes the line being processed.
+type Record struct {
+	NR   int    // Line number, counting from one
+	Line []byte // Text of the line without its terminator; valid only during the action
+}
+
+// A Program is a list of rules along with optional actions to run before
+// and after the input.  The zero value is an empty Program.
+type Program struct {
+	rules []rule
+
+	// Begin, if non-nil, is called before the first line is read.
+	Begin func() error
+
+	// End, if non-nil, is called after the last line has been processed,
+	// or after an action returns Exit.
+	End func() error
+}
+
+type rule struct {
+	scanner *re.Scanner // nil matches every line
+	action  func(*Record) error
+}
+
+// Rule adds a rule that runs action on every line matched by pattern,
+// after storing the sub-matches into output as re.Scan does.  A nil
+// pattern matches every line.  Rules run in the order they were added.
+// An error is returned if action is nil or if the outputs cannot be
+// bound to pattern; see re.Bind.
+func (p *Program) Rule(pattern *regexp.Regexp, action func(*Record) error, output ...interface{}) error {
+	if action == nil {
+		return errors.New("awk: nil action")
+	}
+	var s *re.Scanner
+	if pattern != nil {
+		var err error
+		if s, err = re.Bind(pattern, output...); err != nil {
+			return err
+		}
+	} else if len(output) > 0 {
+		return errors.New("awk: outputs given for rule without a pattern")
+	}
+	p.rules = append(p.rules, rule{s, action})
+	return nil
+}
+
+// Run calls Begin, applies the rules to each line read from r, and then
+// calls End.  Lines are terminated by "\n", optionally preceded by "\r".
+// Run stops at the first error from an action (other than one wrapping
+// Next or Exit), from r, or from parsing a sub-match; such errors are
+// reported along with the line number, and End is not called.
+func (p *Program) Run(r io.Reader) error {
+	if p.Begin != nil {
+		if err := p.Begin(); err != nil {
+			return err
+		}
+	}
+	br := bufio.NewReader(r)
+	rec := &Record{}
+	exited := false
+	for !exited {
+		line, err := br.ReadBytes('\n')
+		if len(line) == 0 && err == io.EOF {
+			break
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		rec.NR++
+		rec.Line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+		if exited, err = p.apply(rec); err != nil {
+			return fmt.Errorf("awk: line %d: %w", rec.NR, err)
+		}
+	}
+	if p.End != nil {
+		return p.End()
+	}
+	return nil
+}
+
+// apply runs the rules on rec, reporting whether an action returned Exit.
+func (p *Program) apply(rec *Record) (bool, error) {
+	for _, r := range p.rules {
+		if r.scanner != nil {
+			err := r.scanner.Scan(rec.Line)
+			if errors.Is(err, re.NotFound) {
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+		}
+		switch err := r.action(rec); {
+		case err == nil:
+		case errors.Is(err, Next):
+			return false, nil
+		case errors.Is(err, Exit):
+			return true, nil
+		default:
+			return false, err
+		}
+	}
+	return false, nil
+}
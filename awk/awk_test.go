@@ -0,0 +1,147 @@
+package awk_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re/awk"
+)
+
+func TestRun(t *testing.T) {
+	input := "# header\r\nGET /a 200 10\nGET /b 404 0\nPOST /a 200 5\n# skip me\nGET /c 500 7"
+	var log []string
+	var p awk.Program
+	var method, path string
+	var status int
+	var size, total int64
+	must := func(err error) {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	p.Begin = func() error {
+		log = append(log, "begin")
+		return nil
+	}
+	must(p.Rule(regexp.MustCompile(`^#`), func(r *awk.Record) error {
+		log = append(log, fmt.Sprintf("comment %d %q", r.NR, r.Line))
+		return awk.Next
+	}))
+	must(p.Rule(regexp.MustCompile(`^(\w+) (\S+) (\d+) (\d+)$`), func(*awk.Record) error {
+		total += size
+		return nil
+	}, &method, &path, &status, &size))
+	must(p.Rule(regexp.MustCompile(` 404 `), func(r *awk.Record) error {
+		log = append(log, fmt.Sprintf("not found %s at %d", path, r.NR))
+		return nil
+	}))
+	must(p.Rule(nil, func(r *awk.Record) error {
+		log = append(log, fmt.Sprintf("line %d", r.NR))
+		return nil
+	}))
+	p.End = func() error {
+		log = append(log, fmt.Sprintf("end %d", total))
+		return nil
+	}
+	if err := p.Run(strings.NewReader(input)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{
+		"begin",
+		`comment 1 "# header"`,
+		"line 2",
+		"not found /b at 3",
+		"line 3",
+		"line 4",
+		`comment 5 "# skip me"`,
+		"line 6",
+		"end 22",
+	}
+	if strings.Join(log, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nexpected:\n%s", strings.Join(log, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestExit(t *testing.T) {
+	var p awk.Program
+	var seen []int
+	p.Rule(nil, func(r *awk.Record) error {
+		seen = append(seen, r.NR)
+		if r.NR == 2 {
+			return awk.Exit
+		}
+		return nil
+	})
+	ended := false
+	p.End = func() error {
+		ended = true
+		return nil
+	}
+	if err := p.Run(strings.NewReader("a\nb\nc\n")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fmt.Sprint(seen) != "[1 2]" || !ended {
+		t.Errorf("saw lines %v, ended=%v; expected [1 2], true", seen, ended)
+	}
+
+	// Wrapped sentinels are recognized too.
+	var q awk.Program
+	seen = nil
+	q.Rule(nil, func(r *awk.Record) error {
+		if r.NR == 1 {
+			return fmt.Errorf("skip header: %w", awk.Next)
+		}
+		return nil
+	})
+	q.Rule(nil, func(r *awk.Record) error {
+		seen = append(seen, r.NR)
+		if r.NR == 2 {
+			return fmt.Errorf("done: %w", awk.Exit)
+		}
+		return nil
+	})
+	if err := q.Run(strings.NewReader("a\nb\nc\n")); err != nil {
+		t.Fatalf("unexpected error with wrapped sentinels: %s", err)
+	}
+	if fmt.Sprint(seen) != "[2]" {
+		t.Errorf("saw lines %v with wrapped sentinels; expected [2]", seen)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var p awk.Program
+	var n uint8
+	p.Rule(regexp.MustCompile(`^(\d+)$`), func(*awk.Record) error { return nil }, &n)
+	p.End = func() error {
+		t.Errorf("End called after an error")
+		return nil
+	}
+	err := p.Run(strings.NewReader("1\n2\n300\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Run error was %v; expected a line 3 error", err)
+	}
+
+	failure := errors.New("failed")
+	var q awk.Program
+	q.Rule(nil, func(*awk.Record) error { return failure })
+	if err := q.Run(strings.NewReader("x\n")); !errors.Is(err, failure) {
+		t.Errorf("Run error was %v; expected one wrapping %v", err, failure)
+	}
+	q.Begin = func() error { return failure }
+	if err := q.Run(strings.NewReader("")); err != failure {
+		t.Errorf("Run error was %v; expected Begin's error", err)
+	}
+
+	if err := q.Rule(nil, func(*awk.Record) error { return nil }, &n); err == nil {
+		t.Errorf("Rule with outputs but no pattern succeeded unexpectedly")
+	}
+	if err := q.Rule(regexp.MustCompile(`x`), func(*awk.Record) error { return nil }, &n); err == nil {
+		t.Errorf("Rule with too many outputs succeeded unexpectedly")
+	}
+	if err := q.Rule(regexp.MustCompile(`x`), nil); err == nil {
+		t.Errorf("Rule with nil action succeeded unexpectedly")
+	}
+}
@@ -0,0 +1,240 @@
+package re
+
+import (
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// Cache remembers the result of scanning recently seen inputs with a
+// regular expression.  Scanning an input identical to one seen recently
+// (e.g., a duplicated log line) reuses the remembered match instead of
+// running the regular expression again.
+//
+// The parsed values of outputs that point to plain data (numbers,
+// strings, booleans, Spans, and arrays and structs made only of such
+// fields) are remembered as well, and copied into the outputs of a later
+// call with the same input and the same output types instead of being
+// parsed again; in that case parse functions such as UnmarshalText
+// methods are not called.  Functions of type func([]byte) error are
+// called, and *[]byte outputs are filled in, on every call, so that the
+// latter alias the latest input.  Other outputs, including those
+// wrapped by Named or Group, are parsed on every call, exactly as Scan
+// would parse them.
+//
+// A Cache holds a bounded number of inputs; once full, the oldest
+// input is forgotten to make room for a new one.  A Cache is safe for
+// concurrent use by multiple goroutines.
+type Cache struct {
+	re *regexp.Regexp
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry // Keyed by input
+	order   []string               // Cached inputs in insertion order (circular)
+	next    int                    // Index of the oldest entry in order once full
+}
+
+// A cacheEntry holds what a Cache remembers about one input.  It is
+// only read or written while holding Cache.mu; readers take a copy.
+type cacheEntry struct {
+	matches []int
+	types   []reflect.Type // Output types of the remembered values
+	values  []interface{}  // Parsed value of each output, or nil
+}
+
+// NewCache returns a Cache that remembers the result of scanning the
+// last size distinct inputs with re.  A size less than one is treated as
+// one.
+func NewCache(re *regexp.Regexp, size int) *Cache {
+	if size < 1 {
+		size = 1
+	}
+	return &Cache{
+		re:      re,
+		entries: make(map[string]*cacheEntry, size),
+		order:   make([]string, 0, size),
+	}
+}
+
+// Scan is like re.Scan(c's regular expression, input, output...), but
+// consults the cache before matching and parsing.
+func (c *Cache) Scan(input []byte, output ...interface{}) error {
+	e := c.entry(input)
+	if e.values != nil && sameTypes(e.types, output) {
+		return reuseValues(input, e, output)
+	}
+	if err := scanMatch(c.re, input, e.matches, output); err != nil {
+		return err
+	}
+	if types, ok := memoizable(output); ok {
+		values := make([]interface{}, len(output))
+		for i, r := range output {
+			if isPlainPointer(r) {
+				values[i] = reflect.ValueOf(r).Elem().Interface()
+			}
+		}
+		c.mu.Lock()
+		if cur, ok := c.entries[string(input)]; ok {
+			*cur = cacheEntry{matches: e.matches, types: types, values: values}
+		}
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// entry returns a copy of the cache entry for input, matching input and
+// adding an entry if there is none.
+func (c *Cache) entry(input []byte) cacheEntry {
+	c.mu.Lock()
+	e, ok := c.entries[string(input)]
+	if ok {
+		result := *e
+		c.mu.Unlock()
+		return result
+	}
+	c.mu.Unlock()
+
+	matches := c.re.FindSubmatchIndex(input)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := string(input)
+	if e, ok := c.entries[key]; ok {
+		// Another goroutine added it in the meantime.
+		return *e
+	}
+	if len(c.order) < cap(c.order) {
+		c.order = append(c.order, key)
+	} else {
+		delete(c.entries, c.order[c.next])
+		c.order[c.next] = key
+		c.next = (c.next + 1) % len(c.order)
+	}
+	c.entries[key] = &cacheEntry{matches: matches}
+	return cacheEntry{matches: matches}
+}
+
+// reuseValues fills in output from the values remembered in e.
+func reuseValues(input []byte, e cacheEntry, output []interface{}) error {
+	for i, r := range output {
+		switch r.(type) {
+		case nil:
+		case func([]byte) error, *[]byte:
+			submatch, span := submatchAt(input, e.matches, i)
+			if err := assign(r, submatch, span); err != nil {
+				return err
+			}
+		default:
+			reflect.ValueOf(r).Elem().Set(reflect.ValueOf(e.values[i]))
+		}
+	}
+	return nil
+}
+
+// memoizable returns the types of output if the parsed values of output
+// can be remembered by a Cache: every output must be nil, a function, a
+// *[]byte, or a pointer to plain data.
+func memoizable(output []interface{}) ([]reflect.Type, bool) {
+	types := make([]reflect.Type, len(output))
+	for i, r := range output {
+		switch r.(type) {
+		case nil, func([]byte) error, *[]byte:
+		default:
+			if !isPlainPointer(r) {
+				return nil, false
+			}
+		}
+		types[i] = reflect.TypeOf(r)
+	}
+	return types, true
+}
+
+// sameTypes reports whether output has the given types.
+func sameTypes(types []reflect.Type, output []interface{}) bool {
+	if len(types) != len(output) {
+		return false
+	}
+	for i, r := range output {
+		if reflect.TypeOf(r) != types[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isPlainPointer reports whether r is a non-nil pointer to plain data,
+// which can be copied without sharing memory with the copy.
+func isPlainPointer(r interface{}) bool {
+	v := reflect.ValueOf(r)
+	return v.Kind() == reflect.Ptr && !v.IsNil() && isPlain(v.Type().Elem())
+}
+
+// plainTypes caches the results of isPlain.
+var plainTypes sync.Map
+
+// isPlain reports whether values of type t hold no pointers, slices,
+// maps, channels, functions or interfaces.
+func isPlain(t reflect.Type) bool {
+	if p, ok := plainTypes.Load(t); ok {
+		return p.(bool)
+	}
+	p := false
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		p = true
+	case reflect.Array:
+		p = isPlain(t.Elem())
+	case reflect.Struct:
+		p = true
+		for i := 0; i < t.NumField(); i++ {
+			p = p && isPlain(t.Field(i).Type)
+		}
+	}
+	plainTypes.Store(t, p)
+	return p
+}
+
+// A fifoMap is a map of bounded size that forgets its oldest entry to
+// make room for a new one.  It is safe for concurrent use.  It holds
+// derived data, such as compiled regular expressions, that package-level
+// caches would otherwise keep forever.
+type fifoMap struct {
+	size int
+
+	mu    sync.Mutex
+	m     map[interface{}]interface{}
+	order []interface{} // Keys in insertion order (circular)
+	next  int           // Index of the oldest key in order once full
+}
+
+// load returns the value stored for key, if any.
+func (f *fifoMap) load(key interface{}) (interface{}, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.m[key]
+	return v, ok
+}
+
+// store stores value for key, forgetting the oldest entry if f is full.
+func (f *fifoMap) store(key, value interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.m == nil {
+		f.m = make(map[interface{}]interface{}, f.size)
+	}
+	if _, ok := f.m[key]; ok {
+		f.m[key] = value
+		return
+	}
+	if len(f.order) < f.size {
+		f.order = append(f.order, key)
+	} else {
+		delete(f.m, f.order[f.next])
+		f.order[f.next] = key
+		f.next = (f.next + 1) % len(f.order)
+	}
+	f.m[key] = value
+}
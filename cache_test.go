@@ -0,0 +1,92 @@
+package re_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestCache(t *testing.T) {
+	c := re.NewCache(regexp.MustCompile(`^(\w+):(\d+)$`), 2)
+	for _, input := range []string{"a:1", "b:2", "a:1", "c:3", "a:1", "c:3"} {
+		var host string
+		var port int
+		if err := c.Scan([]byte(input), &host, &port); err != nil {
+			t.Fatalf("Scan(%q): unexpected error: %s", input, err)
+		}
+		if got := fmt.Sprintf("%s:%d", host, port); got != input {
+			t.Errorf("Scan(%q) extracted %q", input, got)
+		}
+	}
+
+	// Failed matches are cached too.
+	for i := 0; i < 2; i++ {
+		if err := c.Scan([]byte("bad"), nil); !errors.Is(err, re.NotFound) {
+			t.Errorf("Scan(bad) error was %v, want an error that wraps %v", err, re.NotFound)
+		}
+	}
+
+	// Extracted byte slices alias the latest input, not the cached one.
+	b := []byte("a:1")
+	var m []byte
+	if err := c.Scan(b, &m); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	b[0] = 'j'
+	if string(m) != "j" {
+		t.Errorf("extracted byte slice does not alias input")
+	}
+}
+
+// celsius counts the calls of its UnmarshalText method.
+type celsius float64
+
+var celsiusParses int
+
+func (c *celsius) UnmarshalText(b []byte) error {
+	celsiusParses++
+	f, err := strconv.ParseFloat(string(b), 64)
+	*c = celsius(f)
+	return err
+}
+
+func TestCacheValues(t *testing.T) {
+	c := re.NewCache(regexp.MustCompile(`^(\w+)=(\S+)$`), 4)
+	celsiusParses = 0
+	for i := 0; i < 3; i++ {
+		var name []byte
+		var temp celsius
+		input := []byte("kitchen=21.5")
+		if err := c.Scan(input, &name, &temp); err != nil {
+			t.Fatal(err)
+		}
+		if string(name) != "kitchen" || temp != 21.5 {
+			t.Errorf("Scan = %q, %v; expected kitchen, 21.5", name, temp)
+		}
+		input[0] = 'K'
+		if string(name) != "Kitchen" {
+			t.Errorf("extracted byte slice does not alias the latest input")
+		}
+	}
+	if celsiusParses != 1 {
+		t.Errorf("UnmarshalText called %d times; expected cache hits to skip it", celsiusParses)
+	}
+
+	// Different output types are parsed again.
+	var s string
+	if err := c.Scan([]byte("kitchen=21.5"), nil, &s); err != nil || s != "21.5" {
+		t.Errorf("Scan into string = %q, %v; expected 21.5", s, err)
+	}
+
+	// Parse errors are not remembered.
+	var n int
+	for i := 0; i < 2; i++ {
+		if err := c.Scan([]byte("hall=x"), nil, &n); err == nil {
+			t.Errorf("Scan(hall=x) into int succeeded unexpectedly")
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:build go1.15
+
+package re
+
+import "strconv"
+
+// complexAssigner returns the assigner for r if r points to a complex
+// type.
+func complexAssigner(r interface{}) (assigner, bool) {
+	switch v := r.(type) {
+	case *complex64:
+		return func(b []byte, _ Span) error {
+			c, err := strconv.ParseComplex(string(b), 64)
+			if err != nil {
+				return err
+			}
+			*v = complex64(c)
+			return nil
+		}, true
+	case *complex128:
+		return func(b []byte, _ Span) error {
+			c, err := strconv.ParseComplex(string(b), 128)
+			if err != nil {
+				return err
+			}
+			*v = c
+			return nil
+		}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,40 @@
+//go:build go1.15
+
+package re_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestComplex(t *testing.T) {
+	all := regexp.MustCompile(`^(.*)$`)
+	for _, c := range []struct {
+		input  string
+		result bool
+		want   complex128
+	}{
+		{"1.5+2i", true, 1.5 + 2i},
+		{"(3-4i)", true, 3 - 4i},
+		{"2i", true, 2i},
+		{"-7", true, -7},
+		{"1+", false, 0},
+		{"", false, 0},
+	} {
+		var got complex128
+		var got64 complex64
+		err := re.Scan(all, []byte(c.input), &got)
+		err64 := re.Scan(all, []byte(c.input), &got64)
+		if !c.result {
+			if err == nil || err64 == nil {
+				t.Errorf("Scan(%q) into complex succeeded unexpectedly", c.input)
+			}
+			continue
+		}
+		if err != nil || err64 != nil || got != c.want || got64 != complex64(c.want) {
+			t.Errorf("Scan(%q) = %v, %v, %v, %v; expected %v", c.input, got, err, got64, err64, c.want)
+		}
+	}
+}
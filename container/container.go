@@ -0,0 +1,112 @@
+/*
+Package container parses the framing that container runtimes add to the
+log lines of containers, so that a pattern can be applied to the payload
+written by the container:
+
+	l, err := container.Parse(line)
+	if err != nil {
+		return err
+	}
+	var status int
+	err = l.Scan(regexp.MustCompile(`status=(\d+)`), &status)
+
+Two formats are supported: the JSON-file format written by Docker, e.g.,
+
+	{"log":"hello\n","stream":"stdout","time":"2024-01-15T10:00:00.000000001Z"}
+
+and the format written by CRI runtimes such as containerd and CRI-O
+(found in /var/log/pods), e.g.,
+
+	2024-01-15T10:00:00.000000001Z stdout F hello
+*/
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+// A Line is one parsed container log line.
+type Line struct {
+	Time    time.Time
+	Stream  string // "stdout" or "stderr"
+	Partial bool   // Payload continues in the next line of the stream
+	Payload []byte // Text written by the container, without a trailing newline
+}
+
+// CRIPattern matches a CRI log line, capturing the timestamp, stream,
+// tags and payload.  The first tag is "P" for a partial line and "F" for
+// a full one.
+var CRIPattern = regexp.MustCompile(`(?s)^(\S+) (stdout|stderr) ([^\s]+) ?(.*)$`)
+
+// Parse parses a line in either the Docker JSON-file format or the CRI
+// format, choosing the format by whether the line starts with "{".
+func Parse(b []byte) (Line, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		return ParseDocker(b)
+	}
+	return ParseCRI(b)
+}
+
+// ParseDocker parses a line in the Docker JSON-file format.  Docker
+// splits long lines into pieces; all but the last piece lack the
+// trailing newline, and are marked Partial.
+func ParseDocker(b []byte) (Line, error) {
+	var v struct {
+		Log    string    `json:"log"`
+		Stream string    `json:"stream"`
+		Time   time.Time `json:"time"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return Line{}, fmt.Errorf("container: bad docker log line: %w", err)
+	}
+	l := Line{Time: v.Time, Stream: v.Stream, Payload: []byte(v.Log)}
+	if bytes.HasSuffix(l.Payload, []byte("\n")) {
+		l.Payload = l.Payload[:len(l.Payload)-1]
+	} else {
+		l.Partial = true
+	}
+	return l, nil
+}
+
+// ParseCRI parses a line in the CRI format.  The payload of the result
+// is an alias of b.
+func ParseCRI(b []byte) (Line, error) {
+	var l Line
+	var tags []byte
+	b = bytes.TrimSuffix(b, []byte("\n"))
+	if err := re.Scan(CRIPattern, b, re.Time(&l.Time, time.RFC3339Nano), &l.Stream, &tags, &l.Payload); err != nil {
+		return Line{}, fmt.Errorf("container: bad CRI log line: %w", err)
+	}
+	switch t := bytes.SplitN(tags, []byte(":"), 2)[0]; string(t) {
+	case "P":
+		l.Partial = true
+	case "F":
+	default:
+		return Line{}, fmt.Errorf("container: bad CRI log tag %q", t)
+	}
+	return l, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by calling Parse, so
+// that a *Line can be passed to re.Scan.  The payload is copied.
+func (l *Line) UnmarshalText(text []byte) error {
+	p, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	p.Payload = append([]byte(nil), p.Payload...)
+	*l = p
+	return nil
+}
+
+// Scan matches pattern against the payload of l and stores the
+// sub-matches into output, as re.Scan does.
+func (l Line) Scan(pattern *regexp.Regexp, output ...interface{}) error {
+	return re.Scan(pattern, l.Payload, output...)
+}
@@ -0,0 +1,72 @@
+package container_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/container"
+)
+
+func TestParse(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 10, 0, 0, 1, time.UTC)
+	for _, c := range []struct {
+		input string
+		want  container.Line
+	}{
+		{`{"log":"hello \"world\"\n","stream":"stdout","time":"2024-01-15T10:00:00.000000001Z"}`,
+			container.Line{Time: ts, Stream: "stdout", Payload: []byte(`hello "world"`)}},
+		{`{"log":"partial","stream":"stderr","time":"2024-01-15T10:00:00.000000001Z"}` + "\n",
+			container.Line{Time: ts, Stream: "stderr", Partial: true, Payload: []byte("partial")}},
+		{"2024-01-15T10:00:00.000000001Z stdout F hello  world\n",
+			container.Line{Time: ts, Stream: "stdout", Payload: []byte("hello  world")}},
+		{"2024-01-15T10:00:00.000000001Z stderr P part",
+			container.Line{Time: ts, Stream: "stderr", Partial: true, Payload: []byte("part")}},
+		{"2024-01-15T10:00:00.000000001Z stdout F:x ",
+			container.Line{Time: ts, Stream: "stdout", Payload: []byte("")}},
+		{"2024-01-15T10:00:00.000000001Z stdout F",
+			container.Line{Time: ts, Stream: "stdout", Payload: []byte("")}},
+	} {
+		got, err := container.Parse([]byte(c.input))
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		if !got.Time.Equal(c.want.Time) || got.Stream != c.want.Stream || got.Partial != c.want.Partial || string(got.Payload) != string(c.want.Payload) {
+			t.Errorf("Parse(%q) = %+v; expected %+v", c.input, got, c.want)
+		}
+	}
+	for _, bad := range []string{
+		`{"log":`,
+		"2024-01-15T10:00:00Z stdin F x",
+		"yesterday stdout F x",
+		"2024-01-15T10:00:00Z stdout X x",
+		"",
+	} {
+		if got, err := container.Parse([]byte(bad)); err == nil {
+			t.Errorf("Parse(%q) = %+v; expected an error", bad, got)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	l, err := container.Parse([]byte("2024-01-15T10:00:00Z stdout F GET /x status=404"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var status int
+	if err := l.Scan(regexp.MustCompile(`status=(\d+)`), &status); err != nil || status != 404 {
+		t.Errorf("Scan = %d, %v; expected 404", status, err)
+	}
+
+	// A *Line can be the destination of re.Scan.
+	var line container.Line
+	input := []byte(`node1: {"log":"ok\n","stream":"stdout","time":"2024-01-15T10:00:00Z"}`)
+	if err := re.Scan(regexp.MustCompile(`^\w+: (.*)$`), input, &line); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(line.Payload) != "ok" || line.Stream != "stdout" {
+		t.Errorf("re.Scan into Line = %+v", line)
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Package cron parses crontab schedule expressions, such as "30 9-17 * *
+mon-fri", into a structured Schedule.  A *Schedule implements
+encoding.TextUnmarshaler, so it can be passed directly to re.Scan:
+
+	var sched cron.Schedule
+	var command string
+	err := re.Scan(regexp.MustCompile(`^((?:\S+\s+){4}\S+)\s+(.*)$`), line, &sched, &command)
+
+Errors name the field that could not be parsed, e.g.,
+`cron: hour field "25": 25 out of range [0, 23]`.
+*/
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Schedule is a parsed cron expression.  Each field is a set of
+// allowed values, represented as a bit mask in which bit i is set if
+// value i is allowed.  Day of week 7 is folded into 0 (Sunday).
+type Schedule struct {
+	Second     uint64 // 0-59; just 0 unless six fields were given
+	Minute     uint64 // 0-59
+	Hour       uint64 // 0-23
+	DayOfMonth uint64 // 1-31
+	Month      uint64 // 1-12
+	DayOfWeek  uint64 // 0-6, Sunday is 0
+
+	// AnyDayOfMonth and AnyDayOfWeek record whether the day fields
+	// started with "*", as in "*" or "*/2".  Following cron, if both are
+	// restricted, a day matches if it satisfies either one.
+	AnyDayOfMonth bool
+	AnyDayOfWeek  bool
+}
+
+// field describes one field of a cron expression.
+type field struct {
+	name     string
+	min, max int
+	names    []string // Names of values starting at min, if any
+}
+
+var (
+	second     = field{"second", 0, 59, nil}
+	minute     = field{"minute", 0, 59, nil}
+	hour       = field{"hour", 0, 23, nil}
+	dayOfMonth = field{"day of month", 1, 31, nil}
+	month      = field{"month", 1, 12, []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	dayOfWeek  = field{"day of week", 0, 7, []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat", "sun"}}
+)
+
+// macros holds the expansions of the predefined schedules.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a cron expression.  It accepts five fields (minute, hour,
+// day of month, month and day of week) or six (with a leading seconds
+// field), separated by whitespace, as well as the macros @yearly,
+// @annually, @monthly, @weekly, @daily, @midnight and @hourly.
+//
+// Each field is a comma-separated list of "*", a value, or a range
+// "a-b", each optionally followed by a step "/n"; "a/n" is short for
+// "a-max/n".  Months and days of the week may also be given by their
+// three-letter English names, in any case.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@") {
+		expansion, ok := macros[strings.ToLower(spec)]
+		if !ok {
+			return Schedule{}, fmt.Errorf("cron: unknown macro %q", spec)
+		}
+		spec = expansion
+	}
+	parts := strings.Fields(spec)
+	if len(parts) == 5 {
+		parts = append([]string{"0"}, parts...)
+	} else if len(parts) != 6 {
+		return Schedule{}, fmt.Errorf("cron: %q has %d fields; expected 5 or 6", spec, len(parts))
+	}
+	var s Schedule
+	for i, dst := range []struct {
+		f    field
+		bits *uint64
+	}{
+		{second, &s.Second},
+		{minute, &s.Minute},
+		{hour, &s.Hour},
+		{dayOfMonth, &s.DayOfMonth},
+		{month, &s.Month},
+		{dayOfWeek, &s.DayOfWeek},
+	} {
+		bits, err := dst.f.parse(parts[i])
+		if err != nil {
+			return Schedule{}, fmt.Errorf("cron: %s field %q: %w", dst.f.name, parts[i], err)
+		}
+		*dst.bits = bits
+	}
+	if s.DayOfWeek&(1<<7) != 0 {
+		s.DayOfWeek = s.DayOfWeek&^(1<<7) | 1
+	}
+	s.AnyDayOfMonth = strings.HasPrefix(parts[3], "*")
+	s.AnyDayOfWeek = strings.HasPrefix(parts[5], "*")
+	return s, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by calling Parse.
+func (s *Schedule) UnmarshalText(text []byte) error {
+	p, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*s = p
+	return nil
+}
+
+// Matches reports whether the schedule fires at t, to the second.
+func (s Schedule) Matches(t time.Time) bool {
+	if !has(s.Second, t.Second()) || !has(s.Minute, t.Minute()) ||
+		!has(s.Hour, t.Hour()) || !has(s.Month, int(t.Month())) {
+		return false
+	}
+	dom, dow := has(s.DayOfMonth, t.Day()), has(s.DayOfWeek, int(t.Weekday()))
+	if s.AnyDayOfMonth || s.AnyDayOfWeek {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+// parse returns the set of values described by text.
+func (f field) parse(text string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(text, ",") {
+		rng, step := item, 1
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("bad step %q", item[i+1:])
+			}
+			rng, step = item[:i], n
+		}
+		lo, hi := f.min, f.max
+		if f.name == dayOfWeek.name {
+			hi = 6 // Implicit upper bound; avoid listing Sunday twice
+		}
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.IndexByte(rng, '-')
+			var err error
+			if lo, err = f.value(rng[:i]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(rng[i+1:]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("empty range %q", rng)
+			}
+		default:
+			v, err := f.value(rng)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single value of the field, given as a number or name.
+func (f field) value(text string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(text, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("bad value %q", text)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%d out of range [%d, %d]", v, f.min, f.max)
+	}
+	return v, nil
+}
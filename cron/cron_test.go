@@ -0,0 +1,124 @@
+package cron_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/cron"
+)
+
+// bits returns a bit mask with the given values set.
+func bits(values ...int) uint64 {
+	var b uint64
+	for _, v := range values {
+		b |= 1 << uint(v)
+	}
+	return b
+}
+
+// span returns a bit mask with values lo through hi set.
+func span(lo, hi int) uint64 {
+	var b uint64
+	for v := lo; v <= hi; v++ {
+		b |= 1 << uint(v)
+	}
+	return b
+}
+
+func TestParse(t *testing.T) {
+	for _, c := range []struct {
+		spec string
+		want cron.Schedule
+	}{
+		{"* * * * *", cron.Schedule{Second: bits(0), Minute: span(0, 59), Hour: span(0, 23), DayOfMonth: span(1, 31), Month: span(1, 12), DayOfWeek: span(0, 6), AnyDayOfMonth: true, AnyDayOfWeek: true}},
+		{"*/15 9-17 1,15 JAN-mar/2 mon-fri", cron.Schedule{Second: bits(0), Minute: bits(0, 15, 30, 45), Hour: span(9, 17), DayOfMonth: bits(1, 15), Month: bits(1, 3), DayOfWeek: span(1, 5)}},
+		{"30 5 0 12 * 7", cron.Schedule{Second: bits(30), Minute: bits(5), Hour: bits(0), DayOfMonth: bits(12), Month: span(1, 12), DayOfWeek: bits(0)}},
+		{"0 0 * * 5-7", cron.Schedule{Second: bits(0), Minute: bits(0), Hour: bits(0), DayOfMonth: span(1, 31), Month: span(1, 12), DayOfWeek: bits(0, 5, 6), AnyDayOfMonth: true}},
+		{"50/5 * * * */2", cron.Schedule{Second: bits(0), Minute: bits(50, 55), Hour: span(0, 23), DayOfMonth: span(1, 31), Month: span(1, 12), DayOfWeek: bits(0, 2, 4, 6), AnyDayOfMonth: true, AnyDayOfWeek: true}},
+		{"@Weekly", cron.Schedule{Second: bits(0), Minute: bits(0), Hour: bits(0), DayOfMonth: span(1, 31), Month: span(1, 12), DayOfWeek: bits(0), AnyDayOfMonth: true}},
+	} {
+		got, err := cron.Parse(c.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %s", c.spec, err)
+		} else if got != c.want {
+			t.Errorf("Parse(%q) = %+v; expected %+v", c.spec, got, c.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, c := range []struct {
+		spec string
+		want string // Expected substring of the error
+	}{
+		{"* * * *", "4 fields"},
+		{"* * * * * * *", "7 fields"},
+		{"60 * * * *", `minute field "60": 60 out of range [0, 59]`},
+		{"* 25 * * *", `hour field "25"`},
+		{"* * 0 * *", `day of month field "0"`},
+		{"* * * foo *", `month field "foo": bad value "foo"`},
+		{"* * * * 8", `day of week field "8"`},
+		{"*/0 * * * *", `bad step "0"`},
+		{"* 5-3 * * *", `empty range "5-3"`},
+		{"@often", "unknown macro"},
+	} {
+		_, err := cron.Parse(c.spec)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("Parse(%q) error = %v; expected one containing %q", c.spec, err, c.want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.Parse("2006-01-02 15:04:05", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	for _, c := range []struct {
+		spec string
+		time string
+		want bool
+	}{
+		{"*/15 9-17 * * mon-fri", "2024-01-15 09:30:00", true}, // Monday
+		{"*/15 9-17 * * mon-fri", "2024-01-15 09:31:00", false},
+		{"*/15 9-17 * * mon-fri", "2024-01-15 09:30:01", false},
+		{"*/15 9-17 * * mon-fri", "2024-01-14 09:30:00", false}, // Sunday
+		{"0 0 13 * fri", "2024-09-13 00:00:00", true},           // Either day field
+		{"0 0 13 * fri", "2024-09-20 00:00:00", true},
+		{"0 0 13 * fri", "2024-09-21 00:00:00", false},
+		{"0 0 13 * *", "2024-09-20 00:00:00", false},
+		{"0 0 */2 * 1", "2024-01-01 00:00:00", true}, // Stepped "*" is not a restriction
+		{"0 0 */2 * 1", "2024-01-08 00:00:00", false},
+		{"0 0 */2 * 1", "2024-01-03 00:00:00", false},
+	} {
+		s, err := cron.Parse(c.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %s", c.spec, err)
+		}
+		if got := s.Matches(at(c.time)); got != c.want {
+			t.Errorf("Parse(%q).Matches(%s) = %v; expected %v", c.spec, c.time, got, c.want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	var sched cron.Schedule
+	var command string
+	line := []byte("0 3 * * sun /usr/bin/backup --full")
+	pattern := regexp.MustCompile(`^((?:\S+\s+){4}\S+)\s+(.*)$`)
+	if err := re.Scan(pattern, line, &sched, &command); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if sched.Hour != bits(3) || sched.DayOfWeek != bits(0) || command != "/usr/bin/backup --full" {
+		t.Errorf("got %+v, %q", sched, command)
+	}
+	if err := re.Scan(pattern, []byte("0 3 * * funday cmd"), &sched, &command); err == nil {
+		t.Errorf("Scan of bad schedule succeeded unexpectedly")
+	}
+}
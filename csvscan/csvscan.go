@@ -0,0 +1,117 @@
+/*
+Package csvscan combines encoding/csv with re.Scan: records are read
+with a csv.Reader, which takes care of quoting, and then individual
+columns (or the whole record) are matched against regular expressions
+whose sub-matches are stored into typed outputs.  This suits
+semi-structured CSV files whose cells have internal structure, such as
+a "host:port" column:
+
+	var name, host string
+	var port int
+	r := csvscan.NewReader(csv.NewReader(f))
+	r.Column(0, regexp.MustCompile(`^(.*)$`), &name)
+	r.Column(1, regexp.MustCompile(`^([^:]+):(\d+)$`), &host, &port)
+	for {
+		err := r.Scan()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		Process(name, host, port)
+	}
+*/
+package csvscan
+
+import (
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ghemawat/re"
+)
+
+// A Reader reads CSV records and scans them with regular expressions.
+type Reader struct {
+	r       *csv.Reader
+	columns []column
+	row     *re.Scanner
+	sep     string
+	record  []string
+	n       int // Number of records read
+}
+
+// column describes the scanning applied to one column.
+type column struct {
+	index   int
+	scanner *re.Scanner
+}
+
+// NewReader returns a Reader that reads records from r.  Configure r
+// (e.g., its Comma or LazyQuotes fields) before the first call to Scan.
+func NewReader(r *csv.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Column arranges for column i (counting from zero) of each record to
+// be matched against pattern, with the sub-matches stored into output
+// following the rules of re.Scan.  It returns an error if i is negative
+// or if the outputs cannot be bound to pattern; see re.Bind.
+func (r *Reader) Column(i int, pattern *regexp.Regexp, output ...interface{}) error {
+	if i < 0 {
+		return fmt.Errorf("csvscan: negative column %d", i)
+	}
+	s, err := re.Bind(pattern, output...)
+	if err != nil {
+		return err
+	}
+	r.columns = append(r.columns, column{index: i, scanner: s})
+	return nil
+}
+
+// Row arranges for the fields of each record, joined by sep, to be
+// matched against pattern, with the sub-matches stored into output
+// following the rules of re.Scan.  It returns an error if the outputs
+// cannot be bound to pattern; see re.Bind.
+func (r *Reader) Row(sep string, pattern *regexp.Regexp, output ...interface{}) error {
+	s, err := re.Bind(pattern, output...)
+	if err != nil {
+		return err
+	}
+	r.row, r.sep = s, sep
+	return nil
+}
+
+// Scan reads the next record and applies the column and row patterns
+// to it.  It returns io.EOF once there are no more records.  A record
+// that fails to match, or whose sub-matches cannot be parsed, results
+// in an error identifying the record and column; reading can continue
+// with the next record after such an error.
+func (r *Reader) Scan() error {
+	record, err := r.r.Read()
+	if err != nil {
+		return err
+	}
+	r.record = record
+	r.n++
+	for _, c := range r.columns {
+		if c.index >= len(record) {
+			return fmt.Errorf("csvscan: record %d: no column %d", r.n, c.index)
+		}
+		if err := c.scanner.Scan([]byte(record[c.index])); err != nil {
+			return fmt.Errorf("csvscan: record %d, column %d: %w", r.n, c.index, err)
+		}
+	}
+	if r.row != nil {
+		if err := r.row.Scan([]byte(strings.Join(record, r.sep))); err != nil {
+			return fmt.Errorf("csvscan: record %d: %w", r.n, err)
+		}
+	}
+	return nil
+}
+
+// Record returns the fields of the record read by the last call to Scan.
+func (r *Reader) Record() []string {
+	return r.record
+}
@@ -0,0 +1,73 @@
+package csvscan_test
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/csvscan"
+)
+
+func TestReader(t *testing.T) {
+	input := "web,\"www.google.com:80\",1\n" +
+		"db,db:5432,2\n" +
+		"bad,nohost,3\n" +
+		"short\n" +
+		"last,h:1,4\n"
+	c := csv.NewReader(strings.NewReader(input))
+	c.FieldsPerRecord = -1
+	r := csvscan.NewReader(c)
+
+	var host string
+	var port, id int
+	if err := r.Column(1, regexp.MustCompile(`^([^:]+):(\d+)$`), &host, &port); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := r.Row("|", regexp.MustCompile(`\|(\d+)$`), &id); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	type result struct {
+		host string
+		port int
+		id   int
+		ok   bool
+	}
+	var got []result
+	for {
+		err := r.Scan()
+		if err == io.EOF {
+			break
+		}
+		got = append(got, result{host, port, id, err == nil})
+		if err != nil && strings.HasPrefix(r.Record()[0], "bad") && !errors.Is(err, re.NotFound) {
+			t.Errorf("error for bad record was %v, want an error that wraps %v", err, re.NotFound)
+		}
+	}
+	want := []result{
+		{"www.google.com", 80, 1, true},
+		{"db", 5432, 2, true},
+		{"db", 5432, 2, false},
+		{"db", 5432, 2, false},
+		{"h", 1, 4, true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d records; expected %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("record %d: got %+v; expected %+v", i, got[i], want[i])
+		}
+	}
+
+	if err := r.Column(0, regexp.MustCompile(`.*`), &host); err == nil {
+		t.Errorf("Column with too few sub-matches succeeded unexpectedly")
+	}
+	if err := r.Column(-1, regexp.MustCompile(`(.*)`), &host); err == nil {
+		t.Errorf("Column with negative index succeeded unexpectedly")
+	}
+}
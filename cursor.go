@@ -0,0 +1,91 @@
+package re
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// A Cursor consumes an input sequentially: each call to Scan finds the
+// next match at or after the current position and advances past it.
+// This replaces the idiom of re-slicing the input after each match:
+//
+//	c := re.NewCursor(input)
+//	for {
+//		var key, value string
+//		if err := c.Scan(pair, &key, &value); err != nil {
+//			break
+//		}
+//		Process(key, value)
+//	}
+//
+// The remainder of the input is matched as if it were a new input, so
+// ^ and \A match at the current position; a pattern such as `^\s*(\w+)`
+// therefore only matches text that immediately follows the previous
+// match.  Spans stored into *Span outputs are offsets from the start of
+// the whole input.
+type Cursor struct {
+	input   []byte
+	pos     int
+	matched bool // Did the previous call to Scan end at pos?
+}
+
+// NewCursor returns a Cursor positioned at the start of input.
+func NewCursor(input []byte) *Cursor {
+	return &Cursor{input: input}
+}
+
+// Scan finds the next match of re at or after the current position,
+// stores its sub-matches into output following the rules of re.Scan,
+// and advances the position to the end of the match.  Like FindAll, Scan
+// ignores an empty match that abuts the previous match, so a loop over
+// Scan always terminates.
+//
+// If there is no match, Scan returns an error wrapping NotFound.  On any
+// error, the position is left unchanged, so the caller may try a
+// different pattern at the same position.
+func (c *Cursor) Scan(re *regexp.Regexp, output ...interface{}) error {
+	start := c.pos
+	matches := re.FindSubmatchIndex(c.input[start:])
+	if matches != nil && matches[1] == 0 && c.matched {
+		// Empty match abutting the previous match: retry one rune later.
+		if start == len(c.input) {
+			matches = nil
+		} else {
+			_, size := utf8.DecodeRune(c.input[start:])
+			start += size
+			matches = re.FindSubmatchIndex(c.input[start:])
+		}
+	}
+	if matches == nil {
+		return fmt.Errorf("regular expression %q: %w", re, NotFound)
+	}
+	for i := range matches {
+		if matches[i] >= 0 {
+			matches[i] += start
+		}
+	}
+	if err := scanMatch(re, c.input, matches, output); err != nil {
+		return err
+	}
+	c.pos = matches[1]
+	c.matched = true
+	return nil
+}
+
+// Pos returns the current position as an offset from the start of the
+// input.
+func (c *Cursor) Pos() int {
+	return c.pos
+}
+
+// Rest returns the unconsumed remainder of the input.  The result is an
+// alias of the input.
+func (c *Cursor) Rest() []byte {
+	return c.input[c.pos:]
+}
+
+// Done reports whether the entire input has been consumed.
+func (c *Cursor) Done() bool {
+	return c.pos == len(c.input)
+}
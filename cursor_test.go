@@ -0,0 +1,94 @@
+package re_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestCursor(t *testing.T) {
+	c := re.NewCursor([]byte("host:1234 host2:2345 rest"))
+	pair := regexp.MustCompile(`(\w+):(\d+)`)
+	for _, want := range []struct {
+		span re.Span
+		host string
+		port int
+	}{
+		{re.Span{Start: 0, End: 9}, "host", 1234},
+		{re.Span{Start: 10, End: 20}, "host2", 2345},
+	} {
+		var span re.Span
+		var host string
+		var port int
+		if err := c.Scan(pair, &host, &port, re.Group(0, &span)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if span != want.span || host != want.host || port != want.port {
+			t.Errorf("got %v %s %d; expected %v %s %d", span, host, port, want.span, want.host, want.port)
+		}
+		if c.Pos() != want.span.End {
+			t.Errorf("Pos() = %d; expected %d", c.Pos(), want.span.End)
+		}
+	}
+	if err := c.Scan(pair); !errors.Is(err, re.NotFound) {
+		t.Errorf("Scan error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+	if c.Pos() != 20 || string(c.Rest()) != " rest" || c.Done() {
+		t.Errorf("failed Scan moved the cursor to %d", c.Pos())
+	}
+}
+
+func TestCursorAnchored(t *testing.T) {
+	c := re.NewCursor([]byte("let x = 42"))
+	var kw, name string
+	var value int
+	if err := c.Scan(regexp.MustCompile(`^(let|var)\b`), &kw); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := c.Scan(regexp.MustCompile(`^\s*(\d+)`), &value); err == nil {
+		t.Errorf("anchored Scan matched away from the current position")
+	}
+	if err := c.Scan(regexp.MustCompile(`^\s*(\w+)`), &name); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := c.Scan(regexp.MustCompile(`^\s*=\s*(\d+)$`), &value); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if kw != "let" || name != "x" || value != 42 || !c.Done() {
+		t.Errorf("got %q %q %d, done=%v", kw, name, value, c.Done())
+	}
+}
+
+func TestCursorErrors(t *testing.T) {
+	c := re.NewCursor([]byte("n=300 n=3"))
+	var n uint8
+	num := regexp.MustCompile(`n=(\d+)`)
+	if err := c.Scan(num, &n); err == nil || errors.Is(err, re.NotFound) {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+	if c.Pos() != 0 {
+		t.Errorf("failed Scan moved the cursor to %d", c.Pos())
+	}
+}
+
+func TestCursorEmptyMatches(t *testing.T) {
+	c := re.NewCursor([]byte("ab"))
+	var spans []re.Span
+	for {
+		var s re.Span
+		if err := c.Scan(regexp.MustCompile(`a*`), re.Group(0, &s)); err != nil {
+			break
+		}
+		spans = append(spans, s)
+		if len(spans) > 10 {
+			t.Fatalf("Scan did not terminate: %v", spans)
+		}
+	}
+	// Same as FindAllIndex: [0,1] [2,2].
+	want := []re.Span{{Start: 0, End: 1}, {Start: 2, End: 2}}
+	if len(spans) != len(want) || spans[0] != want[0] || spans[1] != want[1] {
+		t.Errorf("got %v, expected %v", spans, want)
+	}
+}
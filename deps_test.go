@@ -0,0 +1,70 @@
+package re_test
+
+import (
+	"go/build"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDependencies checks that the re package, and every subpackage,
+// imports only the standard library (and, for subpackages, other
+// packages of this module), so that the module needs no requirements.
+func TestDependencies(t *testing.T) {
+	dirs := []string{"."}
+	entries, err := ioutil.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	const module = "github.com/ghemawat/re"
+	for _, dir := range dirs {
+		pkg, err := build.ImportDir(dir, 0)
+		if err != nil {
+			t.Fatalf("%s: %s", dir, err)
+		}
+		for _, path := range pkg.Imports {
+			if dir == "." && strings.HasPrefix(path, module+"/") {
+				t.Errorf("package re imports subpackage %s", path)
+			}
+			if path != module && !strings.HasPrefix(path, module+"/") &&
+				strings.Contains(strings.Split(path, "/")[0], ".") {
+				t.Errorf("%s imports non-standard package %s", filepath.Join(module, dir), path)
+			}
+		}
+	}
+}
+
+// minimalImports lists the packages that the re package may import when
+// built with the re_minimal tag.
+var minimalImports = map[string]bool{
+	"bytes": true, "encoding": true, "encoding/base64": true,
+	"encoding/hex": true, "errors": true, "fmt": true, "html": true,
+	"io/ioutil": true, "iter": true, "math": true, "net/netip": true,
+	"net/url": true, "reflect": true, "regexp": true,
+	"regexp/syntax": true, "sort": true, "strconv": true, "strings": true,
+	"sync": true, "sync/atomic": true, "time": true, "unicode": true,
+	"unicode/utf8": true,
+}
+
+// TestMinimalDependencies checks that the re_minimal tag leaves out the
+// adapters and the streaming helpers, along with the packages they
+// import.
+func TestMinimalDependencies(t *testing.T) {
+	ctx := build.Default
+	ctx.BuildTags = append([]string{"re_minimal"}, ctx.BuildTags...)
+	pkg, err := ctx.ImportDir(".", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range pkg.Imports {
+		if !minimalImports[path] {
+			t.Errorf("package re built with re_minimal imports %s", path)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Package email reads the header of an email message, as defined by RFC
+5322, and parses its Received fields into the chain of relays that the
+message passed through:
+
+	fields, err := email.ReadHeader(r)
+	if err != nil {
+		return err
+	}
+	for _, h := range email.Chain(fields) {
+		fmt.Println(h.Time, h.From, h.FromIP, "->", h.By)
+	}
+
+Received fields are written by many different servers, so parsing is
+lenient: clauses are recognized by their keywords (from, by, via, with,
+id, for) in any order, and unknown text is skipped.
+*/
+package email
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ghemawat/re"
+)
+
+// A Field is one header field.
+type Field struct {
+	Name  string // As written, e.g., "Received"
+	Value string // Unfolded, with leading and trailing space removed
+}
+
+// fieldPattern matches the first line of a header field.
+var fieldPattern = regexp.MustCompile(`^([!-9;-~]+)[ \t]*:(.*)$`)
+
+// ReadHeader reads header fields from r up to the blank line that ends
+// the header, or the end of the input.  Folded fields (ones continued on
+// lines starting with a space or tab) are unfolded.  An error is
+// returned for a line that is neither a field nor a continuation.
+func ReadHeader(r io.Reader) ([]Field, error) {
+	br := bufio.NewReader(r)
+	var fields []Field
+	for n := 1; ; n++ {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("email: line %d: continuation without a field", n)
+			}
+			f := &fields[len(fields)-1]
+			f.Value = strings.TrimSpace(f.Value + " " + strings.TrimSpace(line))
+		} else {
+			var f Field
+			if re.Scan(fieldPattern, []byte(line), &f.Name, &f.Value) != nil {
+				return nil, fmt.Errorf("email: line %d: bad header field %q", n, line)
+			}
+			f.Value = strings.TrimSpace(f.Value)
+			fields = append(fields, f)
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return fields, nil
+}
+
+// Get returns the values of the fields with the given name, ignoring
+// case, in the order in which they appear.
+func Get(fields []Field, name string) []string {
+	var values []string
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			values = append(values, f.Value)
+		}
+	}
+	return values
+}
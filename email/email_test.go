@@ -0,0 +1,43 @@
+package email_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re/email"
+)
+
+func TestReadHeader(t *testing.T) {
+	input := "Received: from a.example.com\r\n" +
+		"\tby b.example.com; Mon, 15 Jan 2024 10:00:00 +0000\r\n" +
+		"Subject:  Hello\r\n" +
+		"  world \r\n" +
+		"X-Empty:\r\n" +
+		"\r\n" +
+		"Body: not a header\r\n"
+	fields, err := email.ReadHeader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []email.Field{
+		{"Received", "from a.example.com by b.example.com; Mon, 15 Jan 2024 10:00:00 +0000"},
+		{"Subject", "Hello world"},
+		{"X-Empty", ""},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ReadHeader = %q; expected %q", fields, want)
+	}
+	if got := email.Get(fields, "subject"); len(got) != 1 || got[0] != "Hello world" {
+		t.Errorf("Get(subject) = %q", got)
+	}
+
+	for _, bad := range []string{" leading continuation\n", "no colon here\n"} {
+		if f, err := email.ReadHeader(strings.NewReader(bad)); err == nil {
+			t.Errorf("ReadHeader(%q) = %q; expected an error", bad, f)
+		}
+	}
+	if f, err := email.ReadHeader(strings.NewReader("A: 1")); err != nil || len(f) != 1 {
+		t.Errorf("ReadHeader without final newline = %q, %v", f, err)
+	}
+}
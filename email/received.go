@@ -0,0 +1,148 @@
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Received describes one hop recorded in a Received field, e.g.,
+//
+//	from mx.example.com (mx.example.com [203.0.113.5])
+//		by mail.example.org (Postfix) with ESMTPS id 4F2A
+//		for <bob@example.org>; Mon, 15 Jan 2024 10:00:00 +0000
+type Received struct {
+	From        string    // Name the sending host gave (e.g., in HELO)
+	FromComment string    // Comment after From, often holding the verified name and IP
+	FromIP      string    // Sending host's IP address, if recorded
+	By          string    // Receiving host
+	ByComment   string    // Comment after By, often naming the software
+	Via         string    // Link type
+	With        string    // Protocol, e.g., "ESMTPS"
+	ID          string    // Receiving host's queue identifier
+	For         string    // Recipient, without angle brackets
+	Time        time.Time // When the message was received; zero if unparsable
+	Raw         string    // Entire field value
+}
+
+// ipPattern finds an address literal such as [203.0.113.5] or
+// [IPv6:2001:db8::1].
+var ipPattern = regexp.MustCompile(`\[(?:IPv6:)?([0-9A-Fa-f:.]+)\]`)
+
+// ParseReceived parses the value of a Received field.  The timestamp
+// follows the last semicolon; if it cannot be parsed, Time is left zero
+// rather than failing, since many servers write non-standard dates.  An
+// error is returned only if the field has neither a from nor a by
+// clause.
+func ParseReceived(value string) (Received, error) {
+	r := Received{Raw: value}
+	clauses := value
+	if i := strings.LastIndexByte(value, ';'); i >= 0 {
+		clauses = value[:i]
+		if t, err := mail.ParseDate(strings.TrimSpace(value[i+1:])); err == nil {
+			r.Time = t
+		}
+	}
+
+	var clause *string   // Value of the current clause
+	var comment *string  // Comment of the current clause
+	expectValue := false // The previous token was a keyword
+	for _, tok := range tokenize(clauses) {
+		if strings.HasPrefix(tok, "(") {
+			if comment != nil && *comment == "" {
+				*comment = strings.TrimSpace(tok[1 : len(tok)-1])
+			}
+			continue
+		}
+		if expectValue {
+			*clause = tok
+			expectValue = false
+			continue
+		}
+		comment = nil
+		switch strings.ToLower(tok) {
+		case "from":
+			clause, comment = &r.From, &r.FromComment
+		case "by":
+			clause, comment = &r.By, &r.ByComment
+		case "via":
+			clause = &r.Via
+		case "with":
+			clause = &r.With
+		case "id":
+			clause = &r.ID
+		case "for":
+			clause = &r.For
+		default:
+			continue // Unknown text
+		}
+		expectValue = true
+	}
+	if r.From == "" && r.By == "" {
+		return Received{}, fmt.Errorf("email: Received field without from or by: %q", value)
+	}
+	r.For = strings.TrimSuffix(strings.TrimPrefix(r.For, "<"), ">")
+	for _, s := range []string{r.FromComment, r.From} {
+		if m := ipPattern.FindStringSubmatch(s); m != nil {
+			r.FromIP = m[1]
+			break
+		}
+	}
+	return r, nil
+}
+
+// tokenize splits s into words and parenthesized comments (which may be
+// nested).  An unterminated comment extends to the end of s.
+func tokenize(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(':
+			depth, j := 0, i
+			for ; j < len(s); j++ {
+				if s[j] == '(' {
+					depth++
+				} else if s[j] == ')' {
+					depth--
+				}
+				if depth == 0 {
+					break
+				}
+			}
+			if j == len(s) {
+				tokens = append(tokens, s[i:]+")")
+				i = j
+			} else {
+				tokens = append(tokens, s[i:j+1])
+				i = j + 1
+			}
+		default:
+			j := i
+			for j < len(s) && strings.IndexByte(" \t\r\n(", s[j]) < 0 {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+// Chain parses the Received fields among fields and returns them in the
+// order the message travelled, from the first relay to the last; i.e.,
+// in the reverse of the order in which they appear in the header.
+// Fields that cannot be parsed are skipped.
+func Chain(fields []Field) []Received {
+	var hops []Received
+	values := Get(fields, "Received")
+	for i := len(values) - 1; i >= 0; i-- {
+		if r, err := ParseReceived(values[i]); err == nil {
+			hops = append(hops, r)
+		}
+	}
+	return hops
+}
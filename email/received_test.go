@@ -0,0 +1,64 @@
+package email_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re/email"
+)
+
+func TestParseReceived(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  email.Received
+	}{
+		{
+			"from mx.example.com (mx.example.com [203.0.113.5]) by mail.example.org (Postfix) with ESMTPS id 4F2A for <bob@example.org>; Mon, 15 Jan 2024 10:00:00 +0000",
+			email.Received{From: "mx.example.com", FromComment: "mx.example.com [203.0.113.5]", FromIP: "203.0.113.5", By: "mail.example.org", ByComment: "Postfix", With: "ESMTPS", ID: "4F2A", For: "bob@example.org", Time: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			// Nested comments, IPv6 literal, clauses out of order, bad date.
+			"by relay.example.net (8.15.2/8.15.2) with SMTP id x1 from [IPv6:2001:db8::1] (helo (nested) stuff); sometime",
+			email.Received{From: "[IPv6:2001:db8::1]", FromComment: "helo (nested) stuff", FromIP: "2001:db8::1", By: "relay.example.net", ByComment: "8.15.2/8.15.2", With: "SMTP", ID: "x1"},
+		},
+		{
+			"by localhost via HTTP; Tue, 16 Jan 2024 08:30:00 -0500 (EST)",
+			email.Received{By: "localhost", Via: "HTTP", Time: time.Date(2024, 1, 16, 13, 30, 0, 0, time.UTC)},
+		},
+		{
+			"from unknown (HELO x) (198.51.100.7) by mx (unterminated",
+			email.Received{From: "unknown", FromComment: "HELO x", By: "mx", ByComment: "unterminated"},
+		},
+	} {
+		got, err := email.ParseReceived(c.input)
+		if err != nil {
+			t.Errorf("ParseReceived(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		c.want.Raw = c.input
+		gotTime, wantTime := got.Time, c.want.Time
+		got.Time, c.want.Time = time.Time{}, time.Time{}
+		if got != c.want || !gotTime.Equal(wantTime) {
+			t.Errorf("ParseReceived(%q) =\n%+v %v\nexpected\n%+v %v", c.input, got, gotTime, c.want, wantTime)
+		}
+	}
+	if r, err := email.ParseReceived("with SMTP; Mon, 15 Jan 2024 10:00:00 +0000"); err == nil {
+		t.Errorf("ParseReceived without from or by = %+v; expected an error", r)
+	}
+}
+
+func TestChain(t *testing.T) {
+	header := "Received: from b by c; Mon, 15 Jan 2024 10:00:02 +0000\n" +
+		"Received: garbage\n" +
+		"Received: from a by b; Mon, 15 Jan 2024 10:00:01 +0000\n" +
+		"Subject: hi\n"
+	fields, err := email.ReadHeader(strings.NewReader(header))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	hops := email.Chain(fields)
+	if len(hops) != 2 || hops[0].From != "a" || hops[1].By != "c" {
+		t.Errorf("Chain = %+v", hops)
+	}
+}
@@ -3,6 +3,7 @@ package re_test
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strconv"
 	"time"
@@ -49,19 +50,31 @@ func ExampleScan_binaryNumber() {
 	// 9
 }
 
-// Use a custom re-usable parser for time.Duration.
-func ExampleScan_parseDuration() {
-	// parseDuration(&d) returns a parser that stores its result in *d.
-	parseDuration := func(d *time.Duration) func([]byte) error {
+// Use a custom re-usable parser for *url.URL.
+func ExampleScan_parseURL() {
+	// parseURL(&u) returns a parser that stores its result in *u.
+	parseURL := func(u **url.URL) func([]byte) error {
 		return func(b []byte) (err error) {
-			*d, err = time.ParseDuration(string(b))
+			*u, err = url.Parse(string(b))
 			return err
 		}
 	}
 
+	r := regexp.MustCompile(`^redirect: (.*)$`)
+	var target *url.URL
+	if err := re.Scan(r, []byte("redirect: https://www.google.com/search?q=re"), parseURL(&target)); err != nil {
+		panic(err)
+	}
+	fmt.Println(target.Host, target.Query().Get("q"))
+	// Output:
+	// www.google.com re
+}
+
+// Extract a time.Duration.
+func ExampleScan_duration() {
 	r := regexp.MustCompile(`^elapsed: (.*)$`)
 	var interval time.Duration
-	if err := re.Scan(r, []byte("elapsed: 200s"), parseDuration(&interval)); err != nil {
+	if err := re.Scan(r, []byte("elapsed: 200s"), &interval); err != nil {
 		panic(err)
 	}
 	fmt.Println(interval)
@@ -96,3 +109,59 @@ func ExampleScan_repeatedly() {
 	// host: www.google.com port: 1234
 	// host: www.google.com port: 2345
 }
+
+// Extract every host and port in a line.
+func ExampleScanAll() {
+	var hosts []string
+	var ports []int
+	r := regexp.MustCompile(`(\S+):(\d+)`)
+	n, err := re.ScanAll(r, []byte("www.google.com:1234 www.google.com:2345"), &hosts, &ports)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(n, hosts, ports)
+	// Output:
+	// 2 [www.google.com www.google.com] [1234 2345]
+}
+
+// Extract named groups into the fields of a struct.
+func ExampleUnmarshal() {
+	var addr struct {
+		Host string `re:"host"`
+		Port int    `re:"port"`
+	}
+	r := regexp.MustCompile(`^https?://(?P<host>[^/:]+):(?P<port>\d+)/`)
+	if err := re.Unmarshal(r, []byte("http://www.google.com:1234/"), &addr); err != nil {
+		panic(err)
+	}
+	fmt.Printf("%+v\n", addr)
+	// Output:
+	// {Host:www.google.com Port:1234}
+}
+
+func ExampleUnmarshalAll() {
+	type entry struct {
+		Level string `re:"level"`
+		Msg   string `re:"msg"`
+	}
+	var entries []entry
+	r := regexp.MustCompile(`(?m)^(?P<level>[A-Z]+): (?P<msg>.*)$`)
+	if _, err := re.UnmarshalAll(r, []byte("INFO: started\nWARN: disk low\n"), &entries); err != nil {
+		panic(err)
+	}
+	fmt.Printf("%+v\n", entries)
+	// Output:
+	// [{Level:INFO Msg:started} {Level:WARN Msg:disk low}]
+}
+
+// Parse a timestamp that may be in one of several layouts.
+func ExampleTime() {
+	r := regexp.MustCompile(`^\[(.*?)\] `)
+	var ts time.Time
+	if err := re.Scan(r, []byte("[2014-03-24 10:15:00] started"), re.Time(&ts, time.RFC3339, "2006-01-02 15:04:05")); err != nil {
+		panic(err)
+	}
+	fmt.Println(ts)
+	// Output:
+	// 2014-03-24 10:15:00 +0000 UTC
+}
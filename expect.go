@@ -0,0 +1,141 @@
+//go:build !re_minimal
+
+package re
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// An Expecter waits for text matching regular expressions to appear in
+// an io.Reader, such as the output of a command or a network connection,
+// in the style of the expect tool:
+//
+//	e := re.NewExpecter(stdout)
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	var version string
+//	if err := e.WaitFor(ctx, regexp.MustCompile(`version (\S+)\n`), &version); err != nil {
+//		return err
+//	}
+//	if err := e.WaitFor(ctx, regexp.MustCompile(`\$ $`)); err != nil {
+//		return err
+//	}
+//
+// Since a Read call cannot be interrupted, an Expecter reads from its
+// reader in a separate goroutine.  The goroutine exits once the reader
+// returns an error, or after Close once its pending Read returns.  Text
+// that arrives before a call to WaitFor is kept for that call.
+//
+// A pattern is matched against the text received so far, so it may match
+// before all of the text it would match in the complete input has
+// arrived; e.g., `\d+` may match just the first digits of a number.
+// End patterns with a delimiter, such as a newline, to avoid this.
+type Expecter struct {
+	chunks chan chunk
+	done   chan struct{} // Closed by Close
+	buf    []byte        // Text received but not yet consumed
+	err    error         // Error received from the reader
+}
+
+// chunk is the result of one Read call.
+type chunk struct {
+	data []byte
+	err  error
+}
+
+// NewExpecter returns an Expecter that reads from r.
+func NewExpecter(r io.Reader) *Expecter {
+	e := &Expecter{chunks: make(chan chunk), done: make(chan struct{})}
+	go func() {
+		for {
+			buf := make([]byte, minRead)
+			n, err := r.Read(buf)
+			select {
+			case e.chunks <- chunk{buf[:n], err}:
+			case <-e.done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return e
+}
+
+// WaitFor reads until re matches the text received since the end of the
+// previous match, consumes the text through the end of the match, and
+// stores its sub-matches into output following the rules of re.Scan.
+// Spans stored into *Span outputs are offsets from the end of the
+// previous match, and []byte outputs hold copies rather than aliases.
+// Empty matches are ignored, since they would consume no text and so
+// match again on every later call.
+//
+// If ctx is done before a match is found, WaitFor returns ctx.Err() and
+// the text received so far is kept for the next call.  If the reader
+// reaches the end of its input without a match, WaitFor returns an
+// error wrapping NotFound; if the reader fails, WaitFor returns its
+// error.
+func (e *Expecter) WaitFor(ctx context.Context, re *regexp.Regexp, output ...interface{}) error {
+	for {
+		if matches := firstNonEmpty(re, e.buf); matches != nil {
+			text := append([]byte(nil), e.buf[:matches[1]]...)
+			e.buf = e.buf[matches[1]:]
+			return scanMatch(re, text, matches, output)
+		}
+		if e.err == io.EOF {
+			return fmt.Errorf("regular expression %q: %w", re, NotFound)
+		} else if e.err != nil {
+			return e.err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case c := <-e.chunks:
+			e.buf = append(e.buf, c.data...)
+			e.err = c.err
+		}
+	}
+}
+
+// firstNonEmpty returns the sub-match indices of the first non-empty
+// match of re in b, or nil if there is none.
+func firstNonEmpty(re *regexp.Regexp, b []byte) []int {
+	matches := re.FindSubmatchIndex(b)
+	if matches == nil || matches[1] > matches[0] {
+		return matches
+	}
+	for _, m := range re.FindAllSubmatchIndex(b, -1) {
+		if m[1] > m[0] {
+			return m
+		}
+	}
+	return nil
+}
+
+// Buffered returns the text that has been received but not consumed by
+// a match.  The result is an alias of the Expecter's buffer, and is only
+// valid until the next call to WaitFor.
+func (e *Expecter) Buffered() []byte {
+	return e.buf
+}
+
+// Close stops the Expecter from reading any further; the text read by a
+// pending Read call is discarded.  Close does not close the reader.
+// WaitFor must not be called after Close.
+func (e *Expecter) Close() {
+	close(e.done)
+}
+
+// WaitFor is a shorthand for calling WaitFor once on a new Expecter that
+// reads from r, and then closing it.  Text read from r beyond the end of
+// the match is discarded; use an Expecter to wait for several patterns
+// in turn.
+func WaitFor(ctx context.Context, r io.Reader, re *regexp.Regexp, output ...interface{}) error {
+	e := NewExpecter(r)
+	defer e.Close()
+	return e.WaitFor(ctx, re, output...)
+}
@@ -0,0 +1,92 @@
+//go:build !re_minimal
+
+package re_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+func TestExpecter(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		io.WriteString(pw, "booting...\nversion ")
+		time.Sleep(10 * time.Millisecond)
+		io.WriteString(pw, "1.2.3\nready\n$ ")
+		pw.Close()
+	}()
+
+	e := re.NewExpecter(pr)
+	defer e.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var version string
+	var span re.Span
+	if err := e.WaitFor(ctx, regexp.MustCompile(`version (\S+)\n`), &version, re.Group(0, &span)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if version != "1.2.3" || span != (re.Span{Start: 11, End: 25}) {
+		t.Errorf("got %q at %v; expected \"1.2.3\" at {11 25}", version, span)
+	}
+	if err := e.WaitFor(ctx, regexp.MustCompile(`\$ $`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := e.WaitFor(ctx, regexp.MustCompile(`more`)); !errors.Is(err, re.NotFound) {
+		t.Errorf("WaitFor error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+}
+
+func TestExpecterTimeout(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	e := re.NewExpecter(pr)
+	defer e.Close()
+	go io.WriteString(pw, "login: ")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := e.WaitFor(ctx, regexp.MustCompile(`password: `)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitFor error was %v; expected %v", err, context.DeadlineExceeded)
+	}
+	// Text received before the timeout is kept.
+	if err := e.WaitFor(context.Background(), regexp.MustCompile(`login: `)); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if len(e.Buffered()) != 0 {
+		t.Errorf("Buffered() = %q; expected nothing", e.Buffered())
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	var code int
+	err := re.WaitFor(context.Background(), strings.NewReader("HTTP/1.1 404 Not Found\r\n"), regexp.MustCompile(`^HTTP/\S+ (\d+)`), &code)
+	if err != nil || code != 404 {
+		t.Errorf("WaitFor = %v, %d; expected nil, 404", err, code)
+	}
+	failure := errors.New("connection reset")
+	if err := re.WaitFor(context.Background(), errReader{failure}, regexp.MustCompile(`x`)); err != failure {
+		t.Errorf("WaitFor error was %v; expected %v", err, failure)
+	}
+}
+
+func TestExpecterEmptyMatch(t *testing.T) {
+	e := re.NewExpecter(strings.NewReader("abc 42 x"))
+	defer e.Close()
+	digits := regexp.MustCompile(`(\d*)`)
+	var n string
+	if err := e.WaitFor(context.Background(), digits, &n); err != nil || n != "42" {
+		t.Errorf("WaitFor = %v, %q; expected 42", err, n)
+	}
+	// Only empty matches remain, which must not succeed forever.
+	if err := e.WaitFor(context.Background(), digits, &n); !errors.Is(err, re.NotFound) {
+		t.Errorf("WaitFor error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+}
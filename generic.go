@@ -0,0 +1,168 @@
+//go:build go1.18
+
+package re
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// RegisterParser teaches Scan (and the other functions in this package
+// that parse sub-matches) how to parse values of type T: when a *T is
+// passed as an output, the sub-match is passed to parse and the result
+// is stored into the *T.  A later registration for the same T replaces
+// an earlier one.
+//
+// Registered parsers take precedence over encoding.TextUnmarshaler, but
+// cannot change the handling of the types that Scan supports directly
+// (e.g., *int or *string).  RegisterParser is typically called from an
+// init function, and is safe for concurrent use.
+func RegisterParser[T any](parse func([]byte) (T, error)) {
+	register(reflect.TypeOf((*T)(nil)).Elem(), func(r interface{}) assigner {
+		p := r.(*T)
+		return func(b []byte, _ Span) error {
+			v, err := parse(b)
+			if err != nil {
+				return err
+			}
+			*p = v
+			return nil
+		}
+	})
+}
+
+// Value matches re against input and returns the first sub-match parsed
+// as a T, following the rules Scan uses for a *T.  It is a shorthand for
+// the common case of extracting a single value:
+//
+//	n, err := re.Value[int](regexp.MustCompile(`Total: (\d+)`), report)
+//
+// The zero T is returned along with any error.
+func Value[T any](re *regexp.Regexp, input []byte) (T, error) {
+	var v T
+	if err := Scan(re, input, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
+
+// Default returns an output argument for Scan that stores value into
+// *output if the sub-match is empty, including when its group did not
+// participate in the match, and otherwise parses the sub-match into
+// *output as Scan would.  It suits optional fields with a natural
+// default, such as the port of a URL:
+//
+//	var host string
+//	var port int
+//	reg := regexp.MustCompile(`^http://([^/:]+)(?::(\d+))?`)
+//	err := re.Scan(reg, url, &host, re.Default(&port, 80))
+//
+// OrZero is the special case of a zero default.
+func Default[T any](output *T, value T) func([]byte) error {
+	return func(b []byte) error {
+		if len(b) == 0 {
+			*output = value
+			return nil
+		}
+		return assign(output, b, wrappedSpan(b))
+	}
+}
+
+// number is the set of types accepted by InRange.
+type number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// InRange returns an output argument for Scan that parses the sub-match
+// into *output as Scan would, but returns an error, leaving *output
+// unchanged, if the result is less than lo or greater than hi:
+//
+//	var port int
+//	err := re.Scan(reg, line, re.InRange(&port, 1, 65535))
+//
+// Named numeric types that Scan does not support, e.g., "type Port
+// int", are parsed as their underlying type.
+func InRange[T number](output *T, lo, hi T) func([]byte) error {
+	parse := numberParser[T]()
+	return func(b []byte) error {
+		v, err := parse(b)
+		if err != nil {
+			return err
+		}
+		if v < lo || v > hi || v != v { // v != v rejects NaN
+			return parseError(fmt.Sprintf("out of range [%v, %v]", lo, hi), b)
+		}
+		*output = v
+		return nil
+	}
+}
+
+// numberParser returns a function that parses a sub-match into a T as
+// Scan would, or as Scan would parse the underlying type of T if Scan
+// does not support T itself.
+func numberParser[T number]() func([]byte) (T, error) {
+	var zero T
+	t := reflect.TypeOf(zero)
+	if _, err := newAssigner(&zero); err == nil {
+		return func(b []byte) (T, error) {
+			var v T
+			err := assign(&v, b, wrappedSpan(b))
+			return v, err
+		}
+	}
+	basic := numberTypes[t.Kind()]
+	return func(b []byte) (T, error) {
+		v := reflect.New(basic)
+		if err := assign(v.Interface(), b, wrappedSpan(b)); err != nil {
+			return zero, err
+		}
+		return v.Elem().Convert(t).Interface().(T), nil
+	}
+}
+
+// numberTypes maps the kinds allowed by number to their basic types.
+var numberTypes = map[reflect.Kind]reflect.Type{
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Uintptr: reflect.TypeOf(uintptr(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+}
+
+// Enum returns an output argument for Scan that looks the sub-match up
+// in values and stores the corresponding value into *output, returning
+// an error if the sub-match is not a key of values:
+//
+//	var lvl Level
+//	err := re.Scan(reg, line, re.Enum(&lvl, map[string]Level{
+//		"info": Info, "warn": Warning, "error": Error,
+//	}))
+func Enum[T any](output *T, values map[string]T) func([]byte) error {
+	return func(b []byte) error {
+		v, ok := values[string(b)]
+		if !ok {
+			keys := make([]string, 0, len(values))
+			for k := range values {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			return parseError("not one of "+strings.Join(keys, ", "), b)
+		}
+		*output = v
+		return nil
+	}
+}
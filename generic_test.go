@@ -0,0 +1,171 @@
+//go:build go1.18
+
+package re_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+type level int
+
+type upper string
+
+// UnmarshalText is overridden by the parser registered for upper.
+func (u *upper) UnmarshalText(b []byte) error {
+	return errors.New("should not be called")
+}
+
+func init() {
+	re.RegisterParser(func(b []byte) (level, error) {
+		switch string(b) {
+		case "info":
+			return 1, nil
+		case "error":
+			return 2, nil
+		}
+		return 0, errors.New("unknown level")
+	})
+	re.RegisterParser(func(b []byte) (upper, error) {
+		return upper(strings.ToUpper(string(b))), nil
+	})
+}
+
+func TestRegisterParser(t *testing.T) {
+	r := regexp.MustCompile(`^(\w+): (\w+)$`)
+	var l level
+	var u upper
+	if err := re.Scan(r, []byte("error: disk"), &l, &u); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if l != 2 || u != "DISK" {
+		t.Errorf("Scan extracted %v, %q; expected 2, DISK", l, u)
+	}
+	if err := re.Scan(r, []byte("debug: disk"), &l); err == nil {
+		t.Errorf("Scan of unknown level succeeded unexpectedly")
+	}
+}
+
+func TestValue(t *testing.T) {
+	r := regexp.MustCompile(`Total: (\S+)`)
+	if n, err := re.Value[int](r, []byte("Count: 3 Total: 17")); err != nil || n != 17 {
+		t.Errorf("Value[int] = %d, %v; expected 17", n, err)
+	}
+	if s, err := re.Value[string](r, []byte("Total: x")); err != nil || s != "x" {
+		t.Errorf("Value[string] = %q, %v; expected x", s, err)
+	}
+	if n, err := re.Value[int](r, []byte("Total: 12x")); err == nil || n != 0 {
+		t.Errorf("Value[int] = %d, %v; expected an error", n, err)
+	}
+	if _, err := re.Value[int](r, []byte("none")); !errors.Is(err, re.NotFound) {
+		t.Errorf("Value error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+	if _, err := re.Value[int](regexp.MustCompile(`Total`), []byte("Total")); err == nil {
+		t.Errorf("Value with no sub-match succeeded unexpectedly")
+	}
+}
+
+// Extract a single number.
+func ExampleValue() {
+	r := regexp.MustCompile(`Total: (\d+)`)
+	n, err := re.Value[int](r, []byte("Count: 3 Total: 17"))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(n + 1)
+	// Output:
+	// 18
+}
+
+func TestDefault(t *testing.T) {
+	reg := regexp.MustCompile(`^(\w+)://([^/:]+)(?::(\d*))?`)
+	for _, c := range []struct {
+		input, scheme string
+		port          uint16
+	}{
+		{"http://example.com:8080/x", "http", 8080},
+		{"http://example.com/x", "http", 80},
+		{"http://example.com:/x", "http", 80},
+		{"ftp://example.com", "ftp", 80},
+	} {
+		var scheme string
+		var port uint16
+		err := re.Scan(reg, []byte(c.input), re.Default(&scheme, "http"), nil, re.Default(&port, 80))
+		if err != nil || scheme != c.scheme || port != c.port {
+			t.Errorf("Scan(%q) = %q, %d, %v; expected %q, %d", c.input, scheme, port, err, c.scheme, c.port)
+		}
+	}
+	var port uint16
+	if err := re.Scan(reg, []byte("http://h:99999"), nil, nil, re.Default(&port, 80)); err == nil {
+		t.Errorf("Default of out of range port succeeded unexpectedly")
+	}
+}
+
+func TestInRange(t *testing.T) {
+	reg := regexp.MustCompile(`^(\S+)$`)
+	for _, c := range []struct {
+		input string
+		ok    bool
+	}{
+		{"1", true}, {"65535", true}, {"0", false}, {"65536", false}, {"-5", false}, {"x", false},
+	} {
+		port := 7
+		err := re.Scan(reg, []byte(c.input), re.InRange(&port, 1, 65535))
+		if c.ok && (err != nil || fmt.Sprint(port) != c.input) {
+			t.Errorf("InRange(%q) = %d, %v", c.input, port, err)
+		}
+		if !c.ok && (err == nil || port != 7) {
+			t.Errorf("InRange(%q) = %d, %v; expected an error", c.input, port, err)
+		}
+	}
+	var ratio float64
+	for _, in := range []string{"0.5", "1"} {
+		if err := re.Scan(reg, []byte(in), re.InRange(&ratio, 0, 1)); err != nil {
+			t.Errorf("InRange(%q): unexpected error: %s", in, err)
+		}
+	}
+	for _, in := range []string{"1.01", "NaN", "-Inf"} {
+		if err := re.Scan(reg, []byte(in), re.InRange(&ratio, 0, 1)); err == nil {
+			t.Errorf("InRange(%q) succeeded unexpectedly", in)
+		}
+	}
+	// Registered parsers are used for named numeric types.
+	var lvl level
+	if err := re.Scan(reg, []byte("info"), re.InRange(&lvl, 1, 2)); err != nil || lvl != 1 {
+		t.Errorf("InRange(info) = %d, %v; expected 1", lvl, err)
+	}
+	if err := re.Scan(reg, []byte("error"), re.InRange(&lvl, 0, 1)); err == nil {
+		t.Errorf("InRange(error) succeeded unexpectedly")
+	}
+	// Other named numeric types are parsed as their underlying type.
+	type portNumber uint16
+	var p portNumber
+	if err := re.Scan(reg, []byte("8080"), re.InRange(&p, 1, 65535)); err != nil || p != 8080 {
+		t.Errorf("InRange(8080) into portNumber = %d, %v; expected 8080", p, err)
+	}
+	if err := re.Scan(reg, []byte("0"), re.InRange(&p, 1, 65535)); err == nil || p != 8080 {
+		t.Errorf("InRange(0) into portNumber = %d, %v; expected an error", p, err)
+	}
+}
+
+func TestEnum(t *testing.T) {
+	type color int
+	colors := map[string]color{"red": 1, "green": 2, "blue": 3}
+	reg := regexp.MustCompile(`color=(\w+)`)
+	var c color
+	if err := re.Scan(reg, []byte("color=green"), re.Enum(&c, colors)); err != nil || c != 2 {
+		t.Errorf("Enum = %d, %v; expected 2", c, err)
+	}
+	err := re.Scan(reg, []byte("color=pink"), re.Enum(&c, colors))
+	if err == nil || err.Error() != `re.Scan: parsing "pink": not one of blue, green, red` {
+		t.Errorf("Enum(pink) error = %v", err)
+	}
+	if c != 2 {
+		t.Errorf("failed Enum changed output to %d", c)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Package grok builds regular expressions out of named, reusable
+subpatterns in the style of Logstash's grok filter:
+
+	p, err := grok.Compile(`%{IPORHOST:client} %{WORD:method} %{URIPATHPARAM:path} %{NUMBER:status:int}`)
+	fields, err := p.Parse(line) // {"client": "10.0.0.1", ..., "status": int64(200)}
+
+A reference %{NAME} is replaced by the pattern called NAME.  %{NAME:field}
+also captures the text in a group called field, and %{NAME:field:type}
+additionally converts it to the given type ("int" or "float") in the
+result of Parse.  Since fields become ordinary named groups, the
+compiled regular expression also works with re.Unmarshal and re.Named:
+
+	var entry struct {
+		Client string `re:"client"`
+		Status int    `re:"status"`
+	}
+	err := re.Unmarshal(p.Regexp(), line, &entry)
+
+Base holds the predefined patterns; a Library adds patterns of its own.
+*/
+package grok
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ghemawat/re"
+)
+
+// A Library holds a set of named patterns.
+type Library struct {
+	patterns map[string]string
+}
+
+// New returns a Library holding the patterns in Base.
+func New() *Library {
+	l := &Library{patterns: map[string]string{}}
+	for name, p := range Base {
+		l.patterns[name] = p
+	}
+	return l
+}
+
+// Add defines (or redefines) the pattern called name, which may refer to
+// other patterns.  References are resolved when a pattern is compiled.
+func (l *Library) Add(name, pattern string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("grok: bad pattern name %q", name)
+	}
+	l.patterns[name] = pattern
+	return nil
+}
+
+// A Pattern is a compiled grok pattern.
+type Pattern struct {
+	re    *regexp.Regexp
+	types map[string]string // Conversion for each typed field
+}
+
+var (
+	// reference matches %{NAME}, %{NAME:field} and %{NAME:field:type}.
+	reference = regexp.MustCompile(`%\{([^}:]*)(?::([^}:]*))?(?::([^}:]*))?\}`)
+
+	namePattern = regexp.MustCompile(`^\w+$`)
+)
+
+// Compile expands the references in pattern using the patterns of l and
+// compiles the result.  An error is returned for a reference to an
+// unknown pattern, a cycle of references, a field name that is not a
+// valid group name, or an unknown type.
+func (l *Library) Compile(pattern string) (*Pattern, error) {
+	p := &Pattern{types: map[string]string{}}
+	expanded, err := l.expand(pattern, p.types, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.re, err = regexp.Compile(expanded); err != nil {
+		return nil, fmt.Errorf("grok: %w", err)
+	}
+	return p, nil
+}
+
+// MustCompile is like Compile but panics if pattern cannot be compiled.
+func (l *Library) MustCompile(pattern string) *Pattern {
+	p, err := l.Compile(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Compile compiles pattern using the patterns in Base.
+func Compile(pattern string) (*Pattern, error) {
+	return New().Compile(pattern)
+}
+
+// MustCompile is like Compile but panics if pattern cannot be compiled.
+func MustCompile(pattern string) *Pattern {
+	return New().MustCompile(pattern)
+}
+
+// expand replaces the references in pattern, recording field types in
+// types.  stack holds the names of the patterns being expanded.
+func (l *Library) expand(pattern string, types map[string]string, stack []string) (string, error) {
+	var b strings.Builder
+	last := 0
+	for _, m := range reference.FindAllStringSubmatchIndex(pattern, -1) {
+		b.WriteString(pattern[last:m[0]])
+		last = m[1]
+		name := pattern[m[2]:m[3]]
+		field, typ := "", ""
+		if m[4] >= 0 {
+			field = pattern[m[4]:m[5]]
+		}
+		if m[6] >= 0 {
+			typ = pattern[m[6]:m[7]]
+		}
+		def, ok := l.patterns[name]
+		if !ok {
+			return "", fmt.Errorf("grok: unknown pattern %q", name)
+		}
+		for _, s := range stack {
+			if s == name {
+				return "", fmt.Errorf("grok: pattern %q refers to itself", name)
+			}
+		}
+		sub, err := l.expand(def, types, append(stack, name))
+		if err != nil {
+			return "", err
+		}
+		if field == "" {
+			b.WriteString("(?:" + sub + ")")
+			continue
+		}
+		if !namePattern.MatchString(field) {
+			return "", fmt.Errorf("grok: bad field name %q", field)
+		}
+		switch typ {
+		case "":
+		case "int", "float":
+			types[field] = typ
+		default:
+			return "", fmt.Errorf("grok: unknown type %q for field %q", typ, field)
+		}
+		b.WriteString("(?P<" + field + ">" + sub + ")")
+	}
+	b.WriteString(pattern[last:])
+	return b.String(), nil
+}
+
+// Regexp returns the compiled regular expression, whose named groups
+// are the fields of p.
+func (p *Pattern) Regexp() *regexp.Regexp {
+	return p.re
+}
+
+// String returns the expanded regular expression.
+func (p *Pattern) String() string {
+	return p.re.String()
+}
+
+// Parse matches p against input and returns the fields that took part
+// in the match.  Fields declared with type "int" are converted to int64
+// and ones declared with "float" to float64; others are strings.  If a
+// field name is used more than once, the first participating group
+// wins.  Parse returns an error wrapping re.NotFound if p does not
+// match input.
+func (p *Pattern) Parse(input []byte) (map[string]interface{}, error) {
+	m := p.re.FindSubmatchIndex(input)
+	if m == nil {
+		return nil, fmt.Errorf("grok: pattern %q: %w", p.re, re.NotFound)
+	}
+	result := map[string]interface{}{}
+	for i, name := range p.re.SubexpNames() {
+		if name == "" || m[2*i] < 0 {
+			continue
+		}
+		if _, ok := result[name]; ok {
+			continue
+		}
+		text := string(input[m[2*i]:m[2*i+1]])
+		switch p.types[name] {
+		case "int":
+			v, err := strconv.ParseInt(text, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("grok: field %s: %w", name, err)
+			}
+			result[name] = v
+		case "float":
+			v, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("grok: field %s: %w", name, err)
+			}
+			result[name] = v
+		default:
+			result[name] = text
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package grok_test
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/grok"
+)
+
+func TestBase(t *testing.T) {
+	// Every base pattern must compile, and none may capture on its own.
+	for name := range grok.Base {
+		p, err := grok.Compile("%{" + name + "}")
+		if err != nil {
+			t.Errorf("pattern %s: %s", name, err)
+			continue
+		}
+		if n := p.Regexp().NumSubexp(); n != 0 && name != "COMMONAPACHELOG" && name != "COMBINEDAPACHELOG" {
+			t.Errorf("pattern %s has %d capture groups", name, n)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	for _, c := range []struct {
+		pattern string
+		input   string
+		match   string // Expected text of the entire match
+	}{
+		{"%{IPV4}", "ip 192.168.0.255 x", "192.168.0.255"},
+		{"%{IPV6}", "addr 2001:db8::1 x", "2001:db8::1"},
+		{"%{IP} ", "from 10.1.2.3 to", "10.1.2.3 "},
+		{"%{HOSTPORT}", "connect db.example.com:5432", "db.example.com:5432"},
+		{"%{TIMESTAMP_ISO8601}", "at 2024-01-15T10:20:30.5Z done", "2024-01-15T10:20:30.5Z"},
+		{"%{HTTPDATE}", "[10/Oct/2000:13:55:36 -0700]", "10/Oct/2000:13:55:36 -0700"},
+		{"%{UUID}", "id=123e4567-e89b-12d3-a456-426614174000", "123e4567-e89b-12d3-a456-426614174000"},
+		{"%{LOGLEVEL}", "[WARN] disk", "WARN"},
+		{"%{URI}", "see https://user@example.com:8080/a/b?c=d now", "https://user@example.com:8080/a/b?c=d"},
+		{"%{QUOTEDSTRING}", `say "hi \"there\"" ok`, `"hi \"there\""`},
+	} {
+		p, err := grok.Compile(c.pattern)
+		if err != nil {
+			t.Errorf("Compile(%q): %s", c.pattern, err)
+			continue
+		}
+		if got := p.Regexp().FindString(c.input); got != c.match {
+			t.Errorf("%s in %q matched %q; expected %q", c.pattern, c.input, got, c.match)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	p := grok.MustCompile("%{COMMONAPACHELOG}")
+	line := []byte(`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`)
+	got, err := p.Parse(line)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]interface{}{
+		"clientip":    "127.0.0.1",
+		"ident":       "-",
+		"auth":        "frank",
+		"timestamp":   "10/Oct/2000:13:55:36 -0700",
+		"verb":        "GET",
+		"request":     "/apache_pb.gif",
+		"httpversion": "1.0",
+		"response":    int64(200),
+		"bytes":       int64(2326),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse = %v; expected %v", got, want)
+	}
+	if _, err := p.Parse([]byte("junk")); !errors.Is(err, re.NotFound) {
+		t.Errorf("Parse error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+
+	f := grok.MustCompile(`took %{NUMBER:secs:float}s`)
+	if got, err := f.Parse([]byte("took 1.5s")); err != nil || got["secs"] != 1.5 {
+		t.Errorf("float field = %v, %v", got, err)
+	}
+	i := grok.MustCompile(`n=%{NUMBER:n:int}`)
+	if _, err := i.Parse([]byte("n=1.5")); err == nil {
+		t.Errorf("Parse of non-integer int field succeeded unexpectedly")
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	p := grok.MustCompile(`%{IP:client} %{WORD:method} %{URIPATHPARAM:path} %{INT:status}`)
+	var entry struct {
+		Client string `re:"client"`
+		Method string `re:"method"`
+		Path   string `re:"path"`
+		Status int    `re:"status"`
+	}
+	if err := re.Unmarshal(p.Regexp(), []byte("10.0.0.1 GET /x?y=1 404"), &entry); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if entry.Client != "10.0.0.1" || entry.Method != "GET" || entry.Path != "/x?y=1" || entry.Status != 404 {
+		t.Errorf("Unmarshal = %+v", entry)
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	l := grok.New()
+	if err := l.Add("REQID", `req-%{POSINT}`); err != nil {
+		t.Fatal(err)
+	}
+	p, err := l.Compile(`%{REQID:id}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, _ := p.Parse([]byte("got req-42 ok")); got["id"] != "req-42" {
+		t.Errorf("Parse = %v", got)
+	}
+	if _, err := grok.Compile(`%{REQID}`); err == nil {
+		t.Errorf("library pattern leaked into Base")
+	}
+
+	l.Add("A", `a%{B}`)
+	l.Add("B", `b%{A}`)
+	for _, bad := range []string{`%{NOPE}`, `%{A}`, `%{INT:bad-name}`, `%{INT:n:bool}`, `%{INT}(`} {
+		if _, err := l.Compile(bad); err == nil {
+			t.Errorf("Compile(%q) succeeded unexpectedly", bad)
+		}
+	}
+	if err := l.Add("bad name", "x"); err == nil {
+		t.Errorf("Add with bad name succeeded unexpectedly")
+	}
+	if !regexp.MustCompile(`^\(\?P<n>`).MatchString(grok.MustCompile(`%{INT:n}`).String()) {
+		t.Errorf("String() = %s", grok.MustCompile(`%{INT:n}`))
+	}
+}
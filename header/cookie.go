@@ -0,0 +1,164 @@
+package header
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+// A Cookie is one name=value pair from a Cookie header value.
+type Cookie struct {
+	Name  string
+	Value string
+}
+
+// Cookies is a parsed Cookie header value, such as "a=1; b=2".
+type Cookies []Cookie
+
+// ParseCookies splits a Cookie header value into its cookies.  Values
+// are returned as is, except that surrounding double quotes are
+// removed; malformed pairs (ones without "=" or a name) are skipped.
+func ParseCookies(s string) Cookies {
+	var cookies Cookies
+	for _, pair := range strings.Split(s, ";") {
+		name, value, ok := splitPair(pair)
+		if !ok || name == "" {
+			continue
+		}
+		cookies = append(cookies, Cookie{Name: name, Value: trimQuotes(value)})
+	}
+	return cookies
+}
+
+// Get returns the value of the first cookie with the given name.
+func (c Cookies) Get(name string) (string, bool) {
+	for _, x := range c {
+		if x.Name == name {
+			return x.Value, true
+		}
+	}
+	return "", false
+}
+
+// UnmarshalText parses b with ParseCookies and stores the result in c.
+func (c *Cookies) UnmarshalText(b []byte) error {
+	*c = ParseCookies(string(b))
+	return nil
+}
+
+// SetCookie is a parsed Set-Cookie header value, such as
+// "id=a3fWa; Expires=Wed, 21 Oct 2015 07:28:00 GMT; Secure; HttpOnly".
+type SetCookie struct {
+	Name  string
+	Value string
+
+	Domain   string
+	Path     string
+	SameSite string // As written, e.g., "Lax"; empty if absent
+
+	// Expires is the zero Time if the attribute is absent or cannot be
+	// parsed (in which case the attribute is listed in Unparsed).
+	Expires time.Time
+
+	// MaxAge is zero if the attribute is absent.  Max-Age values of
+	// zero or less, which ask for the cookie to be deleted, are stored
+	// as a negative duration.
+	MaxAge time.Duration
+
+	Secure      bool
+	HttpOnly    bool
+	Partitioned bool
+
+	// Unparsed holds the attributes that were not recognized or could
+	// not be parsed, as written.
+	Unparsed []string
+}
+
+// expiresLayouts lists the date formats seen in Expires attributes.
+var expiresLayouts = []string{
+	time.RFC1123,
+	"Mon, 02-Jan-2006 15:04:05 MST",
+	time.RFC850,
+	time.ANSIC,
+	"Mon, 02 Jan 06 15:04:05 MST",
+}
+
+// ParseSetCookie parses a Set-Cookie header value.  Attribute names are
+// matched without regard to case.  It returns an error if the value does
+// not start with a name=value pair.
+func ParseSetCookie(s string) (SetCookie, error) {
+	parts := strings.Split(s, ";")
+	name, value, ok := splitPair(parts[0])
+	if !ok || name == "" {
+		return SetCookie{}, errors.New("header: Set-Cookie value does not start with name=value")
+	}
+	c := SetCookie{Name: name, Value: trimQuotes(value)}
+	for _, p := range parts[1:] {
+		attr, val, _ := splitPair(p)
+		switch strings.ToLower(attr) {
+		case "":
+			continue
+		case "domain":
+			c.Domain = strings.TrimPrefix(val, ".")
+		case "path":
+			c.Path = val
+		case "samesite":
+			c.SameSite = val
+		case "expires":
+			if err := re.Time(&c.Expires, expiresLayouts...)([]byte(val)); err != nil {
+				c.Unparsed = append(c.Unparsed, strings.TrimSpace(p))
+			}
+		case "max-age":
+			secs, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				c.Unparsed = append(c.Unparsed, strings.TrimSpace(p))
+				continue
+			}
+			if secs <= 0 {
+				c.MaxAge = -time.Second
+			} else {
+				c.MaxAge = time.Duration(secs) * time.Second
+			}
+		case "secure":
+			c.Secure = true
+		case "httponly":
+			c.HttpOnly = true
+		case "partitioned":
+			c.Partitioned = true
+		default:
+			c.Unparsed = append(c.Unparsed, strings.TrimSpace(p))
+		}
+	}
+	return c, nil
+}
+
+// UnmarshalText parses b with ParseSetCookie and stores the result in c.
+func (c *SetCookie) UnmarshalText(b []byte) error {
+	x, err := ParseSetCookie(string(b))
+	if err != nil {
+		return err
+	}
+	*c = x
+	return nil
+}
+
+// splitPair splits "name=value" (with optional surrounding spaces) into
+// its trimmed parts.  ok is false if there is no "=".
+func splitPair(s string) (name, value string, ok bool) {
+	i := strings.IndexByte(s, '=')
+	if i < 0 {
+		return strings.TrimSpace(s), "", false
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:]), true
+}
+
+// trimQuotes removes a pair of surrounding double quotes from s.
+func trimQuotes(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package header_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/header"
+)
+
+func TestParseCookies(t *testing.T) {
+	got := header.ParseCookies(` a=1; b="two words" ;junk; =x; c=`)
+	want := header.Cookies{{"a", "1"}, {"b", "two words"}, {"c", ""}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCookies = %q; expected %q", got, want)
+	}
+	if v, ok := got.Get("b"); !ok || v != "two words" {
+		t.Errorf("Get(b) = %q, %v", v, ok)
+	}
+	if _, ok := got.Get("z"); ok {
+		t.Errorf("Get(z) succeeded unexpectedly")
+	}
+}
+
+func TestParseSetCookie(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  header.SetCookie
+	}{
+		{
+			"id=a3fWa; Expires=Wed, 21 Oct 2015 07:28:00 GMT; Secure; HttpOnly",
+			header.SetCookie{
+				Name: "id", Value: "a3fWa",
+				Expires: time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC),
+				Secure:  true, HttpOnly: true,
+			},
+		},
+		{
+			`sess="x y"; max-age=3600; domain=.example.com; path=/; SameSite=Lax; Partitioned; Priority=High`,
+			header.SetCookie{
+				Name: "sess", Value: "x y",
+				MaxAge: time.Hour, Domain: "example.com", Path: "/", SameSite: "Lax",
+				Partitioned: true, Unparsed: []string{"Priority=High"},
+			},
+		},
+		{
+			"gone=; Max-Age=0; Expires=Thursday, 01-Jan-70 00:00:00 GMT",
+			header.SetCookie{
+				Name: "gone", MaxAge: -time.Second,
+				Expires: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			"x=1; Expires=someday; Max-Age=soon",
+			header.SetCookie{
+				Name: "x", Value: "1",
+				Unparsed: []string{"Expires=someday", "Max-Age=soon"},
+			},
+		},
+	} {
+		got, err := header.ParseSetCookie(c.input)
+		if err != nil {
+			t.Errorf("ParseSetCookie(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		if !got.Expires.Equal(c.want.Expires) {
+			t.Errorf("ParseSetCookie(%q).Expires = %v; expected %v", c.input, got.Expires, c.want.Expires)
+		}
+		got.Expires, c.want.Expires = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseSetCookie(%q) = %+v; expected %+v", c.input, got, c.want)
+		}
+	}
+
+	if _, err := header.ParseSetCookie("Secure; HttpOnly"); err == nil {
+		t.Errorf("ParseSetCookie without name=value succeeded unexpectedly")
+	}
+}
+
+func TestCookieScan(t *testing.T) {
+	var cookies header.Cookies
+	var set header.SetCookie
+	r := regexp.MustCompile(`^Cookie: (.*)\nSet-Cookie: (.*)$`)
+	if err := re.Scan(r, []byte("Cookie: a=1; b=2\nSet-Cookie: c=3; Secure"), &cookies, &set); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(cookies) != 2 || set.Name != "c" || !set.Secure {
+		t.Errorf("Scan extracted %+v, %+v", cookies, set)
+	}
+}
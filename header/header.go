@@ -0,0 +1,16 @@
+/*
+Package header provides lenient parsers for the values of common HTTP and
+MIME header fields, such as Content-Type, Content-Disposition,
+User-Agent, Cookie and Set-Cookie.  The parsers are meant for analyzing
+header values found in logs and other text, which frequently deviate
+from the relevant RFCs; where the standard library parsers (e.g.,
+mime.ParseMediaType) reject such values, the parsers here recover as
+much information as they can.
+
+The parsed types implement encoding.TextUnmarshaler, so pointers to them
+can be passed directly as outputs to re.Scan:
+
+	var ct header.MediaType
+	err := re.Scan(regexp.MustCompile(`(?i)^Content-Type:\s*(.*)$`), line, &ct)
+*/
+package header
@@ -0,0 +1,19 @@
+//go:build go1.23
+
+package header
+
+import "iter"
+
+// Products returns an iterator over the products of User-Agent value s,
+// as split by ParseUserAgent, without building the whole UserAgent.
+func Products(s string) iter.Seq[Product] {
+	return func(yield func(Product) bool) {
+		for rest := s; ; {
+			p, next, ok := nextProduct(rest)
+			if !ok || !yield(p) {
+				return
+			}
+			rest = next
+		}
+	}
+}
@@ -0,0 +1,22 @@
+//go:build go1.23
+
+package header_test
+
+import (
+	"testing"
+
+	"github.com/ghemawat/re/header"
+)
+
+func TestProducts(t *testing.T) {
+	var names []string
+	for p := range header.Products("A/1 (x) B/2 C/3") {
+		names = append(names, p.Name)
+		if p.Name == "B" {
+			break
+		}
+	}
+	if len(names) != 2 || names[0] != "A" || names[1] != "B" {
+		t.Errorf("Products yielded %q; expected [A B]", names)
+	}
+}
@@ -0,0 +1,148 @@
+package header
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MediaType holds a parsed Content-Type or Content-Disposition value,
+// such as `text/html; charset=utf-8` or `attachment; filename="a.txt"`.
+type MediaType struct {
+	// Type is the lower-cased media type (e.g., "text/html") or
+	// disposition type (e.g., "attachment").
+	Type string
+
+	// Params holds the parameters, keyed by lower-cased name.  Values
+	// encoded according to RFC 2231 (e.g., filename*=UTF-8''%E2%82%AC),
+	// including ones split into numbered continuations, are decoded
+	// and stored under the plain parameter name.
+	Params map[string]string
+}
+
+// paramPattern matches one parameter: a name, optionally followed by a
+// value that is either quoted (possibly missing its closing quote) or
+// runs to the next semicolon.
+var paramPattern = regexp.MustCompile(`([^\s=;]+)\s*(?:=\s*("(?:[^"\\]|\\.)*"?|[^;]*))?`)
+
+// continuationPattern matches the name of an RFC 2231 parameter.
+var continuationPattern = regexp.MustCompile(`^(.*?)\*(?:(\d+)\*?)?$`)
+
+// ParseMediaType parses a Content-Type or Content-Disposition value.
+// Unlike mime.ParseMediaType, it tolerates empty parameters, missing
+// closing quotes, whitespace around "=", unquoted values containing
+// special characters, parameters without values (which are ignored),
+// and duplicate parameters (the first one wins).  An error is returned
+// only if v has no type at all.
+func ParseMediaType(v string) (MediaType, error) {
+	typ, rest := v, ""
+	if i := strings.IndexByte(v, ';'); i >= 0 {
+		typ, rest = v[:i], v[i+1:]
+	}
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	if typ == "" {
+		return MediaType{}, errors.New("header: no media type")
+	}
+	m := MediaType{Type: typ, Params: map[string]string{}}
+
+	type part struct {
+		n       int
+		value   string
+		encoded bool
+	}
+	parts := map[string][]part{} // RFC 2231 parameters
+	for _, p := range paramPattern.FindAllStringSubmatch(rest, -1) {
+		if !strings.Contains(p[0], "=") {
+			continue // Parameter without a value
+		}
+		name, value := strings.ToLower(p[1]), unquote(strings.TrimSpace(p[2]))
+		if c := continuationPattern.FindStringSubmatch(name); c != nil {
+			n := 0
+			if c[2] != "" {
+				n, _ = strconv.Atoi(c[2])
+			}
+			encoded := strings.HasSuffix(name, "*")
+			parts[c[1]] = append(parts[c[1]], part{n, value, encoded})
+			continue
+		}
+		if _, ok := m.Params[name]; !ok {
+			m.Params[name] = value
+		}
+	}
+	for name, ps := range parts {
+		sort.SliceStable(ps, func(i, j int) bool { return ps[i].n < ps[j].n })
+		var charset string
+		var b strings.Builder
+		for i, p := range ps {
+			if !p.encoded {
+				b.WriteString(p.value)
+				continue
+			}
+			value := p.value
+			if i == 0 {
+				// charset'language'value
+				if f := strings.SplitN(value, "'", 3); len(f) == 3 {
+					charset, value = strings.ToLower(f[0]), f[2]
+				}
+			}
+			b.WriteString(percentDecode(value, charset))
+		}
+		// Decoded RFC 2231 values take precedence over plain ones.
+		m.Params[name] = b.String()
+	}
+	return m, nil
+}
+
+// UnmarshalText parses b with ParseMediaType and stores the result in m.
+func (m *MediaType) UnmarshalText(b []byte) error {
+	x, err := ParseMediaType(string(b))
+	if err != nil {
+		return err
+	}
+	*m = x
+	return nil
+}
+
+// unquote removes the quotes and backslash escapes from a quoted
+// string, tolerating a missing closing quote.  Other strings are
+// returned unchanged.
+func unquote(s string) string {
+	if !strings.HasPrefix(s, `"`) {
+		return s
+	}
+	s = s[1:]
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			return b.String()
+		case c == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// percentDecode decodes %XX escapes in s, interpreting the result in
+// the given charset: ISO-8859-1 is converted to UTF-8, and everything
+// else is taken as UTF-8.  Malformed escapes are left undecoded.
+func percentDecode(s, charset string) string {
+	d, err := url.PathUnescape(s)
+	if err != nil {
+		return s
+	}
+	if charset == "iso-8859-1" || charset == "latin1" {
+		r := make([]rune, len(d))
+		for i := 0; i < len(d); i++ {
+			r[i] = rune(d[i])
+		}
+		return string(r)
+	}
+	return d
+}
@@ -0,0 +1,61 @@
+package header_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/header"
+)
+
+func TestParseMediaType(t *testing.T) {
+	for _, c := range []struct {
+		input  string
+		typ    string
+		params map[string]string
+	}{
+		{"text/html", "text/html", map[string]string{}},
+		{"Text/HTML; Charset=UTF-8", "text/html", map[string]string{"charset": "UTF-8"}},
+		{`attachment; filename="a b.txt"`, "attachment", map[string]string{"filename": "a b.txt"}},
+		{`attachment; filename="a \"q\".txt"`, "attachment", map[string]string{"filename": `a "q".txt`}},
+
+		// Malformed variants.
+		{"text/plain;; charset = utf-8 ;", "text/plain", map[string]string{"charset": "utf-8"}},
+		{`attachment; filename="unterminated.txt`, "attachment", map[string]string{"filename": "unterminated.txt"}},
+		{"attachment; filename=a b/c.txt", "attachment", map[string]string{"filename": "a b/c.txt"}},
+		{"text/plain; flag; charset=x; charset=y", "text/plain", map[string]string{"charset": "x"}},
+
+		// RFC 2231.
+		{`attachment; filename*=UTF-8''%E2%82%AC%20rates.txt`, "attachment", map[string]string{"filename": "€ rates.txt"}},
+		{`attachment; filename*=iso-8859-1'en'%A3.txt`, "attachment", map[string]string{"filename": "£.txt"}},
+		{`attachment; filename="fallback.txt"; filename*=UTF-8''%C3%A9.txt`, "attachment", map[string]string{"filename": "é.txt"}},
+		{`message/external-body; url*1="/b.txt"; url*0="ftp://h/a"`, "message/external-body",
+			map[string]string{"url": "ftp://h/a/b.txt"}},
+		{`attachment; name*0*=UTF-8''%C3%A9; name*1="t"; name*2*=%C3%A9`, "attachment", map[string]string{"name": "été"}},
+	} {
+		m, err := header.ParseMediaType(c.input)
+		if err != nil {
+			t.Errorf("ParseMediaType(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		if m.Type != c.typ || !reflect.DeepEqual(m.Params, c.params) {
+			t.Errorf("ParseMediaType(%q) = %q, %q; expected %q, %q", c.input, m.Type, m.Params, c.typ, c.params)
+		}
+	}
+
+	if _, err := header.ParseMediaType(" ; charset=utf-8"); err == nil {
+		t.Errorf("ParseMediaType without a type succeeded unexpectedly")
+	}
+}
+
+func TestMediaTypeScan(t *testing.T) {
+	var m header.MediaType
+	r := regexp.MustCompile(`(?i)^Content-Type:\s*(.*)$`)
+	if err := re.Scan(r, []byte("content-type: text/html; charset=utf-8"), &m); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if m.Type != "text/html" || m.Params["charset"] != "utf-8" {
+		t.Errorf("Scan extracted %+v", m)
+	}
+}
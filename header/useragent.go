@@ -0,0 +1,88 @@
+package header
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ProductPattern matches one segment of a User-Agent value: either a
+// product token with an optional version (e.g., "Firefox/115.0"), whose
+// name and version are captured by the first two groups, or a
+// parenthesized comment (e.g., "(X11; Linux x86_64)"), whose text is
+// captured by the third group.  Comments may contain one level of
+// nested parentheses, and a comment missing its closing parenthesis
+// extends to the end of the input.
+var ProductPattern = regexp.MustCompile(`([^\s/()]+)(?:/([^\s()]*))?|\(((?:[^()]|\([^()]*\))*)\)?`)
+
+// A Product is a product segment of a User-Agent value together with
+// the comments that follow it.  For example, "Mozilla/5.0 (X11; Linux
+// x86_64)" has Name "Mozilla", Version "5.0" and Comments ["X11; Linux
+// x86_64"].
+type Product struct {
+	Name     string
+	Version  string
+	Comments []string
+}
+
+// UserAgent is a parsed User-Agent value: its products in order.
+type UserAgent []Product
+
+// ParseUserAgent splits a User-Agent value into its products.  It never
+// fails: text that is not a product or comment is skipped, and comments
+// that precede the first product are attached to a Product with an
+// empty Name.  This is a lightweight splitter, not a device database;
+// it makes no attempt to guess the browser or operating system.
+func ParseUserAgent(s string) UserAgent {
+	var ua UserAgent
+	for rest := s; ; {
+		p, next, ok := nextProduct(rest)
+		if !ok {
+			return ua
+		}
+		ua = append(ua, p)
+		rest = next
+	}
+}
+
+// nextProduct returns the first product in s along with the remainder
+// of s after it.
+func nextProduct(s string) (Product, string, bool) {
+	var p Product
+	found := false
+	for {
+		loc := ProductPattern.FindStringSubmatchIndex(s)
+		if loc == nil {
+			return p, "", found
+		}
+		if loc[2] >= 0 {
+			// A product token.
+			if found {
+				return p, s[loc[0]:], true
+			}
+			p.Name = s[loc[2]:loc[3]]
+			if loc[4] >= 0 {
+				p.Version = s[loc[4]:loc[5]]
+			}
+		} else {
+			p.Comments = append(p.Comments, strings.TrimSpace(s[loc[6]:loc[7]]))
+		}
+		found = true
+		s = s[loc[1]:]
+	}
+}
+
+// Find returns the first product with the given name, ignoring case.
+func (ua UserAgent) Find(name string) (Product, bool) {
+	for _, p := range ua {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Product{}, false
+}
+
+// UnmarshalText parses b with ParseUserAgent and stores the result in ua.
+func (ua *UserAgent) UnmarshalText(b []byte) error {
+	*ua = ParseUserAgent(string(b))
+	return nil
+}
@@ -0,0 +1,57 @@
+package header_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/header"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  header.UserAgent
+	}{
+		{"", nil},
+		{"curl/8.1.2", header.UserAgent{{Name: "curl", Version: "8.1.2"}}},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			header.UserAgent{
+				{Name: "Mozilla", Version: "5.0", Comments: []string{"Windows NT 10.0; Win64; x64"}},
+				{Name: "AppleWebKit", Version: "537.36", Comments: []string{"KHTML, like Gecko"}},
+				{Name: "Chrome", Version: "120.0.0.0"},
+				{Name: "Safari", Version: "537.36"},
+			},
+		},
+		{
+			"(leading) Bot (compatible; +http://x/(info)) Other/ (unterminated",
+			header.UserAgent{
+				{Comments: []string{"leading"}},
+				{Name: "Bot", Comments: []string{"compatible; +http://x/(info)"}},
+				{Name: "Other", Comments: []string{"unterminated"}},
+			},
+		},
+	} {
+		got := header.ParseUserAgent(c.input)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseUserAgent(%q) = %+v; expected %+v", c.input, got, c.want)
+		}
+	}
+}
+
+func TestUserAgentScan(t *testing.T) {
+	var ua header.UserAgent
+	r := regexp.MustCompile(`"([^"]*)"$`)
+	if err := re.Scan(r, []byte(`GET / "Mozilla/5.0 (X11) Firefox/115.0"`), &ua); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	p, ok := ua.Find("firefox")
+	if !ok || p.Version != "115.0" {
+		t.Errorf("Find(firefox) = %+v, %v; expected version 115.0", p, ok)
+	}
+	if _, ok := ua.Find("chrome"); ok {
+		t.Errorf("Find(chrome) succeeded unexpectedly")
+	}
+}
@@ -0,0 +1,91 @@
+package re
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Quotef builds a regular expression from a format and arguments in
+// the style of fmt.Sprintf, but escapes the arguments with
+// regexp.QuoteMeta so that they match literally, no matter what
+// characters they contain:
+//
+//	user := "alice.smith"
+//	pattern := re.Quotef(`^%s logged in from (\S+)$`, user)
+//	// pattern is `^alice\.smith logged in from (\S+)$`
+//
+// The verbs of fmt are supported, and each is escaped after
+// formatting, except for %p, which inserts its argument (a string, or a
+// *regexp.Regexp) verbatim, as a subpattern wrapped in a non-capturing
+// group.  The format itself is not escaped.  As with
+// fmt.Sprintf, missing and extra arguments are reported in the result,
+// e.g., "%!s(MISSING)".
+func Quotef(format string, args ...interface{}) string {
+	s, _ := quotef(format, args)
+	return s
+}
+
+// quotef implements Quotef, also returning an error if the number of
+// arguments does not match format.
+func quotef(format string, args []interface{}) (string, error) {
+	var err error
+	var b strings.Builder
+	n := 0 // Index of the next argument
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' {
+			b.WriteByte(c)
+			continue
+		}
+		// Find the end of the verb, skipping flags, width and precision.
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.", format[j]) >= 0 {
+			j++
+		}
+		if j == len(format) {
+			b.WriteString(format[i:])
+			break
+		}
+		verb := format[j]
+		switch {
+		case verb == '%':
+			b.WriteByte('%')
+		case n >= len(args):
+			fmt.Fprintf(&b, "%%!%c(MISSING)", verb)
+			err = fmt.Errorf("re.Compilef: missing argument for %%%c in %q", verb, format)
+		case verb == 'p':
+			fmt.Fprintf(&b, "(?:%s)", args[n])
+			n++
+		default:
+			b.WriteString(regexp.QuoteMeta(fmt.Sprintf(format[i:j+1], args[n])))
+			n++
+		}
+		i = j
+	}
+	if n < len(args) {
+		fmt.Fprintf(&b, "%%!(EXTRA %d)", len(args)-n)
+		err = fmt.Errorf("re.Compilef: %d extra arguments for %q", len(args)-n, format)
+	}
+	return b.String(), err
+}
+
+// Compilef is like regexp.Compile(Quotef(format, args...)), except that
+// it returns an error if the number of arguments does not match format.
+func Compilef(format string, args ...interface{}) (*regexp.Regexp, error) {
+	pattern, err := quotef(format, args)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(pattern)
+}
+
+// MustCompilef is like Compilef but panics if the pattern cannot be
+// compiled.
+func MustCompilef(format string, args ...interface{}) *regexp.Regexp {
+	reg, err := Compilef(format, args...)
+	if err != nil {
+		panic(err)
+	}
+	return reg
+}
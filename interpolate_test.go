@@ -0,0 +1,53 @@
+package re_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestQuotef(t *testing.T) {
+	for _, c := range []struct {
+		got, want string
+	}{
+		{re.Quotef(`^%s logged in$`, "alice.smith"), `^alice\.smith logged in$`},
+		{re.Quotef(`id=%d|%s`, 42, "a+b*(c)"), `id=42|a\+b\*\(c\)`},
+		{re.Quotef(`%q`, `"x"`), `"\\"x\\""`},
+		{re.Quotef(`%5.2f%%`, 3.14159), ` 3\.14%`},
+		{re.Quotef(`%s-%p`, "v1.0", `\d+`), `v1\.0-(?:\d+)`},
+		{re.Quotef(`%p|%p`, regexp.MustCompile(`a|b`), "c"), `(?:a|b)|(?:c)`},
+		{re.Quotef(`%s %s`, "x"), `x %!s(MISSING)`},
+		{re.Quotef(`%s`, "x", "y"), `x%!(EXTRA 1)`},
+		{re.Quotef(`trailing %`), `trailing %`},
+	} {
+		if c.got != c.want {
+			t.Errorf("got %q; expected %q", c.got, c.want)
+		}
+	}
+}
+
+func TestCompilef(t *testing.T) {
+	reg, err := re.Compilef(`^%s=(%p)$`, "a.b[0]", `\d+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := re.Scan(reg, []byte("a.b[0]=17"), &n); err != nil || n != 17 {
+		t.Errorf("Scan = %d, %v; expected 17", n, err)
+	}
+	if reg.MatchString("axb[0]=17") {
+		t.Errorf("interpolated value was not escaped")
+	}
+	for _, bad := range [][]interface{}{{"%s %s", "x"}, {"%s", "x", "y"}, {"%p", "("}} {
+		if _, err := re.Compilef(bad[0].(string), bad[1:]...); err == nil {
+			t.Errorf("Compilef(%q) succeeded", bad)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustCompilef of a malformed pattern did not panic")
+		}
+	}()
+	re.MustCompilef("%p", "(")
+}
@@ -0,0 +1,81 @@
+//go:build go1.23
+
+package re
+
+import (
+	"iter"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Matches returns an iterator over the successive non-overlapping
+// matches of re in input, for use in range-over-func loops:
+//
+//	for m, err := range re.Matches(reg, input) {
+//		if err != nil {
+//			return err
+//		}
+//		var host string
+//		var port int
+//		if err := m.Scan(&host, &port); err != nil {
+//			return err
+//		}
+//		Process(host, port)
+//	}
+//
+// Matching an in-memory input cannot fail, so the yielded error is
+// currently always nil; it is part of the signature so that callers
+// handle errors uniformly if Matches is extended to other sources.
+func Matches(re *regexp.Regexp, input []byte) iter.Seq2[Match, error] {
+	return MatchesN(re, input, -1)
+}
+
+// MatchesN is like Matches, but yields at most n matches if n >= 0.
+// All matches are found before the first is yielded, so breaking out of
+// the loop early does not avoid searching the rest of input; use
+// MatchesN to bound that work when only the first few matches of a large
+// input are needed.
+func MatchesN(re *regexp.Regexp, input []byte, n int) iter.Seq2[Match, error] {
+	return func(yield func(Match, error) bool) {
+		for _, matches := range re.FindAllSubmatchIndex(input, n) {
+			if !yield(Match{re: re, input: input, matches: matches}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// OverlappingMatches is like Matches, but yields overlapping matches:
+// after each match, the search resumes one character after the start of
+// the match rather than at its end.  E.g., the matches of `\w\w\w` in
+// "abcd" are "abc" and "bcd", which suits k-mer counting and other
+// sliding-window analyses.  At each starting position, only the match
+// that re prefers is yielded.
+//
+// Each search matches the rest of input as if it were a new input, so ^
+// and \A match at the resumption point, and \b does not see the
+// preceding character.
+func OverlappingMatches(re *regexp.Regexp, input []byte) iter.Seq2[Match, error] {
+	return func(yield func(Match, error) bool) {
+		for pos := 0; pos <= len(input); {
+			matches := re.FindSubmatchIndex(input[pos:])
+			if matches == nil {
+				return
+			}
+			for i := range matches {
+				if matches[i] >= 0 {
+					matches[i] += pos
+				}
+			}
+			if !yield(Match{re: re, input: input, matches: matches}, nil) {
+				return
+			}
+			start := matches[0]
+			if start == len(input) {
+				return
+			}
+			_, size := utf8.DecodeRune(input[start:])
+			pos = start + size
+		}
+	}
+}
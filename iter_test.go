@@ -0,0 +1,119 @@
+//go:build go1.23
+
+package re_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestMatches(t *testing.T) {
+	pattern := regexp.MustCompile(`(\w+):(\d+)?`)
+	input := []byte("a:1 b: c:3")
+
+	var hosts []string
+	var ports []int
+	var spans [][]re.Span
+	for m, err := range re.Matches(pattern, input) {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var host string
+		if err := m.Scan(&host); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		hosts = append(hosts, host)
+		spans = append(spans, append([]re.Span{m.Span()}, m.Spans()...))
+
+		var port int
+		if err := m.Scan(nil, &port); err == nil {
+			ports = append(ports, port)
+		}
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %v; expected %v", hosts, want)
+	}
+	if want := []int{1, 3}; !reflect.DeepEqual(ports, want) {
+		t.Errorf("ports = %v; expected %v", ports, want)
+	}
+	want := [][]re.Span{
+		{{0, 3}, {0, 1}, {2, 3}},
+		{{4, 6}, {4, 5}, {-1, -1}},
+		{{7, 10}, {7, 8}, {9, 10}},
+	}
+	if !reflect.DeepEqual(spans, want) {
+		t.Errorf("spans = %v; expected %v", spans, want)
+	}
+
+	// Stopping early.
+	n := 0
+	for m := range re.Matches(pattern, input) {
+		if string(m.Bytes()) != "a:1" {
+			t.Errorf("first match is %q; expected %q", m.Bytes(), "a:1")
+		}
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("loop ran %d times; expected 1", n)
+	}
+}
+
+func TestMatchesN(t *testing.T) {
+	var got []string
+	for m, err := range re.MatchesN(regexp.MustCompile(`\w+`), []byte("a b c d"), 2) {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		got = append(got, string(m.Bytes()))
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchesN = %v; expected %v", got, want)
+	}
+}
+
+func TestOverlappingMatches(t *testing.T) {
+	for _, c := range []struct {
+		pattern, input string
+		want           []string
+	}{
+		{`\w\w\w`, "abcd", []string{"abc", "bcd"}},
+		{`aa`, "aaaa", []string{"aa", "aa", "aa"}},
+		{`\d+`, "12 3", []string{"12", "2", "3"}},
+		{`é.`, "éééé", []string{"éé", "éé", "éé"}},
+		{`x*`, "ab", []string{"", "", ""}},
+		{`z`, "abc", nil},
+	} {
+		var got []string
+		for m, err := range re.OverlappingMatches(regexp.MustCompile(c.pattern), []byte(c.input)) {
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got = append(got, string(m.Bytes()))
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("OverlappingMatches(%s, %q) = %q; expected %q", c.pattern, c.input, got, c.want)
+		}
+	}
+
+	// Spans and sub-matches are relative to the whole input.
+	var spans []re.Span
+	var words []string
+	for m := range re.OverlappingMatches(regexp.MustCompile(`(\w)\w`), []byte("xyz")) {
+		var w string
+		if err := m.Scan(&w); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		words = append(words, w)
+		spans = append(spans, m.Span())
+		if len(spans) == 2 {
+			break // Stopping early must be safe.
+		}
+	}
+	if want := []re.Span{{0, 2}, {1, 3}}; !reflect.DeepEqual(spans, want) || !reflect.DeepEqual(words, []string{"x", "y"}) {
+		t.Errorf("spans = %v, words = %q", spans, words)
+	}
+}
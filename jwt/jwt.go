@@ -0,0 +1,74 @@
+/*
+Package jwt helps inspect JSON Web Tokens found in text such as logs.
+It decodes the header and payload of a token so that they can be
+examined, but it does NOT verify the signature: nothing decoded by this
+package should be trusted.  Use a real JWT library to authenticate
+tokens.
+
+Decode returns an output argument for re.Scan:
+
+	var claims struct {
+		Subject string `json:"sub"`
+		Expires int64  `json:"exp"`
+	}
+	err := re.Scan(regexp.MustCompile(`Bearer (`+jwt.Pattern+`)`), line, jwt.Decode(nil, &claims))
+*/
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Pattern is a regular expression (without capture groups) matching
+// text shaped like a JWT: three base64url segments separated by dots,
+// the first two of which start with the encoding of `{"`.
+const Pattern = `eyJ[A-Za-z0-9_-]*\.eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`
+
+// Header holds the registered JOSE header fields of a token.
+type Header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// Decode returns an output argument for re.Scan that expects the
+// sub-match to be a JWT in compact serialization, and decodes its
+// header and payload as JSON into header and payload respectively (as
+// json.Unmarshal would).  Either may be nil to skip decoding that part.
+// The signature segment is checked to be valid base64url but is not
+// verified.  An error is returned if the sub-match does not have three
+// segments or a segment cannot be decoded.
+func Decode(header, payload interface{}) func([]byte) error {
+	return func(b []byte) error {
+		parts := bytes.Split(b, []byte("."))
+		if len(parts) != 3 {
+			return fmt.Errorf("jwt: token has %d segments; expected 3", len(parts))
+		}
+		for i, p := range parts {
+			raw, err := base64.RawURLEncoding.DecodeString(string(bytes.TrimRight(p, "=")))
+			if err != nil {
+				return fmt.Errorf("jwt: segment %d: %w", i, err)
+			}
+			var dst interface{}
+			switch i {
+			case 0:
+				dst = header
+			case 1:
+				dst = payload
+			}
+			if dst == nil {
+				continue
+			}
+			if !json.Valid(raw) {
+				return fmt.Errorf("jwt: segment %d is not JSON", i)
+			}
+			if err := json.Unmarshal(raw, dst); err != nil {
+				return fmt.Errorf("jwt: segment %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/jwt"
+)
+
+func encode(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func TestDecode(t *testing.T) {
+	token := encode(`{"alg":"HS256","typ":"JWT"}`) + "." + encode(`{"sub":"alice","exp":1700000000}`) + "." + encode("sig")
+	line := []byte("Authorization: Bearer " + token + " from 10.0.0.1")
+	r := regexp.MustCompile(`Bearer (` + jwt.Pattern + `)`)
+
+	var h jwt.Header
+	var claims struct {
+		Subject string `json:"sub"`
+		Expires int64  `json:"exp"`
+	}
+	if err := re.Scan(r, line, jwt.Decode(&h, &claims)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if h.Algorithm != "HS256" || h.Type != "JWT" {
+		t.Errorf("header is %+v", h)
+	}
+	if claims.Subject != "alice" || claims.Expires != 1700000000 {
+		t.Errorf("claims are %+v", claims)
+	}
+
+	all := regexp.MustCompile(`^(.*)$`)
+	for _, bad := range []string{
+		"a.b",
+		encode(`{"alg":"none"}`) + ".!!!." + encode("sig"),
+		encode(`{"alg":"none"}`) + "." + encode(`not json`) + ".",
+		encode(`{"alg":"none"}`) + "." + encode(`{"sub":17}`) + ".",
+	} {
+		if err := re.Scan(all, []byte(bad), jwt.Decode(nil, &claims)); err == nil {
+			t.Errorf("Decode(%q) succeeded unexpectedly", bad)
+		}
+	}
+
+	// Skipping both parts only validates the encoding.
+	if err := re.Scan(all, []byte(token), jwt.Decode(nil, nil)); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if regexp.MustCompile(jwt.Pattern).MatchString("not.a.token") {
+		t.Errorf("Pattern matched a non-token")
+	}
+}
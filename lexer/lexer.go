@@ -0,0 +1,207 @@
+/*
+Package lexer splits text into tokens described by regular expressions,
+for the small languages (filter expressions, query strings,
+configuration values) that are too irregular for a single pattern:
+
+	var l lexer.Lexer
+	l.Skip(regexp.MustCompile(`\s+`))
+	l.Rule("number", regexp.MustCompile(`\d+`), lexer.Int)
+	l.Rule("ident", regexp.MustCompile(`[a-z]\w*`), nil)
+	l.Rule("op", regexp.MustCompile(`[-+/*()]`), nil)
+	tokens, err := l.Tokens([]byte("x + 42"))
+
+At each position, every rule is tried; the longest match wins (even
+among the alternatives of a single rule), and ties are resolved in favor
+of the rule added first, as in lex.  Rules are therefore usually added
+from most to least specific, e.g., keywords before identifiers.
+
+The tokens can then be parsed with a grammar built from the combinators
+Seq, Alt, Repeat, Optional and Map; see Parser.
+*/
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/ghemawat/re"
+)
+
+// A Token is one lexical element of the input.
+type Token struct {
+	Kind  string      // Kind of the rule that matched
+	Span  re.Span     // Extent of the token in the input
+	Text  []byte      // Text of the token; an alias of the input
+	Value interface{} // Result of the rule's value function, or nil
+}
+
+// A Lexer holds a list of rules.  The zero value is a Lexer without
+// rules.  A Lexer may be used concurrently once all rules are added.
+type Lexer struct {
+	rules []rule
+}
+
+type rule struct {
+	kind    string
+	pattern *regexp.Regexp // Anchored at the start of the input
+	value   func([]byte) (interface{}, error)
+	skip    bool
+}
+
+// Rule adds a rule producing tokens of the given kind from the text
+// matched by pattern.  If value is non-nil, it is called with the text
+// of each token, and its result is stored in Token.Value; an error from
+// value stops lexing.  Empty matches are ignored.
+func (l *Lexer) Rule(kind string, pattern *regexp.Regexp, value func([]byte) (interface{}, error)) error {
+	p, err := anchor(pattern)
+	if err != nil {
+		return err
+	}
+	l.rules = append(l.rules, rule{kind: kind, pattern: p, value: value})
+	return nil
+}
+
+// Skip adds a rule whose matches, such as whitespace or comments, are
+// consumed without producing tokens.
+func (l *Lexer) Skip(pattern *regexp.Regexp) error {
+	p, err := anchor(pattern)
+	if err != nil {
+		return err
+	}
+	l.rules = append(l.rules, rule{pattern: p, skip: true})
+	return nil
+}
+
+// anchor returns a version of pattern that only matches at the start of
+// the input, and prefers the longest match, as lex does.
+func anchor(pattern *regexp.Regexp) (*regexp.Regexp, error) {
+	p, err := regexp.Compile(`\A(?:` + pattern.String() + `)`)
+	if err != nil {
+		return nil, err
+	}
+	p.Longest()
+	return p, nil
+}
+
+// Tokens returns all the tokens of input.  See Scanner for errors.
+func (l *Lexer) Tokens(input []byte) ([]Token, error) {
+	var tokens []Token
+	s := l.Scan(input)
+	for s.Next() {
+		tokens = append(tokens, s.Token())
+	}
+	return tokens, s.Err()
+}
+
+// Scan returns a Scanner that produces the tokens of input one by one.
+func (l *Lexer) Scan(input []byte) *Scanner {
+	return &Scanner{rules: l.rules, input: input}
+}
+
+// A Scanner produces the tokens of an input one by one:
+//
+//	s := l.Scan(input)
+//	for s.Next() {
+//		Process(s.Token())
+//	}
+//	if err := s.Err(); err != nil {
+//		return err
+//	}
+//
+// Lexing stops with an error if no rule matches at some position, or if
+// a value function fails.  Errors report the offending offset.
+type Scanner struct {
+	rules []rule
+	input []byte
+	pos   int
+	tok   Token
+	err   error
+}
+
+// Next advances to the next token, returning false at the end of the
+// input or on error.
+func (s *Scanner) Next() bool {
+	for s.err == nil && s.pos < len(s.input) {
+		best, end := -1, s.pos
+		for i, r := range s.rules {
+			if m := r.pattern.FindIndex(s.input[s.pos:]); m != nil && s.pos+m[1] > end {
+				best, end = i, s.pos+m[1]
+			}
+		}
+		if best < 0 {
+			s.err = &Error{Offset: s.pos, Input: s.input}
+			return false
+		}
+		r := s.rules[best]
+		start := s.pos
+		s.pos = end
+		if r.skip {
+			continue
+		}
+		text := s.input[start:end]
+		s.tok = Token{Kind: r.kind, Span: re.Span{Start: start, End: end}, Text: text}
+		if r.value != nil {
+			v, err := r.value(text)
+			if err != nil {
+				s.err = fmt.Errorf("lexer: %s at offset %d: %w", r.kind, start, err)
+				return false
+			}
+			s.tok.Value = v
+		}
+		return true
+	}
+	return false
+}
+
+// Token returns the token found by the last call to Next.
+func (s *Scanner) Token() Token {
+	return s.tok
+}
+
+// Err returns the error, if any, that stopped the Scanner.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// Pos returns the offset of the first byte of input not yet consumed.
+func (s *Scanner) Pos() int {
+	return s.pos
+}
+
+// An Error reports that no rule matches the input at Offset.
+type Error struct {
+	Offset int
+	Input  []byte
+}
+
+func (e *Error) Error() string {
+	rest := e.Input[e.Offset:]
+	if len(rest) > 20 {
+		rest = rest[:20]
+	}
+	return fmt.Sprintf("lexer: no rule matches at offset %d: %q", e.Offset, rest)
+}
+
+// Int is a value function for Rule that parses the token text as an
+// integer following Go syntax (e.g., "42", "0x2a"), producing an int64.
+func Int(b []byte) (interface{}, error) {
+	return strconv.ParseInt(string(b), 0, 64)
+}
+
+// Float is a value function for Rule that parses the token text as a
+// float64.
+func Float(b []byte) (interface{}, error) {
+	return strconv.ParseFloat(string(b), 64)
+}
+
+// String is a value function for Rule that unquotes a token written as
+// a Go string or character literal, producing a string.
+func String(b []byte) (interface{}, error) {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return nil, errors.New("bad string literal")
+	}
+	return s, nil
+}
@@ -0,0 +1,114 @@
+package lexer_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re/lexer"
+)
+
+func newLexer(t *testing.T) *lexer.Lexer {
+	var l lexer.Lexer
+	for _, err := range []error{
+		l.Skip(regexp.MustCompile(`\s+|#.*`)),
+		l.Rule("keyword", regexp.MustCompile(`and|or|not`), nil),
+		l.Rule("ident", regexp.MustCompile(`[a-z_]\w*`), nil),
+		l.Rule("float", regexp.MustCompile(`\d+\.\d*`), lexer.Float),
+		l.Rule("int", regexp.MustCompile(`\d+|0x[0-9a-f]+`), lexer.Int),
+		l.Rule("string", regexp.MustCompile(`"(?:[^"\\]|\\.)*"`), lexer.String),
+		l.Rule("op", regexp.MustCompile(`[<>!=]=|[<>()]`), nil),
+	} {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	return &l
+}
+
+// format renders tokens compactly for comparison.
+func format(tokens []lexer.Token) string {
+	var parts []string
+	for _, tok := range tokens {
+		s := fmt.Sprintf("%s:%s@%d", tok.Kind, tok.Text, tok.Span.Start)
+		if tok.Value != nil {
+			s += fmt.Sprintf("=%#v", tok.Value)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestTokens(t *testing.T) {
+	l := newLexer(t)
+	for _, c := range []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"  # only a comment", ""},
+		{"x", "ident:x@0"},
+		// Longest match: "order" is an identifier, not "or" + "der".
+		{"order or x", "ident:order@0 keyword:or@6 ident:x@9"},
+		// Ties go to the earlier rule: "and" is a keyword.
+		{"and", "keyword:and@0"},
+		{"n >= 10 and r < 2.5", "ident:n@0 op:>=@2 int:10@5=10 keyword:and@8 ident:r@12 op:<@14 float:2.5@16=2.5"},
+		{`name == "a \"b\""`, `ident:name@0 op:==@5 string:"a \"b\""@8="a \"b\""`},
+		{"0x1f", "int:0x1f@0=31"},
+	} {
+		tokens, err := l.Tokens([]byte(c.input))
+		if err != nil {
+			t.Errorf("Tokens(%q): unexpected error: %s", c.input, err)
+		} else if got := format(tokens); got != c.want {
+			t.Errorf("Tokens(%q) = %s; expected %s", c.input, got, c.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	l := newLexer(t)
+	tokens, err := l.Tokens([]byte("x == $y"))
+	var lexErr *lexer.Error
+	if !errors.As(err, &lexErr) || lexErr.Offset != 5 {
+		t.Errorf("Tokens error was %v; expected a lexer.Error at offset 5", err)
+	}
+	if got := format(tokens); got != "ident:x@0 op:==@2" {
+		t.Errorf("tokens before the error = %s", got)
+	}
+	if _, err := l.Tokens([]byte("99999999999999999999")); err == nil || !strings.Contains(err.Error(), "int at offset 0") {
+		t.Errorf("Tokens of out of range int: got error %v", err)
+	}
+}
+
+func TestScanner(t *testing.T) {
+	l := newLexer(t)
+	s := l.Scan([]byte("a b"))
+	if !s.Next() || s.Token().Kind != "ident" || s.Pos() != 1 {
+		t.Fatalf("first token = %+v at %d", s.Token(), s.Pos())
+	}
+	if !s.Next() || string(s.Token().Text) != "b" || s.Pos() != 3 {
+		t.Fatalf("second token = %+v at %d", s.Token(), s.Pos())
+	}
+	if s.Next() || s.Err() != nil {
+		t.Errorf("Next at end = true or error %v", s.Err())
+	}
+}
+
+func TestEmptyMatches(t *testing.T) {
+	var l lexer.Lexer
+	l.Rule("a", regexp.MustCompile(`a*`), nil)
+	if _, err := l.Tokens([]byte("aab")); err == nil {
+		t.Errorf("Tokens succeeded although only an empty match is possible at offset 2")
+	}
+}
+
+func TestFlags(t *testing.T) {
+	var l lexer.Lexer
+	l.Rule("select", regexp.MustCompile(`(?i)select`), nil)
+	l.Skip(regexp.MustCompile(` `))
+	if tokens, err := l.Tokens([]byte("SELECT select")); err != nil || len(tokens) != 2 {
+		t.Errorf("Tokens = %s, %v", format(tokens), err)
+	}
+}
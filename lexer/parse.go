@@ -0,0 +1,247 @@
+package lexer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Parser recognizes a sequence of tokens and produces a value from
+// them.  Parsers are built from Kind and Lit, which match single tokens,
+// with Seq, Alt, Repeat, Optional and Map:
+//
+//	// cmp := ident op (int | string)
+//	cmp := lexer.Map(
+//		lexer.Seq(lexer.Kind("ident"), lexer.Kind("op"), lexer.Alt(lexer.Kind("int"), lexer.Kind("string"))),
+//		func(v interface{}) (interface{}, error) {
+//			x := v.([]interface{})
+//			return Cmp{Field: x[0].(string), Op: x[1].(string), Value: x[2]}, nil
+//		})
+//	ast, err := l.Parse(cmp, []byte(`size > 10`))
+//
+// Alt commits to the first alternative that succeeds, and Repeat and
+// Optional consume as much as they can, as in a parsing expression
+// grammar; there is no backtracking into a parser that has succeeded.
+// Recursive grammars refer to parsers not yet built with Ref.
+type Parser struct {
+	parse func(st *parseState, i int) (interface{}, int, bool)
+}
+
+// parseState is shared by the parsers applied to one token sequence.
+// It records the furthest position at which a token did not match, and
+// the tokens that would have been accepted there, for error messages.
+type parseState struct {
+	tokens   []Token
+	err      error // Error from a Map function, which stops parsing
+	far      int
+	expected []string
+}
+
+// fail records that what was expected at token i, and returns false.
+func (st *parseState) fail(i int, what string) bool {
+	if i > st.far {
+		st.far, st.expected = i, nil
+	}
+	if i == st.far {
+		for _, e := range st.expected {
+			if e == what {
+				return false
+			}
+		}
+		st.expected = append(st.expected, what)
+	}
+	return false
+}
+
+// Kind returns a Parser that matches one token of the given kind.  Its
+// value is the Value of the token, or the token text as a string if the
+// Value is nil.
+func Kind(kind string) Parser {
+	return Parser{func(st *parseState, i int) (interface{}, int, bool) {
+		if i < len(st.tokens) && st.tokens[i].Kind == kind {
+			tok := st.tokens[i]
+			if tok.Value != nil {
+				return tok.Value, i + 1, true
+			}
+			return string(tok.Text), i + 1, true
+		}
+		return nil, i, st.fail(i, kind)
+	}}
+}
+
+// Lit returns a Parser that matches one token whose text is text, such
+// as a keyword or an operator.  Its value is text.
+func Lit(text string) Parser {
+	return Parser{func(st *parseState, i int) (interface{}, int, bool) {
+		if i < len(st.tokens) && string(st.tokens[i].Text) == text {
+			return text, i + 1, true
+		}
+		return nil, i, st.fail(i, strconv.Quote(text))
+	}}
+}
+
+// Seq returns a Parser that matches each of ps in turn.  Its value is a
+// []interface{} holding their values.
+func Seq(ps ...Parser) Parser {
+	return Parser{func(st *parseState, i int) (interface{}, int, bool) {
+		values := make([]interface{}, len(ps))
+		start := i
+		for j, p := range ps {
+			v, next, ok := p.parse(st, i)
+			if !ok {
+				return nil, start, false
+			}
+			values[j], i = v, next
+		}
+		return values, i, true
+	}}
+}
+
+// Alt returns a Parser that matches the first of ps that matches, and
+// has its value.
+func Alt(ps ...Parser) Parser {
+	return Parser{func(st *parseState, i int) (interface{}, int, bool) {
+		for _, p := range ps {
+			if v, next, ok := p.parse(st, i); ok {
+				return v, next, true
+			}
+			if st.err != nil {
+				break
+			}
+		}
+		return nil, i, false
+	}}
+}
+
+// Repeat returns a Parser that matches p as many times as possible, and
+// fails if that is fewer than min times.  Its value is a []interface{}
+// holding the values of the matches.
+func Repeat(p Parser, min int) Parser {
+	return Parser{func(st *parseState, i int) (interface{}, int, bool) {
+		values := []interface{}{}
+		start := i
+		for {
+			v, next, ok := p.parse(st, i)
+			if !ok || next == i {
+				break
+			}
+			values, i = append(values, v), next
+		}
+		if st.err != nil || len(values) < min {
+			return nil, start, false
+		}
+		return values, i, true
+	}}
+}
+
+// Optional returns a Parser that matches p if possible, and otherwise
+// matches nothing, with the value nil.
+func Optional(p Parser) Parser {
+	return Parser{func(st *parseState, i int) (interface{}, int, bool) {
+		v, next, ok := p.parse(st, i)
+		if !ok {
+			return nil, i, st.err == nil
+		}
+		return v, next, true
+	}}
+}
+
+// Map returns a Parser that matches p and whose value is f applied to
+// the value of p.  It is typically used to build the nodes of a syntax
+// tree.  An error from f stops parsing.
+func Map(p Parser, f func(v interface{}) (interface{}, error)) Parser {
+	return Parser{func(st *parseState, i int) (interface{}, int, bool) {
+		v, next, ok := p.parse(st, i)
+		if !ok {
+			return nil, i, false
+		}
+		r, err := f(v)
+		if err != nil {
+			st.err = fmt.Errorf("lexer: at offset %d: %w", st.offset(i), err)
+			return nil, i, false
+		}
+		return r, next, true
+	}}
+}
+
+// Ref returns a Parser that behaves like *p at the time it is used,
+// which allows recursive grammars:
+//
+//	var expr lexer.Parser
+//	term := lexer.Alt(lexer.Kind("int"), lexer.Seq(lexer.Lit("("), lexer.Ref(&expr), lexer.Lit(")")))
+//	expr = lexer.Seq(term, lexer.Repeat(lexer.Seq(lexer.Lit("+"), term), 0))
+func Ref(p *Parser) Parser {
+	return Parser{func(st *parseState, i int) (interface{}, int, bool) {
+		return p.parse(st, i)
+	}}
+}
+
+// Parse applies p to tokens, which it must match entirely, and returns
+// the value of p.  If tokens do not match, the error is a *SyntaxError
+// describing the furthest position reached.
+func (p Parser) Parse(tokens []Token) (interface{}, error) {
+	end := 0
+	if n := len(tokens); n > 0 {
+		end = tokens[n-1].Span.End
+	}
+	return p.parseAll(tokens, end)
+}
+
+// Parse splits input into tokens and parses them with p; see
+// Parser.Parse.
+func (l *Lexer) Parse(p Parser, input []byte) (interface{}, error) {
+	tokens, err := l.Tokens(input)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseAll(tokens, len(input))
+}
+
+// parseAll implements Parse; end is the offset of the end of the input.
+func (p Parser) parseAll(tokens []Token, end int) (interface{}, error) {
+	st := &parseState{tokens: tokens}
+	v, next, ok := p.parse(st, 0)
+	if st.err != nil {
+		return nil, st.err
+	}
+	if ok && next == len(tokens) {
+		return v, nil
+	}
+	if ok {
+		st.fail(next, "end of input")
+	}
+	e := &SyntaxError{Offset: end, Expected: st.expected}
+	if st.far < len(tokens) {
+		e.Offset = tokens[st.far].Span.Start
+		e.Found = string(tokens[st.far].Text)
+	}
+	return nil, e
+}
+
+// offset returns the offset in the input of token i.
+func (st *parseState) offset(i int) int {
+	if i < len(st.tokens) {
+		return st.tokens[i].Span.Start
+	}
+	if n := len(st.tokens); n > 0 {
+		return st.tokens[n-1].Span.End
+	}
+	return 0
+}
+
+// A SyntaxError reports that the tokens of an input do not match a
+// Parser.
+type SyntaxError struct {
+	Offset   int      // Offset in the input of the unexpected token
+	Expected []string // Kinds and quoted texts that would be accepted
+	Found    string   // Text of the unexpected token; empty at the end
+}
+
+func (e *SyntaxError) Error() string {
+	found := "end of input"
+	if e.Found != "" {
+		found = strconv.Quote(e.Found)
+	}
+	return fmt.Sprintf("lexer: syntax error at offset %d: expected %s; found %s",
+		e.Offset, strings.Join(e.Expected, " or "), found)
+}
@@ -0,0 +1,132 @@
+package lexer_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re/lexer"
+)
+
+// A filter expression: comparisons combined with and, or, not and
+// parentheses.
+type (
+	cmp struct {
+		field, op string
+		value     interface{}
+	}
+	not   struct{ x interface{} }
+	logic struct {
+		op   string
+		x, y interface{}
+	}
+)
+
+// filter returns a parser for filter expressions.
+func filter() lexer.Parser {
+	var expr lexer.Parser
+	comparison := lexer.Map(
+		lexer.Seq(lexer.Kind("ident"), lexer.Kind("op"), lexer.Alt(lexer.Kind("int"), lexer.Kind("float"), lexer.Kind("string"))),
+		func(v interface{}) (interface{}, error) {
+			x := v.([]interface{})
+			if x[1] == "(" || x[1] == ")" {
+				return nil, fmt.Errorf("%v is not a comparison", x[1])
+			}
+			return cmp{x[0].(string), x[1].(string), x[2]}, nil
+		})
+	paren := lexer.Map(lexer.Seq(lexer.Lit("("), lexer.Ref(&expr), lexer.Lit(")")),
+		func(v interface{}) (interface{}, error) { return v.([]interface{})[1], nil })
+	var term lexer.Parser
+	term = lexer.Alt(
+		comparison,
+		paren,
+		lexer.Map(lexer.Seq(lexer.Lit("not"), lexer.Ref(&term)),
+			func(v interface{}) (interface{}, error) { return not{v.([]interface{})[1]}, nil }),
+	)
+	// chain parses x (op x)*, associating to the left.
+	chain := func(x lexer.Parser, op string) lexer.Parser {
+		return lexer.Map(lexer.Seq(x, lexer.Repeat(lexer.Seq(lexer.Lit(op), x), 0)),
+			func(v interface{}) (interface{}, error) {
+				s := v.([]interface{})
+				result := s[0]
+				for _, r := range s[1].([]interface{}) {
+					result = logic{op, result, r.([]interface{})[1]}
+				}
+				return result, nil
+			})
+	}
+	expr = chain(chain(term, "and"), "or")
+	return expr
+}
+
+func TestParse(t *testing.T) {
+	l := newLexer(t)
+	p := filter()
+	for _, c := range []struct {
+		input string
+		want  string
+	}{
+		{`n >= 10`, `{n >= 10}`},
+		{`n >= 10 and r < 2.5 or name == "x"`, `{or {and {n >= 10} {r < 2.5}} {name == x}}`},
+		{`not (a == 1 or b == 2) and c != 3`, `{and {{or {a == 1} {b == 2}}} {c != 3}}`},
+		{`not not a == 1`, `{{{a == 1}}}`},
+	} {
+		v, err := l.Parse(p, []byte(c.input))
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		if got := fmt.Sprint(v); got != c.want {
+			t.Errorf("Parse(%q) = %s; expected %s", c.input, got, c.want)
+		}
+	}
+	// Values of typed tokens are kept.
+	v, err := l.Parse(p, []byte(`x == 0x10`))
+	if c, ok := v.(cmp); err != nil || !ok || c.value != int64(16) {
+		t.Errorf("Parse(x == 0x10) = %#v, %v; expected an int64 value", v, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	l := newLexer(t)
+	p := filter()
+	for _, c := range []struct {
+		input  string
+		offset int
+		want   string
+	}{
+		{`a ==`, 4, "expected int or float or string; found end of input"},
+		{`(a == 1`, 7, `expected "and" or "or" or ")"; found end of input`},
+		{`a == 1 b`, 7, `expected "and" or "or" or end of input; found "b"`},
+		{``, 0, `expected ident or "(" or "not"; found end of input`},
+	} {
+		_, err := l.Parse(p, []byte(c.input))
+		var se *lexer.SyntaxError
+		if !errors.As(err, &se) || se.Offset != c.offset || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("Parse(%q) error = %v; expected %q at offset %d", c.input, err, c.want, c.offset)
+		}
+	}
+
+	// Errors from Map functions stop parsing, and lexing errors are
+	// passed on.
+	if _, err := l.Parse(p, []byte(`a ( 1`)); err == nil || !strings.Contains(err.Error(), "( is not a comparison") {
+		t.Errorf("Parse with failing Map: error %v", err)
+	}
+	var lexErr *lexer.Error
+	if _, err := l.Parse(p, []byte(`a == $`)); !errors.As(err, &lexErr) {
+		t.Errorf("Parse with bad token: error %v; expected a lexer.Error", err)
+	}
+
+	// Repeat with a minimum.
+	tokens, err := l.Tokens([]byte("a b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lexer.Repeat(lexer.Kind("ident"), 3).Parse(tokens); err == nil {
+		t.Errorf("Repeat(ident, 3) of two identifiers succeeded unexpectedly")
+	}
+	if v, err := lexer.Seq(lexer.Repeat(lexer.Kind("ident"), 2), lexer.Optional(lexer.Kind("int"))).Parse(tokens); err != nil || fmt.Sprint(v) != "[[a b] <nil>]" {
+		t.Errorf("Seq(Repeat, Optional) = %v, %v", v, err)
+	}
+}
@@ -0,0 +1,132 @@
+//go:build !re_minimal
+
+package re
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// A LineScanner reads text line by line and stores the sub-matches of
+// each line that matches a regular expression into outputs bound ahead
+// of time, as Bind does.  Lines that do not match are skipped:
+//
+//	var host string
+//	var port int
+//	ls, err := re.NewLineScanner(file, regexp.MustCompile(`^(\w+):(\d+)$`), &host, &port)
+//	if err != nil {
+//		return err
+//	}
+//	for ls.Scan() {
+//		Process(ls.LineNumber(), host, port)
+//	}
+//	return ls.Err()
+//
+// Lines are terminated by "\n", optionally preceded by "\r", neither of
+// which is part of the line.  There is no limit on the length of a line.
+// Since the line buffer is reused, []byte outputs (and the result of
+// Line) are only valid until the next call to Scan.
+type LineScanner struct {
+	r      *bufio.Reader
+	s      *Scanner
+	line   []byte
+	long   []byte // Buffer for lines longer than r's buffer
+	lineno int
+	err    error
+}
+
+// NewLineScanner returns a LineScanner that reads lines from r and
+// matches them against re, storing the sub-matches into output.  It
+// returns an error if the outputs cannot be bound to re; see Bind.
+func NewLineScanner(r io.Reader, re *regexp.Regexp, output ...interface{}) (*LineScanner, error) {
+	s, err := Bind(re, output...)
+	if err != nil {
+		return nil, err
+	}
+	return &LineScanner{r: bufio.NewReader(r), s: s}, nil
+}
+
+// Scan advances to the next line that matches, storing its sub-matches
+// into the outputs.  It returns false at the end of the input, or if an
+// error occurred (which is then reported by Err): a read error, or a
+// sub-match that cannot be parsed.
+func (l *LineScanner) Scan() bool {
+	for l.err == nil {
+		line, err := l.readLine()
+		if err != nil {
+			if err != io.EOF {
+				l.err = err
+			}
+			return false
+		}
+		l.line = line
+		l.lineno++
+		err = l.s.Scan(line)
+		if err == nil {
+			return true
+		}
+		if !errors.Is(err, NotFound) {
+			l.err = fmt.Errorf("line %d: %w", l.lineno, err)
+		}
+	}
+	return false
+}
+
+// Err returns the first error encountered by Scan, other than io.EOF.
+func (l *LineScanner) Err() error {
+	return l.err
+}
+
+// Line returns the line that was matched by the last call to Scan.
+func (l *LineScanner) Line() []byte {
+	return l.line
+}
+
+// LineNumber returns the number, counting from one, of the line that
+// was matched by the last call to Scan.
+func (l *LineScanner) LineNumber() int {
+	return l.lineno
+}
+
+// readLine returns the next line without its terminator.
+func (l *LineScanner) readLine() ([]byte, error) {
+	line, err := l.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		l.long = append(l.long[:0], line...)
+		for err == bufio.ErrBufferFull {
+			line, err = l.r.ReadSlice('\n')
+			l.long = append(l.long, line...)
+		}
+		line = l.long
+	}
+	if err == io.EOF && len(line) > 0 {
+		err = nil // Final line without a terminator
+	}
+	if err != nil {
+		return nil, err
+	}
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	return bytes.TrimSuffix(line, []byte("\r")), nil
+}
+
+// ScanLines reads r line by line, and for each line that matches re,
+// stores the sub-matches into output and calls fn with the line number
+// (counting from one).  Lines that do not match are skipped.  ScanLines
+// stops at the first error, whether returned by fn, by r, or from
+// parsing a sub-match; see LineScanner for details.
+func ScanLines(r io.Reader, re *regexp.Regexp, fn func(lineno int) error, output ...interface{}) error {
+	ls, err := NewLineScanner(r, re, output...)
+	if err != nil {
+		return err
+	}
+	for ls.Scan() {
+		if err := fn(ls.LineNumber()); err != nil {
+			return err
+		}
+	}
+	return ls.Err()
+}
@@ -0,0 +1,98 @@
+//go:build !re_minimal
+
+package re_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestLineScanner(t *testing.T) {
+	input := "# comment\r\na:1\r\n\nbb:22\nlast:333"
+	var host string
+	var port int
+	ls, err := re.NewLineScanner(strings.NewReader(input), regexp.MustCompile(`^(\w+):(\d+)$`), &host, &port)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got []string
+	for ls.Scan() {
+		got = append(got, fmt.Sprintf("%d %s %d %q", ls.LineNumber(), host, port, ls.Line()))
+	}
+	if err := ls.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expect := []string{`2 a 1 "a:1"`, `4 bb 22 "bb:22"`, `5 last 333 "last:333"`}
+	if strings.Join(got, ",") != strings.Join(expect, ",") {
+		t.Errorf("got %q, expected %q", got, expect)
+	}
+}
+
+func TestLineScannerLongLine(t *testing.T) {
+	long := strings.Repeat("x", 100000)
+	var s string
+	ls, err := re.NewLineScanner(strings.NewReader("a\n"+long+"\nb"), regexp.MustCompile(`^(x+)$`), &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ls.Scan() || s != long || ls.LineNumber() != 2 {
+		t.Errorf("long line not matched; line %d, length %d", ls.LineNumber(), len(s))
+	}
+	if ls.Scan() {
+		t.Errorf("unexpected match of line %d", ls.LineNumber())
+	}
+}
+
+func TestLineScannerErrors(t *testing.T) {
+	var n uint8
+	ls, err := re.NewLineScanner(strings.NewReader("1\n2\n300\n4\n"), regexp.MustCompile(`^(\d+)$`), &n)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	count := 0
+	for ls.Scan() {
+		count++
+	}
+	if count != 2 || ls.Err() == nil || !strings.Contains(ls.Err().Error(), "line 3") {
+		t.Errorf("got %d matches and error %v; expected 2 matches and a line 3 error", count, ls.Err())
+	}
+
+	if _, err := re.NewLineScanner(strings.NewReader(""), regexp.MustCompile(`(a)`), new(string), new(string)); err == nil {
+		t.Errorf("NewLineScanner with too many outputs succeeded unexpectedly")
+	}
+
+	ls, _ = re.NewLineScanner(errReader{errors.New("read failed")}, regexp.MustCompile(`(a)`), new(string))
+	if ls.Scan() || ls.Err() == nil {
+		t.Errorf("read error was not reported")
+	}
+}
+
+func TestScanLines(t *testing.T) {
+	var key, value string
+	var got []string
+	err := re.ScanLines(strings.NewReader("a=1\nskip\nb=2\n"), regexp.MustCompile(`^(\w+)=(\w+)$`), func(lineno int) error {
+		got = append(got, fmt.Sprintf("%d:%s=%s", lineno, key, value))
+		return nil
+	}, &key, &value)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Join(got, " ") != "1:a=1 3:b=2" {
+		t.Errorf("got %q", got)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = re.ScanLines(strings.NewReader("a=1\nb=2\n"), regexp.MustCompile(`^(\w+)=(\w+)$`), func(int) error {
+		calls++
+		return stop
+	}, &key, &value)
+	if err != stop || calls != 1 {
+		t.Errorf("got error %v after %d calls; expected %v after 1 call", err, calls, stop)
+	}
+}
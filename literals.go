@@ -0,0 +1,116 @@
+package re
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Literals returns a regular expression that matches any of the given
+// literal strings, for use in place of an alternation such as
+// `foo|bar|baz` built by hand:
+//
+//	reg := regexp.MustCompile(`\b(` + re.Literals(keywords...) + `)\b`)
+//
+// The words are escaped, duplicates are removed, and words sharing a
+// prefix are merged so that the prefix is matched once, e.g., "get",
+// "gets" and "put" become `(?:gets?|put)`, which keeps the regular
+// expression small for large sets of words.  Where several words match
+// at the same position, the longest one is preferred, so the result
+// contains no capturing groups and can be embedded in a larger pattern.
+// An empty list of words yields a pattern that matches nothing.
+func Literals(words ...string) string {
+	if len(words) == 0 {
+		return `[^\x00-\x{10FFFF}]`
+	}
+	root := &literalNode{}
+	for _, w := range words {
+		n := root
+		for _, r := range w {
+			child, ok := n.children[r]
+			if !ok {
+				if n.children == nil {
+					n.children = map[rune]*literalNode{}
+				}
+				child = &literalNode{}
+				n.children[r] = child
+			}
+			n = child
+		}
+		n.terminal = true
+	}
+	return root.pattern()
+}
+
+// CompileLiterals compiles the result of Literals into a regular
+// expression with a single sub-match holding the word that matched.
+func CompileLiterals(words ...string) (*regexp.Regexp, error) {
+	return regexp.Compile("(" + Literals(words...) + ")")
+}
+
+// A literalNode is a node in a trie of words.
+type literalNode struct {
+	children map[rune]*literalNode
+	terminal bool // Does a word end here?
+}
+
+// pattern returns a regular expression matching the suffixes of the
+// words below n.  An alternation is wrapped in a group, so the result
+// can be concatenated with other patterns.
+func (n *literalNode) pattern() string {
+	runes := make([]rune, 0, len(n.children))
+	for r := range n.children {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	// Use a character class if every child is the last rune of a word.
+	leaves := len(runes) > 1
+	for _, r := range runes {
+		if len(n.children[r].children) > 0 {
+			leaves = false
+		}
+	}
+	var body string
+	single := false // Is body a single atom that needs no group?
+	switch {
+	case len(runes) == 0:
+		return ""
+	case leaves:
+		var b strings.Builder
+		b.WriteByte('[')
+		for _, r := range runes {
+			b.WriteString(quoteClassRune(r))
+		}
+		b.WriteByte(']')
+		body, single = b.String(), true
+	default:
+		alts := make([]string, len(runes))
+		for i, r := range runes {
+			alts[i] = regexp.QuoteMeta(string(r)) + n.children[r].pattern()
+		}
+		body = strings.Join(alts, "|")
+		single = len(runes) == 1 && len(n.children[runes[0]].children) == 0
+	}
+	if n.terminal {
+		// The word ending here is a prefix of the others; prefer the
+		// longer ones by making the rest optional (and greedy).
+		if single {
+			return body + "?"
+		}
+		return "(?:" + body + ")?"
+	}
+	if len(runes) > 1 && !leaves {
+		return "(?:" + body + ")"
+	}
+	return body
+}
+
+// quoteClassRune returns r quoted for use inside a character class.
+func quoteClassRune(r rune) string {
+	switch r {
+	case '\\', ']', '[', '^', '-':
+		return `\` + string(r)
+	}
+	return string(r)
+}
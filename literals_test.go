@@ -0,0 +1,59 @@
+package re_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestLiterals(t *testing.T) {
+	for _, c := range []struct {
+		words []string
+		want  string
+	}{
+		{[]string{"get", "gets", "put"}, `(?:gets?|put)`},
+		{[]string{"b", "a", "c", "a"}, `[abc]`},
+		{[]string{"foo"}, `foo`},
+		{[]string{"a.", "a+", "a]", "a-"}, `a[+\-.\]]`},
+		{[]string{"a.b", "a+c", "a-"}, `a(?:\+c|-|\.b)`},
+		{[]string{"", "x"}, `x?`},
+		{[]string{"ab", "abcd", "abce"}, `ab(?:c[de])?`},
+		{[]string{"日本", "日本語"}, `日本語?`},
+		{[]string{"x(", "x(y"}, `x\(y?`},
+	} {
+		if got := re.Literals(c.words...); got != c.want {
+			t.Errorf("Literals(%q) = %s; expected %s", c.words, got, c.want)
+		}
+	}
+}
+
+func TestLiteralsMatch(t *testing.T) {
+	words := []string{"in", "int", "interface", "into", "is", "if", "i", "a.b", "(x)", `\`, "^"}
+	reg := regexp.MustCompile(`^` + re.Literals(words...) + `$`)
+	for _, w := range words {
+		if !reg.MatchString(w) {
+			t.Errorf("%s does not match %q", reg, w)
+		}
+	}
+	for _, w := range []string{"", "inte", "axb", "x", "iff", "in int"} {
+		if reg.MatchString(w) {
+			t.Errorf("%s matches %q", reg, w)
+		}
+	}
+
+	// The longest word is preferred.
+	reg, err := re.CompileLiterals(words...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	if err := re.Scan(reg, []byte("x interface{}"), &got); err != nil || got != "interface" {
+		t.Errorf("Scan = %q, %v; expected interface", got, err)
+	}
+
+	none := regexp.MustCompile(re.Literals())
+	if none.MatchString("") || none.MatchString("abc") {
+		t.Errorf("Literals() matched")
+	}
+}
@@ -0,0 +1,39 @@
+package re
+
+import "regexp"
+
+// Match describes a single match of a regular expression in an input.
+type Match struct {
+	re      *regexp.Regexp
+	input   []byte
+	matches []int // Result of FindSubmatchIndex
+}
+
+// Span returns the extent of the entire match.
+func (m Match) Span() Span {
+	return Span{Start: m.matches[0], End: m.matches[1]}
+}
+
+// Spans returns the extents of the sub-matches, in the same order in
+// which Scan assigns them to outputs; i.e., Spans()[i] is the extent of
+// the sub-match that Scan would store into output[i].  A sub-match that
+// did not participate in the match has a Span of {-1, -1}.
+func (m Match) Spans() []Span {
+	spans := make([]Span, len(m.matches)/2-1)
+	for i := range spans {
+		_, spans[i] = submatchAt(m.input, m.matches, i)
+	}
+	return spans
+}
+
+// Bytes returns the text of the entire match.  The result is an alias
+// of the input.
+func (m Match) Bytes() []byte {
+	return m.input[m.matches[0]:m.matches[1]]
+}
+
+// Scan stores the sub-matches of m into output following the same rules
+// as re.Scan.
+func (m Match) Scan(output ...interface{}) error {
+	return scanMatch(m.re, m.input, m.matches, output)
+}
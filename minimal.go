@@ -0,0 +1,26 @@
+//go:build re_minimal
+
+package re
+
+// The following are stubs for builds with the re_minimal tag, which
+// leave out the support for types from packages outside the core.
+
+func adapterAssigner(r interface{}) (assigner, bool) {
+	return nil, false
+}
+
+func sqlAssigner(r interface{}) (assigner, bool) {
+	return nil, false
+}
+
+func sqlScannerAssigner(r interface{}) (assigner, bool) {
+	return nil, false
+}
+
+func flagAssigner(r interface{}) (assigner, bool) {
+	return nil, false
+}
+
+func jsonUnquote(b []byte) (string, bool) {
+	return "", false
+}
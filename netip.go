@@ -0,0 +1,31 @@
+//go:build go1.18
+
+package re
+
+import "net/netip"
+
+// netipAssigner returns the assigner for r if r points to a type from
+// package net/netip.
+func netipAssigner(r interface{}) (assigner, bool) {
+	switch v := r.(type) {
+	case *netip.Addr:
+		return func(b []byte, _ Span) error {
+			a, err := netip.ParseAddr(string(b))
+			if err != nil {
+				return err
+			}
+			*v = a
+			return nil
+		}, true
+	case *netip.AddrPort:
+		return func(b []byte, _ Span) error {
+			a, err := netip.ParseAddrPort(string(b))
+			if err != nil {
+				return err
+			}
+			*v = a
+			return nil
+		}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,61 @@
+//go:build go1.18
+
+package re_test
+
+import (
+	"net/netip"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestNetip(t *testing.T) {
+	all := regexp.MustCompile(`^(.*)$`)
+	for _, c := range []struct {
+		input  string
+		result bool
+		want   netip.Addr
+	}{
+		{"10.0.0.1", true, netip.MustParseAddr("10.0.0.1")},
+		{"::1", true, netip.IPv6Loopback()},
+		{"10.0.0.256", false, netip.Addr{}},
+		{"", false, netip.Addr{}},
+	} {
+		var got netip.Addr
+		err := re.Scan(all, []byte(c.input), &got)
+		if !c.result {
+			if err == nil {
+				t.Errorf("Scan(%q) into netip.Addr succeeded unexpectedly", c.input)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("Scan(%q) into netip.Addr = %v, %v; expected %v", c.input, got, err, c.want)
+		}
+	}
+
+	for _, c := range []struct {
+		input  string
+		result bool
+		want   netip.AddrPort
+	}{
+		{"10.0.0.1:80", true, netip.MustParseAddrPort("10.0.0.1:80")},
+		{"[::1]:443", true, netip.MustParseAddrPort("[::1]:443")},
+		{"10.0.0.1:65536", false, netip.AddrPort{}},
+		{"10.0.0.1", false, netip.AddrPort{}},
+		{"", false, netip.AddrPort{}},
+	} {
+		var got netip.AddrPort
+		err := re.Scan(all, []byte(c.input), &got)
+		if !c.result {
+			if err == nil {
+				t.Errorf("Scan(%q) into netip.AddrPort succeeded unexpectedly", c.input)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("Scan(%q) into netip.AddrPort = %v, %v; expected %v", c.input, got, err, c.want)
+		}
+	}
+}
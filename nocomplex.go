@@ -0,0 +1,8 @@
+//go:build !go1.15
+
+package re
+
+// complexAssigner is a stub for releases without strconv.ParseComplex.
+func complexAssigner(r interface{}) (assigner, bool) {
+	return nil, false
+}
@@ -0,0 +1,8 @@
+//go:build !go1.18
+
+package re
+
+// netipAssigner is a stub for releases without package net/netip.
+func netipAssigner(r interface{}) (assigner, bool) {
+	return nil, false
+}
@@ -0,0 +1,174 @@
+/*
+Package patterns provides regular expressions for common kinds of text,
+such as email addresses, IP addresses, URLs and UUIDs, along with output
+arguments for re.Scan that convert matches to typed values.
+
+The patterns are strings without capture groups, each wrapped in a
+non-capturing group, so that they can be composed into larger
+expressions:
+
+	var id [16]byte
+	var when time.Time
+	r := regexp.MustCompile(`request (` + patterns.UUID + `) at (` + patterns.ISODateTime + `)`)
+	err := re.Scan(r, line, patterns.ParsedUUID(&id), patterns.ParsedTime(&when))
+
+The patterns find text of the right shape; where the shape alone is not
+enough (e.g., IPv6 addresses), the Parsed functions validate the match.
+*/
+package patterns
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Email matches an address such as "bob.smith+tag@mail.example.com".
+	// Quoted local parts and address literals are not supported.
+	Email = `(?:[A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@` + Hostname + `)`
+
+	// Hostname matches a DNS name with at least two labels, the last of
+	// which is alphabetic, e.g., "www.example.com".
+	Hostname = `(?:(?:[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)+[A-Za-z]{2,63}\b)`
+
+	// IPv4 matches a dotted-quad address with octets in [0, 255].
+	IPv4 = `(?:\b(?:(?:25[0-5]|2[0-4][0-9]|1[0-9][0-9]|[1-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1[0-9][0-9]|[1-9]?[0-9])\b)`
+
+	// IPv6 matches an IPv6 address in full or "::"-compressed form,
+	// optionally ending with an embedded IPv4 address.  It accepts a few
+	// malformed addresses (e.g., ones with too many groups), which
+	// ParsedIP rejects.
+	IPv6 = `(?:(?:[0-9A-Fa-f]{1,4}:){7}[0-9A-Fa-f]{1,4}` +
+		`|(?:[0-9A-Fa-f]{1,4}(?::[0-9A-Fa-f]{1,4}){0,6})?::(?:(?:[0-9A-Fa-f]{1,4}:){0,5}` + IPv4 + `|[0-9A-Fa-f]{1,4}(?::[0-9A-Fa-f]{1,4}){0,6})?)`
+
+	// IP matches an IPv4 or IPv6 address.
+	IP = `(?:` + IPv4 + `|` + IPv6 + `)`
+
+	// URL matches an absolute http, https or ftp URL.  Trailing
+	// punctuation that usually ends a sentence, such as "." or ")", is
+	// not included.
+	URL = `(?:(?i:https?|ftp)://[^\s<>"]*[^\s<>".,;:!?)\]}'])`
+
+	// UUID matches a UUID in its canonical hyphenated form.
+	UUID = `(?:\b[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}\b)`
+
+	// ISODate matches a calendar date such as "2024-01-15".
+	ISODate = `(?:\b` + isoDate + `\b)`
+
+	// ISODateTime matches an RFC 3339 timestamp such as
+	// "2024-01-15T10:20:30.5Z"; the time zone is optional, and a space
+	// may separate the date and time.
+	ISODateTime = `(?:\b` + isoDate + `[T ](?:[01][0-9]|2[0-3]):[0-5][0-9]:(?:[0-5][0-9]|60)(?:\.[0-9]+)?(?:[Zz]|[+-](?:[01][0-9]|2[0-3]):?[0-5][0-9])?)`
+
+	// MAC matches a 48-bit hardware address written as six pairs of hex
+	// digits separated by ":" or "-", or three groups of four separated
+	// by ".".
+	MAC = `\b(?:[0-9A-Fa-f]{2}(?::[0-9A-Fa-f]{2}){5}|[0-9A-Fa-f]{2}(?:-[0-9A-Fa-f]{2}){5}|[0-9A-Fa-f]{4}(?:\.[0-9A-Fa-f]{4}){2})\b`
+)
+
+// isoDate is ISODate without word boundaries.
+const isoDate = `[0-9]{4}-(?:0[1-9]|1[0-2])-(?:0[1-9]|[12][0-9]|3[01])`
+
+// ParsedEmail returns an output argument for re.Scan that parses the
+// sub-match with mail.ParseAddress (which also accepts forms such as
+// "Bob <bob@example.com>") and stores the result into *a.
+func ParsedEmail(a *mail.Address) func([]byte) error {
+	return func(b []byte) error {
+		v, err := mail.ParseAddress(string(b))
+		if err != nil {
+			return fmt.Errorf("patterns: bad email address %q: %w", b, err)
+		}
+		*a = *v
+		return nil
+	}
+}
+
+// ParsedIP returns an output argument for re.Scan that parses the
+// sub-match as an IPv4 or IPv6 address and stores it into *ip.
+func ParsedIP(ip *net.IP) func([]byte) error {
+	return func(b []byte) error {
+		v := net.ParseIP(string(b))
+		if v == nil {
+			return fmt.Errorf("patterns: bad IP address %q", b)
+		}
+		*ip = v
+		return nil
+	}
+}
+
+// ParsedURL returns an output argument for re.Scan that parses the
+// sub-match with url.Parse and stores the result into *u.
+func ParsedURL(u *url.URL) func([]byte) error {
+	return func(b []byte) error {
+		v, err := url.Parse(string(b))
+		if err != nil {
+			return fmt.Errorf("patterns: %w", err)
+		}
+		*u = *v
+		return nil
+	}
+}
+
+// ParsedUUID returns an output argument for re.Scan that decodes a UUID
+// in canonical form into its 16 bytes.
+func ParsedUUID(id *[16]byte) func([]byte) error {
+	return func(b []byte) error {
+		s := string(b)
+		if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return fmt.Errorf("patterns: bad UUID %q", b)
+		}
+		raw, err := hex.DecodeString(strings.Replace(s, "-", "", -1))
+		if err != nil {
+			return fmt.Errorf("patterns: bad UUID %q", b)
+		}
+		copy(id[:], raw)
+		return nil
+	}
+}
+
+// ParsedMAC returns an output argument for re.Scan that parses the
+// sub-match with net.ParseMAC and stores the result into *mac.
+func ParsedMAC(mac *net.HardwareAddr) func([]byte) error {
+	return func(b []byte) error {
+		v, err := net.ParseMAC(string(b))
+		if err != nil {
+			return fmt.Errorf("patterns: %w", err)
+		}
+		*mac = v
+		return nil
+	}
+}
+
+// isoLayouts lists the layouts accepted by ParsedTime, with "T" standing
+// for either "T" or " ".
+var isoLayouts = []string{
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// ParsedTime returns an output argument for re.Scan that parses text
+// matched by ISODate or ISODateTime and stores the result into *t.
+// Times without a time zone are taken to be in UTC.
+func ParsedTime(t *time.Time) func([]byte) error {
+	return func(b []byte) error {
+		s := string(b)
+		if len(s) > 10 && s[10] == ' ' {
+			s = s[:10] + "T" + s[11:]
+		}
+		s = strings.Replace(s, "z", "Z", 1)
+		for _, layout := range isoLayouts {
+			if v, err := time.Parse(layout, s); err == nil {
+				*t = v
+				return nil
+			}
+		}
+		return fmt.Errorf("patterns: bad ISO 8601 time %q", b)
+	}
+}
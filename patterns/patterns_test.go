@@ -0,0 +1,169 @@
+package patterns_test
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/patterns"
+)
+
+func TestPatterns(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		pattern string
+		good    []string
+		bad     []string
+	}{
+		{"Email", patterns.Email,
+			[]string{"bob@example.com", "bob.smith+tag@mail.example.co.uk", "x_y@a-b.io"},
+			[]string{"bob@", "@example.com", "bob@example", "bob@@example.com", "bob@-example.com"}},
+		{"Hostname", patterns.Hostname,
+			[]string{"example.com", "www.example.com", "a-1.b.org"},
+			[]string{"localhost", "-a.com", "a..com", "example.123"}},
+		{"IPv4", patterns.IPv4,
+			[]string{"0.0.0.0", "192.168.1.255", "255.255.255.255"},
+			[]string{"256.1.1.1", "1.2.3", "1.2.3.4.5", "01.2.3.4a"}},
+		{"IPv6", patterns.IPv6,
+			[]string{"2001:db8:0:0:0:0:0:1", "2001:db8::1", "::1", "::", "fe80::", "::ffff:192.0.2.1", "1:2:3:4:5:6:7:8"},
+			[]string{"2001:db8", "1:2:3:4:5:6:7", "12345::1", ":::"}},
+		{"IP", patterns.IP,
+			[]string{"10.0.0.1", "::1"},
+			[]string{"10.0.0", "host"}},
+		{"URL", patterns.URL,
+			[]string{"http://example.com", "HTTPS://example.com/a/b?c=d&e=f#g", "ftp://files.example.com/x.tgz"},
+			[]string{"example.com", "mailto:bob@example.com", "http://"}},
+		{"UUID", patterns.UUID,
+			[]string{"123e4567-e89b-12d3-a456-426614174000", "123E4567-E89B-12D3-A456-426614174000"},
+			[]string{"123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400", "g23e4567-e89b-12d3-a456-426614174000"}},
+		{"ISODate", patterns.ISODate,
+			[]string{"2024-01-15", "1999-12-31"},
+			[]string{"2024-13-01", "2024-01-32", "24-01-15", "2024/01/15"}},
+		{"ISODateTime", patterns.ISODateTime,
+			[]string{"2024-01-15T10:20:30Z", "2024-01-15 10:20:30.123+05:30", "2024-01-15T23:59:60", "2024-01-15T10:20:30-0800"},
+			[]string{"2024-01-15T24:00:00", "2024-01-15T10:20", "2024-01-15"}},
+		{"MAC", patterns.MAC,
+			[]string{"00:1A:2b:3c:4D:5e", "00-1a-2b-3c-4d-5e", "001a.2b3c.4d5e"},
+			[]string{"00:1a:2b:3c:4d", "00:1a-2b:3c:4d:5e", "00:1a:2b:3c:4d:5g"}},
+	} {
+		whole := regexp.MustCompile(`^(?:` + c.pattern + `)$`)
+		for _, s := range c.good {
+			if !whole.MatchString(s) {
+				t.Errorf("%s does not match %q", c.name, s)
+			}
+		}
+		for _, s := range c.bad {
+			if whole.MatchString(s) {
+				t.Errorf("%s matches %q", c.name, s)
+			}
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	for _, c := range []struct {
+		pattern string
+		input   string
+		want    string
+	}{
+		{patterns.URL, "see (https://example.com/a_b). Thanks", "https://example.com/a_b"},
+		{patterns.URL, "<http://example.com/?q=1>", "http://example.com/?q=1"},
+		{patterns.IPv4, "from 10.0.0.1:8080", "10.0.0.1"},
+		{patterns.IPv6, "addr 2001:db8::1:2 up", "2001:db8::1:2"},
+		{patterns.Email, "mail bob@example.com.", "bob@example.com"},
+	} {
+		if got := regexp.MustCompile(c.pattern).FindString(c.input); got != c.want {
+			t.Errorf("find %q in %q = %q; expected %q", c.pattern, c.input, got, c.want)
+		}
+	}
+}
+
+func TestCompose(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		pattern string
+		good    string
+	}{
+		{"Email", patterns.Email, "bob@example.com"},
+		{"Hostname", patterns.Hostname, "example.com"},
+		{"IPv4", patterns.IPv4, "10.0.0.1"},
+		{"IPv6", patterns.IPv6, "::1"},
+		{"IP", patterns.IP, "::1"},
+		{"URL", patterns.URL, "http://example.com/x"},
+		{"UUID", patterns.UUID, "123e4567-e89b-12d3-a456-426614174000"},
+		{"ISODate", patterns.ISODate, "2024-01-15"},
+		{"ISODateTime", patterns.ISODateTime, "2024-01-15T10:20:30Z"},
+		{"MAC", patterns.MAC, "00:1a:2b:3c:4d:5e"},
+	} {
+		// Every alternative of the pattern must stay between the
+		// surrounding literals.
+		r := regexp.MustCompile(`^src=` + c.pattern + ` end$`)
+		if !r.MatchString("src=" + c.good + " end") {
+			t.Errorf("%s: composed pattern does not match %q", c.name, c.good)
+		}
+		for _, bad := range []string{"xx " + c.good + " end", "src=" + c.good + " yy", "xx ::1 end", "src=10.0.0.1 yy"} {
+			if r.MatchString(bad) {
+				t.Errorf("%s: composed pattern matches %q", c.name, bad)
+			}
+		}
+	}
+}
+
+func TestParsed(t *testing.T) {
+	line := []byte("user Bob <bob@example.com> from fe80::1 at 2024-01-15 10:20:30Z " +
+		"id 123e4567-e89b-12d3-a456-426614174000 mac 00:1a:2b:3c:4d:5e url https://example.com/x?y=1")
+	r := regexp.MustCompile(`user (.*?) from (` + patterns.IP + `) at (` + patterns.ISODateTime + `) id (` +
+		patterns.UUID + `) mac (` + patterns.MAC + `) url (` + patterns.URL + `)`)
+	var addr mail.Address
+	var ip net.IP
+	var when time.Time
+	var id [16]byte
+	var mac net.HardwareAddr
+	var u url.URL
+	err := re.Scan(r, line, patterns.ParsedEmail(&addr), patterns.ParsedIP(&ip), patterns.ParsedTime(&when),
+		patterns.ParsedUUID(&id), patterns.ParsedMAC(&mac), patterns.ParsedURL(&u))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if addr.Name != "Bob" || addr.Address != "bob@example.com" {
+		t.Errorf("email = %+v", addr)
+	}
+	if !ip.Equal(net.ParseIP("fe80::1")) {
+		t.Errorf("ip = %v", ip)
+	}
+	if !when.Equal(time.Date(2024, 1, 15, 10, 20, 30, 0, time.UTC)) {
+		t.Errorf("time = %v", when)
+	}
+	if id[0] != 0x12 || id[15] != 0x00 || id[6] != 0x12 {
+		t.Errorf("uuid = %x", id)
+	}
+	if mac.String() != "00:1a:2b:3c:4d:5e" {
+		t.Errorf("mac = %v", mac)
+	}
+	if u.Host != "example.com" || u.Query().Get("y") != "1" {
+		t.Errorf("url = %v", u)
+	}
+
+	var d time.Time
+	if err := patterns.ParsedTime(&d)([]byte("2024-02-29")); err != nil || d != time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("ParsedTime(date) = %v, %v", d, err)
+	}
+	for _, f := range []func([]byte) error{
+		patterns.ParsedEmail(&addr),
+		patterns.ParsedIP(&ip),
+		patterns.ParsedTime(&when),
+		patterns.ParsedUUID(&id),
+		patterns.ParsedMAC(&mac),
+	} {
+		if err := f([]byte("1:2:3:4:5:6:7:8:9")); err == nil {
+			t.Errorf("parser accepted garbage")
+		}
+	}
+	if err := patterns.ParsedURL(&u)([]byte("http://[::1")); err == nil {
+		t.Errorf("ParsedURL accepted a bad URL")
+	}
+}
@@ -0,0 +1,89 @@
+package re
+
+import (
+	"bytes"
+	"regexp"
+	"regexp/syntax"
+)
+
+// maxLiterals bounds the number of alternatives returned by
+// requiredLiterals; checking more is rarely cheaper than matching.
+const maxLiterals = 16
+
+// requiredLiterals returns a set of strings at least one of which
+// occurs in every match of re, or nil if there is no such set (e.g.,
+// because re can match the empty string, or only matches text that
+// varies too much).  An input containing none of the strings cannot
+// match re.
+func requiredLiterals(re *regexp.Regexp) []string {
+	prog, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	return literalsOf(prog.Simplify())
+}
+
+// literalsOf implements requiredLiterals for a parsed expression.
+func literalsOf(n *syntax.Regexp) []string {
+	switch n.Op {
+	case syntax.OpLiteral:
+		if n.Flags&syntax.FoldCase != 0 {
+			return nil
+		}
+		return []string{string(n.Rune)}
+	case syntax.OpCapture, syntax.OpPlus:
+		return literalsOf(n.Sub[0])
+	case syntax.OpRepeat:
+		if n.Min > 0 {
+			return literalsOf(n.Sub[0])
+		}
+	case syntax.OpConcat:
+		// Use the sub-expression whose shortest literal is longest.
+		var best []string
+		for _, sub := range n.Sub {
+			if l := literalsOf(sub); l != nil && (best == nil || shortest(l) > shortest(best)) {
+				best = l
+			}
+		}
+		return best
+	case syntax.OpAlternate:
+		var all []string
+		for _, sub := range n.Sub {
+			l := literalsOf(sub)
+			if l == nil {
+				return nil
+			}
+			all = append(all, l...)
+		}
+		if len(all) > maxLiterals {
+			return nil
+		}
+		return all
+	}
+	return nil
+}
+
+// shortest returns the length of the shortest string in l.
+func shortest(l []string) int {
+	n := len(l[0])
+	for _, s := range l[1:] {
+		if len(s) < n {
+			n = len(s)
+		}
+	}
+	return n
+}
+
+// containsAny reports whether input contains one of literals, or
+// literals is empty.
+func containsAny(input []byte, literals [][]byte) bool {
+	if len(literals) == 0 {
+		return true
+	}
+	for _, lit := range literals {
+		if bytes.Contains(input, lit) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+/*
+Package quantity parses Kubernetes resource quantities, such as "500m",
+"2Gi" or "1.5e3", without depending on the Kubernetes libraries.  A
+*Quantity implements encoding.TextUnmarshaler, so it can be passed
+directly to re.Scan:
+
+	var cpu, mem quantity.Quantity
+	err := re.Scan(regexp.MustCompile(`cpu=(\S+) memory=(\S+)`), line, &cpu, &mem)
+	fmt.Println(cpu.MilliValue(), mem.Value())
+*/
+package quantity
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+// A Quantity is an exact decimal or binary amount.  The zero value is
+// zero.
+type Quantity struct {
+	rat *big.Rat // nil means zero
+}
+
+// pattern matches a quantity: a signed decimal number followed by an
+// optional binary suffix, decimal suffix or exponent.
+var pattern = regexp.MustCompile(`^([+-]?(?:\d+(?:\.\d*)?|\.\d+))(Ki|Mi|Gi|Ti|Pi|Ei|[numkMGTPE]|[eE][+-]?\d+)?$`)
+
+// decimalSuffixes maps decimal suffixes to powers of ten.
+var decimalSuffixes = map[string]int{"n": -9, "u": -6, "m": -3, "": 0, "k": 3, "M": 6, "G": 9, "T": 12, "P": 15, "E": 18}
+
+// binarySuffixes maps binary suffixes to powers of two.
+var binarySuffixes = map[string]uint{"Ki": 10, "Mi": 20, "Gi": 30, "Ti": 40, "Pi": 50, "Ei": 60}
+
+// maxExponent bounds exponents so that a quantity such as "1e999999999"
+// cannot exhaust memory.
+const maxExponent = 1000
+
+// Parse parses a quantity: a number with an optional sign and fraction,
+// followed by a binary suffix (Ki, Mi, Gi, Ti, Pi, Ei), a decimal suffix
+// (n, u, m, k, M, G, T, P, E) or a decimal exponent (e.g., "e3").
+func Parse(s string) (Quantity, error) {
+	m := pattern.FindStringSubmatch(s)
+	if m == nil {
+		return Quantity{}, fmt.Errorf("quantity: bad quantity %q", s)
+	}
+	r, ok := new(big.Rat).SetString(m[1])
+	if !ok {
+		return Quantity{}, fmt.Errorf("quantity: bad number in %q", s)
+	}
+	suffix := m[2]
+	if shift, ok := binarySuffixes[suffix]; ok {
+		return Quantity{r.Mul(r, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), shift)))}, nil
+	}
+	exp, ok := decimalSuffixes[suffix]
+	if !ok {
+		e, err := strconv.Atoi(suffix[1:])
+		if err != nil || e > maxExponent || e < -maxExponent {
+			return Quantity{}, fmt.Errorf("quantity: exponent out of range in %q", s)
+		}
+		exp = e
+	}
+	p := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exp))), nil))
+	if exp < 0 {
+		p.Inv(p)
+	}
+	return Quantity{r.Mul(r, p)}, nil
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by calling Parse.
+func (q *Quantity) UnmarshalText(text []byte) error {
+	p, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*q = p
+	return nil
+}
+
+// Rat returns the exact value of q.
+func (q Quantity) Rat() *big.Rat {
+	if q.rat == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(q.rat)
+}
+
+// Value returns q rounded up to an integer, as Kubernetes does; e.g.,
+// "500m" yields 1.  Values beyond the range of int64 are clamped.
+func (q Quantity) Value() int64 {
+	return ceil(q.Rat())
+}
+
+// MilliValue returns 1000*q rounded up to an integer; e.g., "0.5" and
+// "500m" both yield 500.  Values beyond the range of int64 are clamped.
+func (q Quantity) MilliValue() int64 {
+	r := q.Rat()
+	return ceil(r.Mul(r, big.NewRat(1000, 1)))
+}
+
+// ceil returns the smallest integer >= r, clamped to the range of int64.
+func ceil(r *big.Rat) int64 {
+	n, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() > 0 {
+		n.Add(n, big.NewInt(1))
+	}
+	switch {
+	case n.IsInt64():
+		return n.Int64()
+	case n.Sign() > 0:
+		return math.MaxInt64
+	default:
+		return math.MinInt64
+	}
+}
+
+// Cmp compares q and o, returning -1, 0 or +1 if q is less than, equal
+// to, or greater than o.
+func (q Quantity) Cmp(o Quantity) int {
+	return q.Rat().Cmp(o.Rat())
+}
+
+// String returns q as an exact decimal number when possible (e.g.,
+// "0.5" or "2147483648"), and otherwise as a fraction (e.g., "1/3").
+func (q Quantity) String() string {
+	r := q.Rat()
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	// A fraction has a finite decimal expansion iff the denominator has
+	// no prime factors other than 2 and 5.
+	d := new(big.Int).Set(r.Denom())
+	digits := 0
+	for _, f := range []int64{2, 5} {
+		n := 0
+		for m := new(big.Int); ; n++ {
+			quo, mod := new(big.Int).QuoRem(d, big.NewInt(f), m)
+			if mod.Sign() != 0 {
+				break
+			}
+			d = quo
+		}
+		if n > digits {
+			digits = n
+		}
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return r.String()
+	}
+	return r.FloatString(digits)
+}
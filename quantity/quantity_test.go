@@ -0,0 +1,87 @@
+package quantity_test
+
+import (
+	"math"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/quantity"
+)
+
+func TestParse(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		str   string
+		value int64
+		milli int64
+	}{
+		{"0", "0", 0, 0},
+		{"500m", "0.5", 1, 500},
+		{"0.5", "0.5", 1, 500},
+		{"1500Mi", "1572864000", 1572864000, 1572864000000},
+		{"2Gi", "2147483648", 2147483648, 2147483648000},
+		{"1.5k", "1500", 1500, 1500000},
+		{"+3", "3", 3, 3000},
+		{"-2.5", "-2.5", -2, -2500},
+		{"100n", "0.0000001", 1, 1},
+		{"5u", "0.000005", 1, 1},
+		{"1e3", "1000", 1000, 1000000},
+		{"12E-1", "1.2", 2, 1200},
+		{"1E", "1000000000000000000", 1000000000000000000, math.MaxInt64},
+		{"16Ei", "18446744073709551616", math.MaxInt64, math.MaxInt64},
+		{".5Ki", "512", 512, 512000},
+	} {
+		q, err := quantity.Parse(c.input)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		if s := q.String(); s != c.str {
+			t.Errorf("Parse(%q).String() = %q; expected %q", c.input, s, c.str)
+		}
+		if v := q.Value(); v != c.value {
+			t.Errorf("Parse(%q).Value() = %d; expected %d", c.input, v, c.value)
+		}
+		if v := q.MilliValue(); v != c.milli {
+			t.Errorf("Parse(%q).MilliValue() = %d; expected %d", c.input, v, c.milli)
+		}
+	}
+	for _, bad := range []string{"", "m", "1.2.3", "1 Gi", "1gi", "1Ki2", "1e", "1e99999", "0x10"} {
+		if q, err := quantity.Parse(bad); err == nil {
+			t.Errorf("Parse(%q) = %v; expected an error", bad, q)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	a, _ := quantity.Parse("1Ki")
+	b, _ := quantity.Parse("1024")
+	c, _ := quantity.Parse("1k")
+	if a.Cmp(b) != 0 || c.Cmp(a) != -1 || a.Cmp(c) != 1 {
+		t.Errorf("Cmp: 1Ki vs 1024 = %d, 1k vs 1Ki = %d", a.Cmp(b), c.Cmp(a))
+	}
+	var zero quantity.Quantity
+	if zero.String() != "0" || zero.Value() != 0 || zero.Cmp(quantity.Quantity{}) != 0 {
+		t.Errorf("zero Quantity = %v", zero)
+	}
+	third := a.Rat()
+	third.SetFrac64(1, 3)
+	if a.String() != "1024" {
+		t.Errorf("modifying Rat() result changed the quantity to %v", a)
+	}
+}
+
+func TestScan(t *testing.T) {
+	var cpu, mem quantity.Quantity
+	pattern := regexp.MustCompile(`cpu=(\S+) memory=(\S+)`)
+	if err := re.Scan(pattern, []byte("requests: cpu=250m memory=64Mi"), &cpu, &mem); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cpu.MilliValue() != 250 || mem.Value() != 64<<20 {
+		t.Errorf("got cpu=%v memory=%v", cpu, mem)
+	}
+	if err := re.Scan(pattern, []byte("cpu=lots memory=1"), &cpu, &mem); err == nil {
+		t.Errorf("Scan of bad quantity succeeded unexpectedly")
+	}
+}
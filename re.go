@@ -19,15 +19,25 @@ follows:
 
 A "func([]byte) error" can also be passed in as an extra argument to provide
 custom parsing.
+
+Building with the re_minimal tag leaves out the streaming helpers
+(Stream, LineScanner and Expecter), JSON, and the support for output
+types from packages database/sql, encoding/json, math/big and flag, so
+that programs needing only Scan do not link in those packages.
 */
 package re
 
 import (
+	"encoding"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Span is a special type designed to be passed via pointer to Scan.  re.Scan
@@ -61,6 +71,18 @@ var (
 // stored in the pointed-to object.  When storing into a []byte, no
 // copying is done, and the stored slice is an alias of the input.
 //
+// Pointer to []rune: The corresponding sub-match is decoded as UTF-8
+// and its runes are stored into a newly allocated slice.  As in a
+// conversion from string to []rune, invalid UTF-8 is decoded as
+// utf8.RuneError.
+//
+// Pointer to bool: The corresponding sub-match is parsed with
+// strconv.ParseBool, which accepts "1", "t", "T", "TRUE", "true",
+// "True", "0", "f", "F", "FALSE", "false" and "False".
+//
+// Pointer to time.Duration: The corresponding sub-match is parsed
+// with time.ParseDuration; e.g., "1h30m" or "200ms".
+//
 // Pointer to some built-in numeric types (int, int8, int16, int32,
 // int64, uint, uintptr, uint8, uint16, uint32, uint64, float32,
 // float64): The corresponding sub-match will be parsed as a literal
@@ -68,20 +90,91 @@ var (
 // will return an error if the sub-match cannot be parsed
 // successfully, or the parse result is out of range for the type.
 //
+// Pointer to json.Number (unless built with the re_minimal tag): The
+// corresponding sub-match, which must be a number in JSON syntax (e.g.,
+// "-12", "3.5e10", but not "0x1f" or "+1"), is stored as is, deferring
+// the choice between integer and floating-point until the number is
+// used or written out as JSON.
+//
+// *big.Int, *big.Float or *big.Rat (unless built with the re_minimal
+// tag): The corresponding sub-match is parsed with the SetString
+// method, so arbitrarily large numbers can be extracted.  As for the
+// built-in integer types, the base of a *big.Int is determined by its
+// prefix.  A *big.Float with zero precision gets a precision of 64, as
+// with SetString; set a larger precision on the *big.Float before
+// calling Scan to avoid rounding.
+//
 // Pointer to a rune or a byte: rune is an alias of uint32 and byte is
 // an alias of uint8, so the preceding rule applies; i.e., Scan treats
 // the input as a string of digits to be parsed into the rune or
-// byte. Therefore Scan cannot be used to directly extract a single
-// rune or byte from the input. For that, parse into a string or
-// []byte and use the first element, or pass in a custom parsing
-// function (see below).
+// byte. To extract a single character from the input instead, wrap
+// the output with Char or Byte.
+//
+// *sql.NullString, *sql.NullInt64, *sql.NullInt32, *sql.NullFloat64,
+// *sql.NullBool or *sql.NullTime (unless built with the re_minimal
+// tag): If the corresponding group did not participate in the match,
+// the value is zeroed and Valid is set to false.  Otherwise the
+// sub-match is stored into the value following these same rules, and
+// Valid is set to true.  The results can be passed directly as
+// arguments to the Exec method of a sql.DB.
+//
+// Pointer to complex64 or complex128 (when built with Go 1.15 or later):
+// The corresponding sub-match is parsed with strconv.ParseComplex; e.g.,
+// "1.5+2i", "(3-4i)" or "2i".
 //
 // func([]byte) error: The function is passed the corresponding
 // sub-match.  If the result is a non-nil error, the Scan call fails
 // with that error. Pass in such a function to provide custom parsing:
 // e.g., treating a number as decimal even if it starts with "0"
 // (normally Scan would treat such as a number as octal); or parsing
-// an otherwise unsupported type like time.Duration.
+// an otherwise unsupported type like *url.URL.
+//
+// An output created by Named or Group: The sub-match of the selected
+// group is stored into the wrapped output, following these same rules.
+//
+// Pointer to a type with a parser added by RegisterParser (when built
+// with Go 1.18 or later): The corresponding sub-match is parsed by the
+// registered parser.  Registered parsers take precedence over the
+// remaining rules.
+//
+// Pointer to netip.Addr or netip.AddrPort (when built with Go 1.18 or
+// later): The corresponding sub-match is parsed with netip.ParseAddr or
+// netip.ParseAddrPort respectively, so an empty or malformed address, or
+// an out of range port, is an error.
+//
+// encoding.TextUnmarshaler: If output[i] has none of the preceding
+// types but implements encoding.TextUnmarshaler, its UnmarshalText
+// method is called with the corresponding sub-match.  This covers
+// types such as *time.Time (RFC 3339 timestamps) and *net.IP.
+//
+// sql.Scanner (unless built with the re_minimal tag): If output[i] has
+// none of the preceding types but implements the Scanner interface of
+// package database/sql, its Scan method is called with the
+// corresponding sub-match as a string, or with nil if the group did not
+// participate in the match.  This covers many decimal and custom
+// database types.
+//
+// fmt.Scanner: If output[i] has none of the preceding types but
+// implements fmt.Scanner, the corresponding sub-match is scanned with
+// fmt.Fscan, and text other than white space left over after the value
+// is an error.
+//
+// flag.Value (unless built with the re_minimal tag): If output[i] has
+// none of the preceding types but implements flag.Value, its Set method
+// is called with the corresponding sub-match, so option types written
+// for package flag can be scanned as well.
+//
+// Pointer to a byte array (e.g., *[32]byte): A sub-match of the same
+// length as the array is copied into it, while one of twice the length
+// is hex-decoded into it, which suits digests and fixed-size IDs.  A
+// sub-match of any other length is an error.
+//
+// Pointer to a pointer (e.g., **int): If the corresponding group did
+// not participate in the match, nil is stored into the pointed-to
+// pointer.  Otherwise a new variable is allocated, the sub-match is
+// stored into it following these same rules, and its address is stored
+// into the pointed-to pointer.  This distinguishes an absent optional
+// field from one holding the zero value.
 //
 // An error is returned if output[i] does not have one of the preceding
 // types.  Caveat: the set of supported types might be extended in the
@@ -89,23 +182,142 @@ var (
 //
 // Extra sub-matches (ones with no corresponding output) are discarded silently.
 func Scan(re *regexp.Regexp, input []byte, output ...interface{}) error {
+	return scanMatch(re, input, re.FindSubmatchIndex(input), output)
+}
+
+// ScanStrict is like Scan, except that it returns an error if the
+// number of outputs differs from the number of sub-matches in re,
+// instead of silently discarding extra sub-matches.  This catches call
+// sites that were not updated when a group was added to re.  The check
+// does not depend on input, so it fails even if re does not match.
+func ScanStrict(re *regexp.Regexp, input []byte, output ...interface{}) error {
+	if n := re.NumSubexp(); n != len(output) {
+		return fmt.Errorf(`re.ScanStrict: "%s" has %d sub-matches; got %d outputs`, re, n, len(output))
+	}
+	return Scan(re, input, output...)
+}
+
+// Cut is like Scan, but it also slices input around the first match of
+// re, returning the text before and after the match, as strings.Cut
+// does for a fixed separator.  If re does not match, Cut returns input,
+// nil, and an error wrapping NotFound.  before and after are aliases of
+// input, and are returned even if a sub-match cannot be parsed.
+func Cut(re *regexp.Regexp, input []byte, output ...interface{}) (before, after []byte, err error) {
+	matches := re.FindSubmatchIndex(input)
+	if matches == nil {
+		return input, nil, fmt.Errorf("regular expression %q: %w", re, NotFound)
+	}
+	err = scanMatch(re, input, matches, output)
+	return input[:matches[0]], input[matches[1]:], err
+}
+
+// ScanLast is like Scan, but extracts the sub-matches of the last match
+// of re in input instead of the first, e.g., the final "Total: N" line
+// of a report.  The last match is the last of the successive
+// non-overlapping matches found by ScanAll, which is not necessarily
+// the match that starts last: the last match of `\d+` in "12" is "12",
+// not "2".
+func ScanLast(re *regexp.Regexp, input []byte, output ...interface{}) error {
+	all := re.FindAllSubmatchIndex(input, -1)
+	if len(all) == 0 {
+		return fmt.Errorf("regular expression %q: %w", re, NotFound)
+	}
+	return scanMatch(re, input, all[len(all)-1], output)
+}
+
+// ScanAt is like Scan, but only finds a match of re that starts exactly
+// at offset in input, as if re were anchored there.  The text from
+// offset onwards is matched as if it were a new input, so ^ and \A match
+// at offset.  Spans stored into *Span outputs are offsets from the start
+// of input, not from offset, so they can be used to slice input
+// directly.  If there is no match at offset (including when offset is
+// out of range), ScanAt returns an error wrapping NotFound.
+//
+// A match found later in the input is discarded, so when re does not
+// begin with ^ or \A, a failing call may cost as much as a search of
+// the rest of input.
+func ScanAt(re *regexp.Regexp, input []byte, offset int, output ...interface{}) error {
+	var matches []int
+	if offset >= 0 && offset <= len(input) {
+		matches = re.FindSubmatchIndex(input[offset:])
+	}
+	if matches == nil || matches[0] != 0 {
+		return fmt.Errorf("regular expression %q: %w", re, NotFound)
+	}
+	for i := range matches {
+		if matches[i] >= 0 {
+			matches[i] += offset
+		}
+	}
+	return scanMatch(re, input, matches, output)
+}
+
+// ScanFull is like Scan, but only succeeds if re matches the entire
+// input, as if re were wrapped in \A(?:...)\z.  This is usually what is
+// wanted when validating a value, where forgetting the anchors lets
+// "80x" through a pattern meant for port numbers.  Unlike checking that
+// the match found by Scan spans the input, ScanFull also finds a full
+// match that leftmost-first matching would not prefer; e.g., `a|ab`
+// fully matches "ab".  For a regular expression using leftmost-longest
+// matching (see regexp.Regexp.Longest), the sub-matches are those of the
+// leftmost-longest match.
+func ScanFull(re *regexp.Regexp, input []byte, output ...interface{}) error {
 	matches := re.FindSubmatchIndex(input)
+	if matches == nil {
+		return fmt.Errorf("regular expression %q does not match entire input: %w", re, NotFound)
+	}
+	if matches[0] != 0 || matches[1] != len(input) {
+		// A full match may still exist if leftmost-first matching
+		// preferred a shorter alternative.  If re uses leftmost-longest
+		// matching, there is none, and the anchored form finds none
+		// either.
+		full, err := anchored(re)
+		if err != nil {
+			return err
+		}
+		if matches = full.FindSubmatchIndex(input); matches == nil {
+			return fmt.Errorf("regular expression %q does not match entire input: %w", re, NotFound)
+		}
+	}
+	return scanMatch(re, input, matches, output)
+}
+
+// anchoredCache maps recently used regular expressions to their anchored
+// forms.
+var anchoredCache = &fifoMap{size: 64}
+
+// anchored returns re wrapped in \A(?:...)\z.
+func anchored(re *regexp.Regexp) (*regexp.Regexp, error) {
+	if a, ok := anchoredCache.load(re); ok {
+		return a.(*regexp.Regexp), nil
+	}
+	a, err := regexp.Compile(`\A(?:` + re.String() + `)\z`)
+	if err != nil {
+		return nil, err
+	}
+	anchoredCache.store(re, a)
+	return a, nil
+}
+
+// scanMatch stores the sub-matches recorded in matches (the result of
+// matching re against input) into output.
+func scanMatch(re *regexp.Regexp, input []byte, matches []int, output []interface{}) error {
 	if matches == nil {
 		return fmt.Errorf("regular expression %q: %w", re, NotFound)
 	}
-	if len(matches) < 2+2*len(output) {
+	if n := positional(output); len(matches) < 2+2*n {
 		return fmt.Errorf(`re.Scan: only got %d matches from "%s"; need at least %d`,
-			len(matches)/2-1, re, len(output))
+			len(matches)/2-1, re, n)
 	}
+	// Check names before storing anything.
 	for i, r := range output {
-		span := Span{
-			Start: matches[2+2*i],
-			End:   matches[2+2*i+1],
-		}
-		var submatch []byte
-		if span.Start > -1 && span.End >= span.Start {
-			submatch = input[span.Start:span.End]
+		if _, _, err := outputGroup(re, i, r); err != nil {
+			return err
 		}
+	}
+	for i, r := range output {
+		j, r, _ := outputGroup(re, i, r)
+		submatch, span := submatchAt(input, matches, j)
 		if err := assign(r, submatch, span); err != nil {
 			return err
 		}
@@ -113,20 +325,246 @@ func Scan(re *regexp.Regexp, input []byte, output ...interface{}) error {
 	return nil
 }
 
+// ScanAll is like Scan, but it processes every non-overlapping match
+// of re in input instead of just the first one.  It returns the number
+// of matches processed.
+//
+// Each non-nil entry in output must be a pointer to a slice.  For every
+// match, the corresponding sub-match is parsed into a new element
+// (following the same rules as Scan for a pointer to the element type)
+// and appended to the slice.  E.g., a *[]int collects one int per
+// match.  A "func([]byte) error" may also be passed; it is called once
+// per match.
+//
+// If no match is found, an error wrapping NotFound is returned.  If a
+// sub-match cannot be parsed, ScanAll stops and returns the number of
+// matches that were completely processed before the failure; the
+// slices contain exactly one element per completely processed match.
+func ScanAll(re *regexp.Regexp, input []byte, output ...interface{}) (int, error) {
+	return ScanAllN(re, input, -1, output...)
+}
+
+// ScanAllN is like ScanAll, but processes at most n matches, as
+// regexp.Regexp.FindAll does: if n >= 0, the search stops after n
+// matches, so the rest of a large input is not examined.  If n < 0, all
+// matches are processed.  If n == 0, nothing is processed and ScanAllN
+// returns 0 and a nil error.
+func ScanAllN(re *regexp.Regexp, input []byte, n int, output ...interface{}) (int, error) {
+	slices := make([]reflect.Value, len(output))
+	groups := make([]int, len(output))
+	need := positional(output)
+	output = append([]interface{}(nil), output...)
+	for i, r := range output {
+		j, r, err := outputGroup(re, i, r)
+		if err != nil {
+			return 0, err
+		}
+		groups[i], output[i] = j, r
+		switch r.(type) {
+		case nil, func([]byte) error:
+			continue
+		}
+		v := reflect.ValueOf(r)
+		if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+			return 0, fmt.Errorf("re.ScanAll: output %d has type %s; need a pointer to a slice", i, v.Type())
+		}
+		slices[i] = v.Elem()
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	all := re.FindAllSubmatchIndex(input, n)
+	if all == nil {
+		return 0, fmt.Errorf("regular expression %q: %w", re, NotFound)
+	}
+	if len(all[0]) < 2+2*need {
+		return 0, fmt.Errorf(`re.ScanAll: only got %d matches from "%s"; need at least %d`,
+			len(all[0])/2-1, re, need)
+	}
+	elems := make([]reflect.Value, len(output))
+	for n, matches := range all {
+		for i, r := range output {
+			submatch, span := submatchAt(input, matches, groups[i])
+			if !slices[i].IsValid() {
+				if err := assign(r, submatch, span); err != nil {
+					return n, err
+				}
+				continue
+			}
+			e := reflect.New(slices[i].Type().Elem())
+			if err := assign(e.Interface(), submatch, span); err != nil {
+				return n, err
+			}
+			elems[i] = e.Elem()
+		}
+		// Only append once every sub-match of this match has been parsed.
+		for i, e := range elems {
+			if e.IsValid() {
+				slices[i].Set(reflect.Append(slices[i], e))
+			}
+		}
+	}
+	return len(all), nil
+}
+
+// Named returns an output argument that receives the sub-match of the
+// group with the given name, e.g., (?P<host>\w+), instead of the
+// sub-match at its position in the list of outputs.  output is
+// interpreted as usual; e.g.
+//
+//	var host string
+//	var port int
+//	err := re.Scan(reg, input, re.Named("port", &port), re.Named("host", &host))
+//
+// Named and positional outputs may be mixed; a positional output
+// receives the sub-match corresponding to its position in the output
+// list, regardless of any named outputs that precede it.  It is an
+// error if re has no group with the given name.
+func Named(name string, output interface{}) interface{} {
+	return selectedOutput{name: name, output: output}
+}
+
+// Group returns an output argument that receives the sub-match of the
+// group with the given index, counting groups from one in the order of
+// their opening parentheses (as in regexp.Regexp.Expand), instead of
+// the sub-match at its position in the list of outputs.  Index zero
+// selects the entire match.  This avoids padding the output list with
+// nils when only a few groups of a large regular expression are needed:
+//
+//	var status int
+//	err := re.Scan(reg, line, re.Group(7, &status))
+//
+// Group outputs may be mixed with positional and Named outputs, as
+// described for Named.  It is an error if index is negative or greater
+// than the number of groups in re.
+func Group(index int, output interface{}) interface{} {
+	return selectedOutput{index: index, output: output}
+}
+
+// selectedOutput is an output created by Named or Group.
+type selectedOutput struct {
+	name   string // Group name for Named
+	index  int    // Group index for Group
+	output interface{}
+}
+
+// outputGroup returns the index (counting from zero, with -1 denoting
+// the entire match) of the sub-match received by output argument r
+// found at position i of the output list, along with the output it
+// should be stored into.
+func outputGroup(re *regexp.Regexp, i int, r interface{}) (int, interface{}, error) {
+	sel, ok := r.(selectedOutput)
+	if !ok {
+		return i, r, nil
+	}
+	if sel.name == "" {
+		if sel.index < 0 || sel.index > re.NumSubexp() {
+			return 0, nil, fmt.Errorf(`re.Group: no group %d in "%s"`, sel.index, re)
+		}
+		return sel.index - 1, sel.output, nil
+	}
+	for j, name := range re.SubexpNames() {
+		if j > 0 && name == sel.name {
+			return j - 1, sel.output, nil
+		}
+	}
+	return 0, nil, fmt.Errorf(`re.Named: no group named "%s" in "%s"`, sel.name, re)
+}
+
+// positional returns the number of sub-matches needed by the outputs
+// whose sub-match is determined by their position in output.
+func positional(output []interface{}) int {
+	for i := len(output) - 1; i >= 0; i-- {
+		if _, ok := output[i].(selectedOutput); !ok {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// submatchAt returns the i'th sub-match (counting from zero, and
+// excluding the entire match) recorded in matches, along with its span.
+// An i of -1 denotes the entire match.
+func submatchAt(input []byte, matches []int, i int) ([]byte, Span) {
+	span := Span{
+		Start: matches[2+2*i],
+		End:   matches[2+2*i+1],
+	}
+	var submatch []byte
+	if span.Start > -1 && span.End >= span.Start {
+		submatch = input[span.Start:span.End]
+	}
+	return submatch, span
+}
+
+// wrappedSpan returns the span to store sub-match b with when it is
+// passed on by an output of type func([]byte) error, which does not know
+// where b is in the input.  Since submatchAt returns nil only for a group
+// that did not participate in the match, the span of a nil b marks it
+// absent, as pointer and nullable outputs expect.
+func wrappedSpan(b []byte) Span {
+	if b == nil {
+		return Span{Start: -1, End: -1}
+	}
+	return Span{Start: 0, End: len(b)}
+}
+
+// An assigner stores a sub-match (whose extent in the input is given
+// by the Span) into a particular output argument.
+type assigner func(b []byte, s Span) error
+
+// assign stores sub-match b with extent s into output argument r.
 func assign(r interface{}, b []byte, s Span) error {
+	if err := assignBasic(r, b, s); err != errNotBasic {
+		return err
+	}
+	a, err := newAssigner(r)
+	if err != nil {
+		return err
+	}
+	return a(b, s)
+}
+
+// errNotBasic is returned by assignBasic for outputs of other types.
+var errNotBasic = errors.New("not a basic output type")
+
+// assignBasic stores sub-match b with extent s into r if r has one of the
+// basic types that newAssigner handles with a type switch, and returns
+// errNotBasic otherwise.  Scan calls it directly so that the common
+// outputs are stored without allocating an assigner.
+func assignBasic(r interface{}, b []byte, s Span) error {
 	switch v := r.(type) {
 	case nil:
 		// Discard the match.
+		return nil
 	case func([]byte) error:
-		if err := v(b); err != nil {
-			return err
-		}
+		return v(b)
 	case *Span:
 		*v = s
+		return nil
 	case *string:
 		*v = string(b)
+		return nil
 	case *[]byte:
 		*v = b
+		return nil
+	case *[]rune:
+		*v = []rune(string(b))
+		return nil
+	case *bool:
+		x, err := strconv.ParseBool(string(b))
+		if err != nil {
+			return err
+		}
+		*v = x
+		return nil
+	case *time.Duration:
+		d, err := time.ParseDuration(string(b))
+		if err != nil {
+			return err
+		}
+		*v = d
+		return nil
 	case *int:
 		i, err := strconv.ParseInt(string(b), 0, 64)
 		if err != nil {
@@ -136,30 +574,35 @@ func assign(r interface{}, b []byte, s Span) error {
 			return parseError("out of range for int", b)
 		}
 		*v = int(i)
+		return nil
 	case *int8:
 		i, err := strconv.ParseInt(string(b), 0, 8)
 		if err != nil {
 			return err
 		}
 		*v = int8(i)
+		return nil
 	case *int16:
 		i, err := strconv.ParseInt(string(b), 0, 16)
 		if err != nil {
 			return err
 		}
 		*v = int16(i)
+		return nil
 	case *int32:
 		i, err := strconv.ParseInt(string(b), 0, 32)
 		if err != nil {
 			return err
 		}
 		*v = int32(i)
+		return nil
 	case *int64:
 		i, err := strconv.ParseInt(string(b), 0, 64)
 		if err != nil {
 			return err
 		}
 		*v = i
+		return nil
 	case *uint:
 		u, err := strconv.ParseUint(string(b), 0, 64)
 		if err != nil {
@@ -169,6 +612,7 @@ func assign(r interface{}, b []byte, s Span) error {
 			return parseError("out of range for uint", b)
 		}
 		*v = uint(u)
+		return nil
 	case *uintptr:
 		u, err := strconv.ParseUint(string(b), 0, 64)
 		if err != nil {
@@ -178,47 +622,161 @@ func assign(r interface{}, b []byte, s Span) error {
 			return parseError("out of range for uintptr", b)
 		}
 		*v = uintptr(u)
+		return nil
 	case *uint8:
 		u, err := strconv.ParseUint(string(b), 0, 8)
 		if err != nil {
 			return err
 		}
 		*v = uint8(u)
+		return nil
 	case *uint16:
 		u, err := strconv.ParseUint(string(b), 0, 16)
 		if err != nil {
 			return err
 		}
 		*v = uint16(u)
+		return nil
 	case *uint32:
 		u, err := strconv.ParseUint(string(b), 0, 32)
 		if err != nil {
 			return err
 		}
 		*v = uint32(u)
+		return nil
 	case *uint64:
 		u, err := strconv.ParseUint(string(b), 0, 64)
 		if err != nil {
 			return err
 		}
 		*v = u
+		return nil
 	case *float32:
 		f, err := strconv.ParseFloat(string(b), 32)
 		if err != nil {
 			return err
 		}
 		*v = float32(f)
+		return nil
 	case *float64:
 		f, err := strconv.ParseFloat(string(b), 64)
 		if err != nil {
 			return err
 		}
 		*v = f
-	default:
-		t := reflect.ValueOf(r).Type()
-		return parseError(fmt.Sprintf("unsupported type %s", t), b)
+		return nil
+	}
+	return errNotBasic
+}
+
+// newAssigner returns the assigner for output argument r, or an error
+// if r does not have a supported type.
+func newAssigner(r interface{}) (assigner, error) {
+	switch r.(type) {
+	case nil, func([]byte) error, *Span, *string, *[]byte, *[]rune,
+		*bool, *time.Duration, *int, *int8, *int16, *int32, *int64,
+		*uint, *uintptr, *uint8, *uint16, *uint32, *uint64,
+		*float32, *float64:
+		return func(b []byte, s Span) error { return assignBasic(r, b, s) }, nil
+	}
+	if a, ok := complexAssigner(r); ok {
+		return a, nil
+	}
+	if a, ok := adapterAssigner(r); ok {
+		return a, nil
+	}
+	if a, ok := sqlAssigner(r); ok {
+		return a, nil
+	}
+	if a, ok := registeredAssigner(r); ok {
+		return a, nil
+	}
+	if a, ok := netipAssigner(r); ok {
+		return a, nil
+	}
+	if u, ok := r.(encoding.TextUnmarshaler); ok {
+		return func(b []byte, _ Span) error { return u.UnmarshalText(b) }, nil
+	}
+	if a, ok := sqlScannerAssigner(r); ok {
+		return a, nil
+	}
+	if sc, ok := r.(fmt.Scanner); ok {
+		return fmtScannerAssigner(sc), nil
+	}
+	if a, ok := flagAssigner(r); ok {
+		return a, nil
+	}
+	if v := reflect.ValueOf(r); v.Kind() == reflect.Ptr && !v.IsNil() {
+		switch e := v.Type().Elem(); {
+		case e.Kind() == reflect.Ptr:
+			return pointerAssigner(v)
+		case e.Kind() == reflect.Array && e.Elem().Kind() == reflect.Uint8:
+			return arrayAssigner(v), nil
+		}
+	}
+	t := reflect.ValueOf(r).Type()
+	return nil, fmt.Errorf("re.Scan: unsupported type %s", t)
+}
+
+// pointerAssigner returns the assigner for v, a pointer to a pointer.
+// The assigner stores nil into *v for a sub-match that did not
+// participate in the match, and otherwise parses the sub-match into a
+// newly allocated variable and stores its address into *v.
+func pointerAssigner(v reflect.Value) (assigner, error) {
+	elem := v.Type().Elem().Elem()
+	// Check that the element type is supported before any match.
+	if _, err := newAssigner(reflect.New(elem).Interface()); err != nil {
+		return nil, err
+	}
+	return func(b []byte, s Span) error {
+		if s.Start < 0 {
+			v.Elem().Set(reflect.Zero(v.Elem().Type()))
+			return nil
+		}
+		p := reflect.New(elem)
+		if err := assign(p.Interface(), b, s); err != nil {
+			return err
+		}
+		v.Elem().Set(p)
+		return nil
+	}, nil
+}
+
+// fmtScannerAssigner returns the assigner for r, which implements
+// fmt.Scanner.  The assigner scans the sub-match with fmt.Fscan and
+// reports an error if any text other than white space is left over.
+func fmtScannerAssigner(r fmt.Scanner) assigner {
+	return func(b []byte, _ Span) error {
+		rd := strings.NewReader(string(b))
+		if _, err := fmt.Fscan(rd, r); err != nil {
+			return parseError(err.Error(), b)
+		}
+		if rest, _ := ioutil.ReadAll(rd); len(strings.TrimSpace(string(rest))) > 0 {
+			return parseError(fmt.Sprintf("unexpected %q after value", rest), b)
+		}
+		return nil
+	}
+}
+
+// arrayAssigner returns the assigner for v, a pointer to a byte array.
+func arrayAssigner(v reflect.Value) assigner {
+	return func(b []byte, _ Span) error {
+		a := v.Elem()
+		n := a.Len()
+		switch len(b) {
+		case n:
+			reflect.Copy(a, reflect.ValueOf(b))
+		case 2 * n:
+			buf := make([]byte, n)
+			if _, err := hex.Decode(buf, b); err != nil {
+				return parseError(err.Error(), b)
+			}
+			reflect.Copy(a, reflect.ValueOf(buf))
+		default:
+			return parseError(fmt.Sprintf("need %d bytes or %d hex digits", n, 2*n), b)
+		}
+		return nil
 	}
-	return nil
 }
 
 func parseError(explanation string, b []byte) error {
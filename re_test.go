@@ -3,9 +3,13 @@ package re_test
 import (
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/ghemawat/re"
 )
@@ -67,6 +71,24 @@ func TestFind(t *testing.T) {
 		test(`(.*):\d+`, "host:1234", true, new([]byte), []byte("host")),
 		test(`(.*):\d+`, ":1234", true, new([]byte), []byte("")),
 
+		// bool
+		test(`(.*)`, "true", true, new(bool), true),
+		test(`(.*)`, "1", true, new(bool), true),
+		test(`(.*)`, "false", true, new(bool), false),
+		test(`(.*)`, "0", true, new(bool), false),
+		test(`(.*)`, "yes", false, new(bool), nil),
+
+		// time.Duration
+		test(`(.*)`, "1h30m", true, new(time.Duration), 90*time.Minute),
+		test(`(.*)`, "200ms", true, new(time.Duration), 200*time.Millisecond),
+		test(`(.*)`, "200", false, new(time.Duration), nil),
+
+		// encoding.TextUnmarshaler
+		test(`(.*)`, "2020-01-02T03:04:05Z", true, new(time.Time), time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)),
+		test(`(.*)`, "yesterday", false, new(time.Time), nil),
+		test(`(.*)`, "10.0.0.1", true, new(net.IP), net.IPv4(10, 0, 0, 1)),
+		test(`(.*)`, "10.0.0.300", false, new(net.IP), nil),
+
 		// int
 		test(`(\d+)`, "1234", true, new(int), 1234),
 		test(`(.*)`, "-1234", true, new(int), -1234),
@@ -151,6 +173,15 @@ func TestFind(t *testing.T) {
 		test(`(.*)`, "1e40", true, new(float64), float64(1e40)),
 		test(`(.*)`, "1e400", false, new(float64), nil),
 		test(`(.*)`, "x", false, new(float64), nil),
+
+		// math/big
+		test(`(.*)`, "123456789123456789123456789", true, new(big.Int), bigInt("123456789123456789123456789")),
+		test(`(.*)`, "-0x10", true, new(big.Int), bigInt("-16")),
+		test(`(.*)`, "12x", false, new(big.Int), nil),
+		test(`(.*)`, "1.5e400", true, new(big.Float), bigFloat("1.5e400")),
+		test(`(.*)`, "1.5.5", false, new(big.Float), nil),
+		test(`(.*)`, "3/6", true, new(big.Rat), *big.NewRat(1, 2)),
+		test(`(.*)`, "3/x", false, new(big.Rat), nil),
 	} {
 		err := re.Scan(regexp.MustCompile(c.re), []byte(c.input), c.args...)
 		if !c.result {
@@ -179,6 +210,18 @@ func TestFind(t *testing.T) {
 	}
 }
 
+func bigInt(s string) big.Int {
+	var i big.Int
+	i.SetString(s, 0)
+	return i
+}
+
+func bigFloat(s string) big.Float {
+	var f big.Float
+	f.SetString(s)
+	return f
+}
+
 func TestReFunc(t *testing.T) {
 	var arg string
 	savearg := func(a []byte) error {
@@ -290,3 +333,417 @@ func TestReAliasing(t *testing.T) {
 		t.Fatalf("extracted byte slice does not alias input")
 	}
 }
+
+func TestScanStrict(t *testing.T) {
+	pattern := regexp.MustCompile(`^(\w+):(\d+)$`)
+	var host string
+	var port int
+	if err := re.ScanStrict(pattern, []byte("h:80"), &host, &port); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if host != "h" || port != 80 {
+		t.Errorf("ScanStrict extracted %s, %d; expected h, 80", host, port)
+	}
+	if err := re.ScanStrict(pattern, []byte("h:80"), &host); err == nil {
+		t.Errorf("ScanStrict with too few outputs succeeded unexpectedly")
+	}
+	if err := re.ScanStrict(pattern, []byte("h:80"), &host, &port, nil); err == nil {
+		t.Errorf("ScanStrict with too many outputs succeeded unexpectedly")
+	}
+	if err := re.ScanStrict(pattern, []byte("junk"), &host, &port); !errors.Is(err, re.NotFound) {
+		t.Errorf("ScanStrict error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+}
+
+func TestCut(t *testing.T) {
+	sep := regexp.MustCompile(`\s*(\d+)\s*`)
+	var n int
+	before, after, err := re.Cut(sep, []byte("abc 12 def 34"), &n)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(before) != "abc" || string(after) != "def 34" || n != 12 {
+		t.Errorf("Cut = %q, %q, %d; expected \"abc\", \"def 34\", 12", before, after, n)
+	}
+	before, after, err = re.Cut(sep, []byte("none"), &n)
+	if !errors.Is(err, re.NotFound) {
+		t.Errorf("Cut error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+	if string(before) != "none" || after != nil {
+		t.Errorf("Cut without a match = %q, %q; expected \"none\", nil", before, after)
+	}
+	var small uint8
+	before, after, err = re.Cut(sep, []byte("a 999 b"), &small)
+	if err == nil || errors.Is(err, re.NotFound) {
+		t.Errorf("Cut of out of range number: got error %v", err)
+	}
+	if string(before) != "a" || string(after) != "b" {
+		t.Errorf("Cut with parse error = %q, %q; expected \"a\", \"b\"", before, after)
+	}
+}
+
+func TestScanLast(t *testing.T) {
+	total := regexp.MustCompile(`(?m)^Total: (\d+)$`)
+	var n int
+	input := []byte("Total: 1\nitem\nTotal: 2\nTotal: 30\nend")
+	if err := re.ScanLast(total, input, &n); err != nil || n != 30 {
+		t.Errorf("ScanLast = %d, %v; expected 30", n, err)
+	}
+	var span re.Span
+	if err := re.ScanLast(regexp.MustCompile(`(\d+)`), []byte("a 12"), &span); err != nil || span != (re.Span{2, 4}) {
+		t.Errorf("ScanLast span = %v, %v; expected {2 4}", span, err)
+	}
+	if err := re.ScanLast(total, []byte("none"), &n); !errors.Is(err, re.NotFound) {
+		t.Errorf("ScanLast error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+	var small int8
+	if err := re.ScanLast(total, []byte("Total: 1\nTotal: 300"), &small); err == nil || errors.Is(err, re.NotFound) {
+		t.Errorf("ScanLast of out of range number: got error %v", err)
+	}
+}
+
+func TestScanAt(t *testing.T) {
+	input := []byte("key=12 other=34")
+	kv := regexp.MustCompile(`^(\w+)=(\d+)`)
+	var key string
+	var value int
+	var span re.Span
+	if err := re.ScanAt(kv, input, 7, &key, &value, re.Group(0, &span)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if key != "other" || value != 34 || span != (re.Span{7, 15}) {
+		t.Errorf("ScanAt = %q, %d, %v; expected other, 34, {7 15}", key, value, span)
+	}
+	if string(input[span.Start:span.End]) != "other=34" {
+		t.Errorf("span does not slice input: %q", input[span.Start:span.End])
+	}
+
+	// A match later in the input does not count.
+	unanchored := regexp.MustCompile(`(\d+)`)
+	for _, offset := range []int{0, 3, 6, -1, 16} {
+		if err := re.ScanAt(unanchored, input, offset, &value); !errors.Is(err, re.NotFound) {
+			t.Errorf("ScanAt(%d) error was %v, want an error that wraps %v", offset, err, re.NotFound)
+		}
+	}
+	if err := re.ScanAt(unanchored, input, 5, &value); err != nil || value != 2 {
+		t.Errorf("ScanAt(5) = %d, %v; expected 2", value, err)
+	}
+	if err := re.ScanAt(regexp.MustCompile(`(\d*)$`), input, 15, &key); err != nil || key != "" {
+		t.Errorf("ScanAt at end of input = %q, %v", key, err)
+	}
+}
+
+func TestScanFull(t *testing.T) {
+	port := regexp.MustCompile(`(\d+)`)
+	var n int
+	if err := re.ScanFull(port, []byte("8080"), &n); err != nil || n != 8080 {
+		t.Errorf("ScanFull = %d, %v; expected 8080", n, err)
+	}
+	for _, bad := range []string{"80x", "x80", " 80", ""} {
+		if err := re.ScanFull(port, []byte(bad), &n); !errors.Is(err, re.NotFound) {
+			t.Errorf("ScanFull(%q) error was %v, want an error that wraps %v", bad, err, re.NotFound)
+		}
+	}
+
+	// Alternatives that leftmost-first matching would not prefer.
+	var s string
+	if err := re.ScanFull(regexp.MustCompile(`(a|ab)`), []byte("ab"), &s); err != nil || s != "ab" {
+		t.Errorf("ScanFull(a|ab) = %q, %v; expected ab", s, err)
+	}
+	// A pattern's own anchors and alternations are kept intact.
+	if err := re.ScanFull(regexp.MustCompile(`^x|(y)$`), []byte("y"), &s); err != nil || s != "y" {
+		t.Errorf("ScanFull(^x|(y)$) = %q, %v; expected y", s, err)
+	}
+	// Leftmost-longest regular expressions are used as they are.
+	longest := regexp.MustCompile(`(a|ab)(b*)`)
+	longest.Longest()
+	var rest string
+	if err := re.ScanFull(longest, []byte("abb"), &s, &rest); err != nil || s+rest != "abb" {
+		t.Errorf("ScanFull(longest (a|ab)(b*)) = %q, %q, %v; expected a full match", s, rest, err)
+	}
+	if err := re.ScanFull(longest, []byte("abbc"), &s, &rest); !errors.Is(err, re.NotFound) {
+		t.Errorf("ScanFull(longest, abbc) error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+	var small int8
+	if err := re.ScanFull(port, []byte("999"), &small); err == nil || errors.Is(err, re.NotFound) {
+		t.Errorf("ScanFull of out of range number: got error %v", err)
+	}
+}
+
+func TestNamed(t *testing.T) {
+	pattern := regexp.MustCompile(`^(?P<scheme>\w+)://(?P<host>[^:]+):(?P<port>\d+)$`)
+	input := []byte("http://h:80")
+
+	var scheme, host string
+	var port int
+	if err := re.Scan(pattern, input, re.Named("port", &port), re.Named("host", &host)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if host != "h" || port != 80 {
+		t.Errorf("Scan extracted %s, %d; expected h, 80", host, port)
+	}
+
+	// Mixed with positional outputs, which keep their positions.
+	var portText string
+	port = 0
+	if err := re.Scan(pattern, input, &scheme, re.Named("port", &port), &portText); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if scheme != "http" || port != 80 || portText != "80" {
+		t.Errorf("Scan extracted %s, %d, %s; expected http, 80, 80", scheme, port, portText)
+	}
+
+	// Unknown names.
+	host = ""
+	if err := re.Scan(pattern, input, &host, re.Named("prot", &port)); err == nil {
+		t.Errorf("Scan with unknown name succeeded unexpectedly")
+	}
+	if host != "" {
+		t.Errorf("Scan with unknown name stored %q", host)
+	}
+	if _, err := re.Bind(pattern, re.Named("prot", &port)); err == nil {
+		t.Errorf("Bind with unknown name succeeded unexpectedly")
+	}
+
+	// ScanAll and Scanner.
+	var ports []int
+	if _, err := re.ScanAll(pattern, input, re.Named("port", &ports)); err != nil || len(ports) != 1 || ports[0] != 80 {
+		t.Errorf("ScanAll extracted %v, %v; expected [80]", ports, err)
+	}
+	pair := regexp.MustCompile(`(?P<a>\d+)-(?P<b>\d+)`)
+	var xs, ys, zs []int
+	if _, err := re.ScanAll(pair, []byte("1-2 3-4"), re.Named("a", &xs), re.Named("b", &ys), re.Group(1, &zs)); err != nil ||
+		fmt.Sprint(xs, ys, zs) != "[1 3] [2 4] [1 3]" {
+		t.Errorf("ScanAll with more selected outputs than groups extracted %v %v %v, %v", xs, ys, zs, err)
+	}
+	port = 0
+	s, err := re.Bind(pattern, re.Named("port", &port))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := s.Scan(input); err != nil || port != 80 {
+		t.Errorf("Scanner extracted %d, %v; expected 80", port, err)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	pattern := regexp.MustCompile(`^(\w+) (\w+) (\w+) (\d+)$`)
+	input := []byte("a b c 17")
+
+	var n int
+	var first, all string
+	if err := re.Scan(pattern, input, re.Group(4, &n)); err != nil || n != 17 {
+		t.Errorf("Scan(Group(4)) extracted %d, %v; expected 17", n, err)
+	}
+	if err := re.Scan(pattern, input, &first, re.Group(0, &all)); err != nil || first != "a" || all != "a b c 17" {
+		t.Errorf("Scan(first, Group(0)) extracted %q, %q, %v", first, all, err)
+	}
+	for _, i := range []int{-1, 5} {
+		if err := re.Scan(pattern, input, re.Group(i, &n)); err == nil {
+			t.Errorf("Scan(Group(%d)) succeeded unexpectedly", i)
+		}
+		if _, err := re.Bind(pattern, re.Group(i, &n)); err == nil {
+			t.Errorf("Bind(Group(%d)) succeeded unexpectedly", i)
+		}
+	}
+}
+
+func TestPointerToPointer(t *testing.T) {
+	reg := regexp.MustCompile(`user=(\w+)(?: uid=(\d*))?`)
+	var user *string
+	uid := new(int)
+	if err := re.Scan(reg, []byte("user=bob"), &user, &uid); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if user == nil || *user != "bob" || uid != nil {
+		t.Errorf("Scan without uid = %v, %v; expected bob, nil", user, uid)
+	}
+	if err := re.Scan(reg, []byte("user=bob uid=0"), &user, &uid); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if uid == nil || *uid != 0 {
+		t.Errorf("Scan with uid=0 stored %v; expected a pointer to 0", uid)
+	}
+	// A participating empty group is parsed, not treated as absent.
+	if err := re.Scan(reg, []byte("user=bob uid="), &user, &uid); err == nil {
+		t.Errorf("Scan of empty uid succeeded unexpectedly")
+	}
+	if uid == nil || *uid != 0 {
+		t.Errorf("failed Scan changed uid to %v", uid)
+	}
+
+	// Element types are checked even if the group does not participate.
+	type mytype int
+	var bad *mytype
+	if err := re.Scan(reg, []byte("user=bob"), nil, &bad); err == nil {
+		t.Errorf("Scan into **mytype succeeded unexpectedly")
+	}
+
+	// ScanAll collects nil for absent groups.
+	var uids []*int
+	if _, err := re.ScanAll(reg, []byte("user=a uid=1 user=b"), nil, &uids); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(uids) != 2 || uids[0] == nil || *uids[0] != 1 || uids[1] != nil {
+		t.Errorf("ScanAll = %v; expected [1 nil]", uids)
+	}
+}
+
+func TestScanAll(t *testing.T) {
+	pattern := regexp.MustCompile(`(\w+):(\d+)`)
+	var hosts []string
+	var ports []int
+	n, err := re.ScanAll(pattern, []byte("a:1 b:2 c:3"), &hosts, &ports)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 3 {
+		t.Errorf("ScanAll returned %d; expected 3", n)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %v; expected %v", hosts, want)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(ports, want) {
+		t.Errorf("ports = %v; expected %v", ports, want)
+	}
+
+	// Parse failure part way through.
+	hosts, ports = nil, nil
+	pattern = regexp.MustCompile(`(\w+):(\w+)`)
+	n, err = re.ScanAll(pattern, []byte("a:1 b:x c:3"), &hosts, &ports)
+	if err == nil {
+		t.Fatalf("ScanAll succeeded unexpectedly")
+	}
+	if n != 1 || len(hosts) != 1 || len(ports) != 1 {
+		t.Errorf("ScanAll returned %d, %v, %v; expected one match", n, hosts, ports)
+	}
+
+	// No match.
+	if _, err := re.ScanAll(pattern, []byte("-"), &hosts); !errors.Is(err, re.NotFound) {
+		t.Errorf("ScanAll error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+
+	// Output that is not a pointer to a slice.
+	var host string
+	if _, err := re.ScanAll(pattern, []byte("a:1"), &host); err == nil {
+		t.Errorf("ScanAll into *string succeeded unexpectedly")
+	}
+
+	// Spans and functions.
+	var spans []re.Span
+	var count int
+	countMatch := func([]byte) error {
+		count++
+		return nil
+	}
+	pattern = regexp.MustCompile(`((\w+))`)
+	if _, err := re.ScanAll(pattern, []byte("ab cd"), &spans, countMatch); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []re.Span{{0, 2}, {3, 5}}; !reflect.DeepEqual(spans, want) {
+		t.Errorf("spans = %v; expected %v", spans, want)
+	}
+	if count != 2 {
+		t.Errorf("function called %d times; expected 2", count)
+	}
+}
+
+func TestScanAllN(t *testing.T) {
+	pattern := regexp.MustCompile(`(\d+)`)
+	input := []byte("1 2 3 4 5")
+	for _, c := range []struct {
+		n    int
+		want []int
+	}{
+		{-1, []int{1, 2, 3, 4, 5}},
+		{2, []int{1, 2}},
+		{9, []int{1, 2, 3, 4, 5}},
+		{0, nil},
+	} {
+		var got []int
+		count, err := re.ScanAllN(pattern, input, c.n, &got)
+		if err != nil || count != len(c.want) || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ScanAllN(%d) = %d, %v, %v; expected %v", c.n, count, got, err, c.want)
+		}
+	}
+	var got []int
+	if _, err := re.ScanAllN(pattern, []byte("none"), 3, &got); !errors.Is(err, re.NotFound) {
+		t.Errorf("ScanAllN error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+}
+
+func TestRunes(t *testing.T) {
+	var r []rune
+	if err := re.Scan(regexp.MustCompile(`name=(\S*)`), []byte("name=héllo"), &r); err != nil {
+		t.Fatal(err)
+	}
+	if string(r) != "héllo" || len(r) != 5 {
+		t.Errorf("Scan into []rune = %q; expected %q", r, "héllo")
+	}
+	if err := re.Scan(regexp.MustCompile(`^(.*)$`), []byte("a\xff"), &r); err != nil || len(r) != 2 || r[1] != utf8.RuneError {
+		t.Errorf("Scan of invalid UTF-8 into []rune = %q, %v", r, err)
+	}
+}
+
+func TestByteArray(t *testing.T) {
+	all := regexp.MustCompile(`^(.*)$`)
+	var id [4]byte
+	for _, c := range []struct {
+		input string
+		want  [4]byte
+	}{
+		{"abcd", [4]byte{'a', 'b', 'c', 'd'}},
+		{"DEADbeef", [4]byte{0xde, 0xad, 0xbe, 0xef}},
+	} {
+		if err := re.Scan(all, []byte(c.input), &id); err != nil || id != c.want {
+			t.Errorf("Scan(%q) = %x, %v; expected %x", c.input, id, err, c.want)
+		}
+	}
+	for _, input := range []string{"", "abc", "abcde", "deadbeeg"} {
+		id = [4]byte{}
+		if err := re.Scan(all, []byte(input), &id); err == nil {
+			t.Errorf("Scan(%q) = %x; expected error", input, id)
+		}
+	}
+	var ints [2]int
+	if err := re.Scan(all, []byte("ab"), &ints); err == nil {
+		t.Errorf("Scan into [2]int succeeded; expected error")
+	}
+}
+
+// point implements fmt.Scanner for text such as "(1,2)".
+type point struct{ x, y int }
+
+func (p *point) Scan(state fmt.ScanState, verb rune) error {
+	_, err := fmt.Fscanf(state, "(%d,%d)", &p.x, &p.y)
+	return err
+}
+
+func TestFmtScanner(t *testing.T) {
+	reg := regexp.MustCompile(`^at (.*)$`)
+	var p point
+	if err := re.Scan(reg, []byte("at (3,-4)"), &p); err != nil || p != (point{3, -4}) {
+		t.Errorf("Scan = %v, %v; expected {3 -4}", p, err)
+	}
+	for _, input := range []string{"at (3,x)", "at (3,4) extra", "at "} {
+		if err := re.Scan(reg, []byte(input), &p); err == nil {
+			t.Errorf("Scan(%q) = %v; expected error", input, p)
+		}
+	}
+}
+
+func TestScanAllocs(t *testing.T) {
+	reg := regexp.MustCompile(`^(\w+):(\d+)$`)
+	input := []byte("host:8080")
+	var s string
+	var n int
+	// One allocation for the match indices and one for the string.
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := re.Scan(reg, input, &s, &n); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 2 {
+		t.Errorf("Scan made %v allocations; expected at most 2", allocs)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Package records writes the records produced by scanning text in forms
+suitable for further processing.  The typed records produced by
+re.ScanTyped (one map[string]interface{} per matched input) can be
+written as CSV or TSV text via encoding/csv, or gathered into
+column-oriented batches that can be handed to a Parquet or Arrow writer.
+Struct records filled in by re.Unmarshal can be inserted into a
+database table in batches.
+
+For example, a log file can be converted to CSV as follows:
+
+	reg := regexp.MustCompile(`^(?P<host>\S+) (?P<status_int>\d+) (?P<took_duration>\S+)$`)
+	w := records.NewCSVWriter(os.Stdout, []string{"host", "status", "took"})
+	for _, line := range lines {
+		rec, err := re.ScanTyped(reg, line)
+		if err != nil {
+			continue
+		}
+		if err := w.Write(rec); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+*/
+package records
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+)
+
+// A Writer writes records as delimited text with a fixed set of
+// columns.  The first call to Write also writes a header row holding
+// the column names.
+type Writer struct {
+	w       *csv.Writer
+	columns []string
+	header  bool // Has the header row been written?
+	row     []string
+}
+
+// NewCSVWriter returns a Writer that writes comma-separated records
+// with the named columns to w.
+func NewCSVWriter(w io.Writer, columns []string) *Writer {
+	return &Writer{w: csv.NewWriter(w), columns: columns}
+}
+
+// NewTSVWriter returns a Writer that writes tab-separated records with
+// the named columns to w.
+func NewTSVWriter(w io.Writer, columns []string) *Writer {
+	cw := csv.NewWriter(w)
+	cw.Comma = '\t'
+	return &Writer{w: cw, columns: columns}
+}
+
+// Write writes one record.  The value for each column is formatted with
+// Format; columns missing from the record are left empty, and record
+// entries that do not correspond to a column are ignored.
+func (w *Writer) Write(record map[string]interface{}) error {
+	if !w.header {
+		if err := w.w.Write(w.columns); err != nil {
+			return err
+		}
+		w.header = true
+	}
+	w.row = w.row[:0]
+	for _, c := range w.columns {
+		v, ok := record[c]
+		if !ok {
+			w.row = append(w.row, "")
+			continue
+		}
+		w.row = append(w.row, Format(v))
+	}
+	return w.w.Write(w.row)
+}
+
+// Flush writes any buffered data to the underlying io.Writer.  Call
+// Error to check whether the flush succeeded.
+func (w *Writer) Flush() {
+	w.w.Flush()
+}
+
+// Error reports any error that occurred during a previous Write or
+// Flush.
+func (w *Writer) Error() error {
+	return w.w.Error()
+}
+
+// Format returns the textual form used for v in CSV and TSV output.
+// Times are formatted with time.RFC3339Nano and everything else with
+// fmt.Sprint; e.g., a time.Duration is formatted as "1m30s".
+func Format(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// A Batch accumulates records in column-oriented form, the layout
+// expected by columnar formats such as Parquet and Arrow.
+type Batch struct {
+	Columns []*Column
+	Len     int // Number of records added to the batch
+}
+
+// A Column holds the values of one field across all records in a Batch.
+type Column struct {
+	Name string
+
+	// Values is a slice (e.g., []int64 or []time.Time) holding one
+	// element per record.  Its element type is fixed by the first
+	// record that has a value for the column; until then it is nil.
+	// Missing values are stored as the zero value of the element type.
+	Values interface{}
+
+	// Valid[i] reports whether record i had a value for the column.
+	Valid []bool
+}
+
+// NewBatch returns an empty Batch with the named columns.
+func NewBatch(columns []string) *Batch {
+	b := &Batch{}
+	for _, c := range columns {
+		b.Columns = append(b.Columns, &Column{Name: c})
+	}
+	return b
+}
+
+// Add appends one record to the batch.  A nil value is treated like a
+// missing one.  Add returns an error, and leaves the batch unchanged, if
+// the record holds a value whose type differs from that of earlier
+// values in the same column.
+func (b *Batch) Add(record map[string]interface{}) error {
+	for _, c := range b.Columns {
+		v := record[c.Name]
+		if v != nil && c.Values != nil && reflect.TypeOf(c.Values).Elem() != reflect.TypeOf(v) {
+			return fmt.Errorf("records: column %s: value of type %T in a column of type %s",
+				c.Name, v, reflect.TypeOf(c.Values).Elem())
+		}
+	}
+	for _, c := range b.Columns {
+		v := record[c.Name]
+		ok := v != nil
+		if ok && c.Values == nil {
+			// First value: back-fill earlier records with zero values.
+			c.Values = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(v)), b.Len, b.Len).Interface()
+		}
+		if c.Values != nil {
+			s := reflect.ValueOf(c.Values)
+			e := reflect.Zero(s.Type().Elem())
+			if ok {
+				e = reflect.ValueOf(v)
+			}
+			c.Values = reflect.Append(s, e).Interface()
+		}
+		c.Valid = append(c.Valid, ok)
+	}
+	b.Len++
+	return nil
+}
+
+// Reset empties the batch so that it can be reused for the next group
+// of records.  Column types are forgotten.
+func (b *Batch) Reset() {
+	for _, c := range b.Columns {
+		c.Values = nil
+		c.Valid = nil
+	}
+	b.Len = 0
+}
@@ -0,0 +1,87 @@
+package records_test
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/records"
+)
+
+func scanAll(t *testing.T, lines ...string) []map[string]interface{} {
+	reg := regexp.MustCompile(`^(?P<host>\S+) (?P<status_int>\d+)(?: (?P<took_duration>\S+))?$`)
+	var result []map[string]interface{}
+	for _, l := range lines {
+		rec, err := re.ScanTyped(reg, []byte(l))
+		if err != nil {
+			t.Fatalf("ScanTyped(%q): unexpected error: %s", l, err)
+		}
+		result = append(result, rec)
+	}
+	return result
+}
+
+func TestCSVWriter(t *testing.T) {
+	for _, c := range []struct {
+		tsv  bool
+		want string
+	}{
+		{false, "host,status,took\na,200,1.5s\n\"b,c\",404,\n"},
+		{true, "host\tstatus\ttook\na\t200\t1.5s\nb,c\t404\t\n"},
+	} {
+		var buf bytes.Buffer
+		columns := []string{"host", "status", "took"}
+		w := records.NewCSVWriter(&buf, columns)
+		if c.tsv {
+			w = records.NewTSVWriter(&buf, columns)
+		}
+		for _, rec := range scanAll(t, "a 200 1.5s", "b,c 404") {
+			if err := w.Write(rec); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got := buf.String(); got != c.want {
+			t.Errorf("output is %q; expected %q", got, c.want)
+		}
+	}
+}
+
+func TestBatch(t *testing.T) {
+	b := records.NewBatch([]string{"host", "took", "missing"})
+	for _, rec := range scanAll(t, "a 200", "b 404 2s") {
+		if err := b.Add(rec); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if err := b.Add(map[string]interface{}{"host": 17}); err == nil {
+		t.Errorf("Add of mismatched type succeeded unexpectedly")
+	}
+	if b.Len != 2 {
+		t.Errorf("batch length is %d; expected 2", b.Len)
+	}
+	for i, want := range []struct {
+		values interface{}
+		valid  []bool
+	}{
+		{[]string{"a", "b"}, []bool{true, true}},
+		{[]time.Duration{0, 2 * time.Second}, []bool{false, true}},
+		{nil, []bool{false, false}},
+	} {
+		c := b.Columns[i]
+		if !reflect.DeepEqual(c.Values, want.values) || !reflect.DeepEqual(c.Valid, want.valid) {
+			t.Errorf("column %s is %v %v; expected %v %v", c.Name, c.Values, c.Valid, want.values, want.valid)
+		}
+	}
+
+	b.Reset()
+	if b.Len != 0 || b.Columns[0].Values != nil {
+		t.Errorf("Reset did not empty the batch")
+	}
+}
@@ -0,0 +1,185 @@
+package records
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// A Batcher collects rows and passes them to Flush in groups of Size.
+// A typical Flush inserts the rows into a database; see Inserter.
+type Batcher struct {
+	Size  int                            // Number of rows per batch (at least one)
+	Flush func(rows []interface{}) error // Called with each full batch
+
+	rows []interface{}
+}
+
+// Add adds row to the current batch, and calls Flush if the batch is
+// now full.  The error returned by Flush, if any, is returned.
+func (b *Batcher) Add(row interface{}) error {
+	b.rows = append(b.rows, row)
+	if len(b.rows) < b.Size {
+		return nil
+	}
+	return b.flush()
+}
+
+// Close passes any remaining rows to Flush.
+func (b *Batcher) Close() error {
+	if len(b.rows) == 0 {
+		return nil
+	}
+	return b.flush()
+}
+
+func (b *Batcher) flush() error {
+	rows := b.rows
+	b.rows = nil
+	return b.Flush(rows)
+}
+
+// An Execer executes SQL statements.  It is implemented by *sql.DB,
+// *sql.Tx and *sql.Conn.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// An Inserter inserts struct records, such as those filled in by
+// re.Unmarshal, into a database table.  Each exported field becomes a
+// column named by its "re" struct tag, or by the field name if it has
+// no tag; fields tagged `re:"-"` are skipped.  The table and column
+// names are placed in the statement verbatim, so they must not come
+// from untrusted input.
+//
+// Its Insert method is suitable for use as Batcher.Flush:
+//
+//	ins := &records.Inserter{DB: db, Table: "requests"}
+//	b := &records.Batcher{Size: 500, Flush: ins.Insert}
+//	for _, line := range lines {
+//		var r Request
+//		if err := re.Unmarshal(reg, line, &r); err == nil {
+//			if err := b.Add(r); err != nil {
+//				return err
+//			}
+//		}
+//	}
+//	return b.Close()
+type Inserter struct {
+	DB    Execer
+	Table string
+
+	// Context is passed to DB.ExecContext; if nil,
+	// context.Background() is used.
+	Context context.Context
+
+	// Placeholder returns the parameter placeholder for the i'th
+	// (counting from one) argument of a statement.  If nil, "?" is
+	// used; PostgreSQL drivers need DollarPlaceholder.
+	Placeholder func(i int) string
+
+	// OnError sets the per-row error policy.  If nil, a failing batch
+	// insert is returned as an error.  Otherwise a failing batch is
+	// retried one row at a time, and OnError is called for each row
+	// that still fails: if OnError returns nil the row is skipped,
+	// otherwise insertion stops and that error is returned.
+	OnError func(row interface{}, err error) error
+}
+
+// DollarPlaceholder returns PostgreSQL style placeholders: $1, $2, ...
+func DollarPlaceholder(i int) string {
+	return fmt.Sprintf("$%d", i)
+}
+
+// Insert inserts rows with a single multi-row INSERT statement, subject
+// to the OnError policy.  Each row must be a struct or a pointer to a
+// struct, and all rows must have the same type.
+func (ins *Inserter) Insert(rows []interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	err := ins.exec(rows)
+	if err == nil || ins.OnError == nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := ins.exec([]interface{}{row}); err != nil {
+			if err := ins.OnError(row, err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ins *Inserter) exec(rows []interface{}) error {
+	t := structType(rows[0])
+	if t == nil {
+		return fmt.Errorf("records: Insert needs structs or pointers to structs; got %T", rows[0])
+	}
+	var columns []string
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, ok := f.Tag.Lookup("re")
+		if name == "-" {
+			continue
+		}
+		if !ok {
+			name = f.Name
+		}
+		columns = append(columns, name)
+		fields = append(fields, i)
+	}
+
+	placeholder := ins.Placeholder
+	if placeholder == nil {
+		placeholder = func(int) string { return "?" }
+	}
+	var q strings.Builder
+	fmt.Fprintf(&q, "INSERT INTO %s (%s) VALUES ", ins.Table, strings.Join(columns, ", "))
+	args := make([]interface{}, 0, len(rows)*len(fields))
+	for r, row := range rows {
+		if structType(row) != t {
+			return fmt.Errorf("records: Insert of mixed row types %s and %T", t, row)
+		}
+		v := reflect.Indirect(reflect.ValueOf(row))
+		if r > 0 {
+			q.WriteString(", ")
+		}
+		q.WriteString("(")
+		for i, f := range fields {
+			if i > 0 {
+				q.WriteString(", ")
+			}
+			args = append(args, v.Field(f).Interface())
+			q.WriteString(placeholder(len(args)))
+		}
+		q.WriteString(")")
+	}
+
+	ctx := ins.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, err := ins.DB.ExecContext(ctx, q.String(), args...)
+	return err
+}
+
+// structType returns the struct type of row (which may be a pointer to
+// a struct), or nil if row is not a struct.
+func structType(row interface{}) reflect.Type {
+	t := reflect.TypeOf(row)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,120 @@
+package records_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/records"
+)
+
+// fakeDB records the statements it executes, failing any statement
+// with an argument equal to fail.
+type fakeDB struct {
+	fail  interface{}
+	stmts []string
+}
+
+func (db *fakeDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	for _, a := range args {
+		if a == db.fail {
+			return nil, errors.New("constraint violated")
+		}
+	}
+	db.stmts = append(db.stmts, fmt.Sprint(query, " ", args))
+	return nil, nil
+}
+
+type request struct {
+	Host   string `re:"host"`
+	Status int    `re:"status"`
+	Note   string `re:"-"`
+}
+
+func scanRequests(t *testing.T, lines ...string) []request {
+	reg := regexp.MustCompile(`^(?P<host>\S+) (?P<status>\d+)$`)
+	var result []request
+	for _, l := range lines {
+		var r request
+		if err := re.Unmarshal(reg, []byte(l), &r); err != nil {
+			t.Fatalf("Unmarshal(%q): unexpected error: %s", l, err)
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+func TestInserter(t *testing.T) {
+	db := &fakeDB{}
+	ins := &records.Inserter{DB: db, Table: "requests"}
+	b := &records.Batcher{Size: 2, Flush: ins.Insert}
+	for _, r := range scanRequests(t, "a 200", "b 404", "c 500") {
+		if err := b.Add(r); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{
+		"INSERT INTO requests (host, status) VALUES (?, ?), (?, ?) [a 200 b 404]",
+		"INSERT INTO requests (host, status) VALUES (?, ?) [c 500]",
+	}
+	if !reflect.DeepEqual(db.stmts, want) {
+		t.Errorf("statements are %q; expected %q", db.stmts, want)
+	}
+}
+
+func TestInserterErrorPolicy(t *testing.T) {
+	rows := []interface{}{}
+	for _, r := range scanRequests(t, "a 200", "bad 404", "c 500") {
+		r := r
+		rows = append(rows, &r)
+	}
+
+	// Without a policy the batch fails.
+	db := &fakeDB{fail: "bad"}
+	ins := &records.Inserter{DB: db, Table: "t", Placeholder: records.DollarPlaceholder}
+	if err := ins.Insert(rows); err == nil {
+		t.Errorf("Insert succeeded unexpectedly")
+	}
+
+	// Skip failing rows.
+	var skipped []string
+	db = &fakeDB{fail: "bad"}
+	ins.DB = db
+	ins.OnError = func(row interface{}, err error) error {
+		skipped = append(skipped, row.(*request).Host)
+		return nil
+	}
+	if err := ins.Insert(rows); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{
+		"INSERT INTO t (host, status) VALUES ($1, $2) [a 200]",
+		"INSERT INTO t (host, status) VALUES ($1, $2) [c 500]",
+	}
+	if !reflect.DeepEqual(db.stmts, want) {
+		t.Errorf("statements are %q; expected %q", db.stmts, want)
+	}
+	if !reflect.DeepEqual(skipped, []string{"bad"}) {
+		t.Errorf("skipped rows are %q; expected [bad]", skipped)
+	}
+
+	// Abort on a failing row.
+	db = &fakeDB{fail: "bad"}
+	ins.DB = db
+	ins.OnError = func(row interface{}, err error) error { return err }
+	if err := ins.Insert(rows); err == nil {
+		t.Errorf("Insert succeeded unexpectedly")
+	}
+
+	if err := ins.Insert([]interface{}{17}); err == nil {
+		t.Errorf("Insert of non-struct succeeded unexpectedly")
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Package redact masks sensitive text, such as credit card numbers and
+email addresses, found by regular expressions.  It is intended for
+scrubbing logs before they are stored or shared:
+
+	r, err := redact.New(redact.CreditCard, redact.Email)
+	clean, found := r.Redact(line)
+
+Each Rule either masks entire matches of its pattern, or only the
+sub-matches of selected named groups, so that context needed to find
+the sensitive text (e.g., "password=") can be left in place.
+
+Writer and Reader apply a Redactor to a stream of text, so that logs can
+be scrubbed before they are written anywhere.
+*/
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/ghemawat/re"
+)
+
+// A Rule describes one kind of sensitive text.
+type Rule struct {
+	// Label identifies the rule in Redactions, e.g., "email".
+	Label string
+
+	// Pattern finds the sensitive text.
+	Pattern *regexp.Regexp
+
+	// Groups names the groups of Pattern whose sub-matches are masked;
+	// New fails if Pattern lacks one of them.  If empty, entire matches
+	// are masked.
+	Groups []string
+
+	// Valid, if non-nil, is called with each candidate (the text that
+	// would be masked); the candidate is left alone unless Valid
+	// returns true.  It allows checks that regular expressions cannot
+	// express, such as the Luhn checksum used by CreditCard.
+	Valid func(b []byte) bool
+}
+
+// A Redaction describes one masked region of the input.
+type Redaction struct {
+	Label string  // Label of the Rule that found the region
+	Span  re.Span // Extent of the region in the input
+}
+
+// A Redactor masks the text found by a set of rules.
+type Redactor struct {
+	rules  []Rule
+	groups [][]int // Indexes of the masked groups of each rule
+
+	// Mask returns the replacement for text found by the rule with the
+	// given label.  If nil, each rune of the text is replaced by '*',
+	// which preserves the layout of the input.
+	Mask func(label string, text []byte) []byte
+}
+
+// New returns a Redactor that applies the given rules.  It returns an
+// error if a rule names a group that its Pattern does not have, since
+// such a rule would silently mask nothing.
+func New(rules ...Rule) (*Redactor, error) {
+	r := &Redactor{rules: rules, groups: make([][]int, len(rules))}
+	for i, rule := range rules {
+		groups, err := groupIndexes(rule)
+		if err != nil {
+			return nil, err
+		}
+		r.groups[i] = groups
+	}
+	return r, nil
+}
+
+// Redact returns a copy of input with the text found by the rules
+// masked, along with a description of each masked region in increasing
+// order of position.  Regions found by different rules that overlap are
+// merged into one region, which is attributed to the rule listed first
+// in New.
+func (r *Redactor) Redact(input []byte) ([]byte, []Redaction) {
+	found := r.Find(input)
+	if len(found) == 0 {
+		return append([]byte(nil), input...), nil
+	}
+	var out bytes.Buffer
+	last := 0
+	for _, f := range found {
+		out.Write(input[last:f.Span.Start])
+		out.Write(r.mask(f.Label, input[f.Span.Start:f.Span.End]))
+		last = f.Span.End
+	}
+	out.Write(input[last:])
+	return out.Bytes(), found
+}
+
+// Find returns the regions of input that Redact would mask, without
+// masking them.
+func (r *Redactor) Find(input []byte) []Redaction {
+	type candidate struct {
+		Redaction
+		rule int
+	}
+	var all []candidate
+	for i, rule := range r.rules {
+		groups := r.groups[i]
+		for _, m := range rule.Pattern.FindAllSubmatchIndex(input, -1) {
+			for _, g := range groups {
+				s := re.Span{Start: m[2*g], End: m[2*g+1]}
+				if s.Start < 0 || s.Start == s.End {
+					continue
+				}
+				if rule.Valid != nil && !rule.Valid(input[s.Start:s.End]) {
+					continue
+				}
+				all = append(all, candidate{Redaction{rule.Label, s}, i})
+			}
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Span.Start != all[j].Span.Start {
+			return all[i].Span.Start < all[j].Span.Start
+		}
+		return all[i].rule < all[j].rule
+	})
+
+	// Merge overlapping regions.
+	var result []Redaction
+	var rules []int // Rule of each entry in result
+	for _, c := range all {
+		if n := len(result); n > 0 && c.Span.Start < result[n-1].Span.End {
+			last := &result[n-1]
+			if c.Span.End > last.Span.End {
+				last.Span.End = c.Span.End
+			}
+			if c.rule < rules[n-1] {
+				last.Label, rules[n-1] = c.Label, c.rule
+			}
+			continue
+		}
+		result = append(result, c.Redaction)
+		rules = append(rules, c.rule)
+	}
+	return result
+}
+
+func (r *Redactor) mask(label string, text []byte) []byte {
+	if r.Mask != nil {
+		return r.Mask(label, text)
+	}
+	return bytes.Repeat([]byte("*"), utf8.RuneCount(text))
+}
+
+// groupIndexes returns the indexes (into the result of
+// FindSubmatchIndex) of the groups masked by rule, or an error if rule
+// names a group that its pattern does not have.
+func groupIndexes(rule Rule) ([]int, error) {
+	if len(rule.Groups) == 0 {
+		return []int{0}, nil
+	}
+	var result []int
+	for _, g := range rule.Groups {
+		found := false
+		for i, name := range rule.Pattern.SubexpNames() {
+			if i > 0 && name == g {
+				result = append(result, i)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("redact: rule %q: no group named %q in %s", rule.Label, g, rule.Pattern)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package redact_test
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/redact"
+)
+
+func TestRedact(t *testing.T) {
+	password := redact.Rule{
+		Label:   "password",
+		Pattern: regexp.MustCompile(`password=(?P<secret>\S+)`),
+		Groups:  []string{"secret"},
+	}
+	r, err := redact.New(redact.CreditCard, redact.Email, redact.SSN, redact.IPv4, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		input string
+		want  string
+		found []redact.Redaction
+	}{
+		{"nothing here", "nothing here", nil},
+		{
+			"card 4111 1111 1111 1111 by bob@example.com",
+			"card ******************* by ***************",
+			[]redact.Redaction{{"credit-card", re.Span{Start: 5, End: 24}}, {"email", re.Span{Start: 28, End: 43}}},
+		},
+		{"order 4111 1111 1111 1112", "order 4111 1111 1111 1112", nil}, // Fails Luhn
+		{"ssn 123-45-6789 not 000-12-3456", "ssn *********** not 000-12-3456", []redact.Redaction{{"ssn", re.Span{Start: 4, End: 15}}}},
+		{"login password=hunter2 from 10.1.2.3", "login password=******* from ********",
+			[]redact.Redaction{{"password", re.Span{Start: 15, End: 22}}, {"ipv4", re.Span{Start: 28, End: 36}}}},
+		// Overlapping regions are merged and attributed to the first rule.
+		{"password=a@example.com", "password=*************",
+			[]redact.Redaction{{"email", re.Span{Start: 9, End: 22}}}},
+	} {
+		got, found := r.Redact([]byte(c.input))
+		if string(got) != c.want || !reflect.DeepEqual(found, c.found) {
+			t.Errorf("Redact(%q) = %q, %v; expected %q, %v", c.input, got, found, c.want, c.found)
+		}
+	}
+
+	// Custom masks.
+	r.Mask = func(label string, text []byte) []byte { return []byte("[" + label + "]") }
+	if got, _ := r.Redact([]byte("mail bob@example.com now")); string(got) != "mail [email] now" {
+		t.Errorf("Redact with custom mask = %q", got)
+	}
+
+	// The input is not modified.
+	input := []byte("bob@example.com")
+	r.Redact(input)
+	if string(input) != "bob@example.com" {
+		t.Errorf("Redact modified its input")
+	}
+
+	// A group that does not exist is reported rather than masking nothing.
+	_, err = redact.New(redact.Rule{
+		Label:   "password",
+		Pattern: regexp.MustCompile(`password=(?P<secret>\S+)`),
+		Groups:  []string{"secret", "pasword"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "pasword") {
+		t.Errorf("New with unknown group: error %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package redact
+
+import "regexp"
+
+// CreditCard finds payment card numbers: 13 to 19 digits, optionally
+// separated into groups by single spaces or dashes, that pass the Luhn
+// checksum.
+var CreditCard = Rule{
+	Label:   "credit-card",
+	Pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+	Valid:   luhn,
+}
+
+// Email finds email addresses.
+var Email = Rule{
+	Label:   "email",
+	Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`),
+}
+
+// SSN finds United States social security numbers written as
+// AAA-GG-SSSS, excluding the ranges that are never issued.
+var SSN = Rule{
+	Label:   "ssn",
+	Pattern: regexp.MustCompile(`\b(\d{3})-(\d{2})-(\d{4})\b`),
+	Valid: func(b []byte) bool {
+		area, group, serial := string(b[0:3]), string(b[4:6]), string(b[7:11])
+		return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+	},
+}
+
+// IPv4 finds dotted-quad IPv4 addresses.
+var IPv4 = Rule{
+	Label:   "ipv4",
+	Pattern: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`),
+}
+
+// luhn reports whether the digits in b (ignoring other characters)
+// pass the Luhn checksum.
+func luhn(b []byte) bool {
+	sum, n := 0, 0
+	for i := len(b) - 1; i >= 0; i-- {
+		c := b[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
@@ -0,0 +1,142 @@
+package redact
+
+import "io"
+
+// DefaultWindow is the window used by NewWriter and NewReader when the
+// window passed to them is not positive.
+const DefaultWindow = 4096
+
+// stream redacts a sequence of chunks of text.  It holds back the last
+// window bytes it has seen (more if a match straddles that point), since
+// text arriving later may extend them into sensitive text.
+type stream struct {
+	r       *Redactor
+	window  int
+	report  func(Redaction)
+	pending []byte // Text not yet redacted
+	offset  int    // Offset in the whole text of pending[0]
+}
+
+// process adds p to the text seen so far and returns the redacted text
+// that can be released.  If final is true, everything is released.
+func (s *stream) process(p []byte, final bool) []byte {
+	s.pending = append(s.pending, p...)
+	cut := len(s.pending)
+	if !final {
+		cut -= s.window
+		if cut <= 0 {
+			return nil
+		}
+	}
+	found := s.r.Find(s.pending)
+	var out []byte
+	last := 0
+	for _, f := range found {
+		if f.Span.Start >= cut {
+			break
+		}
+		if f.Span.End > cut && !final {
+			// Wait for more text in case the match grows.
+			cut = f.Span.Start
+			break
+		}
+		out = append(out, s.pending[last:f.Span.Start]...)
+		out = append(out, s.r.mask(f.Label, s.pending[f.Span.Start:f.Span.End])...)
+		last = f.Span.End
+		if s.report != nil {
+			f.Span.Start += s.offset
+			f.Span.End += s.offset
+			s.report(f)
+		}
+	}
+	out = append(out, s.pending[last:cut]...)
+	s.pending = append(s.pending[:0], s.pending[cut:]...)
+	s.offset += cut
+	return out
+}
+
+// A Writer redacts the text written to it before passing it on to an
+// underlying io.Writer, so that sensitive text never reaches it.  Text
+// is held back until it is at least window bytes away from the end of
+// the text written so far, so that sensitive text split across several
+// calls to Write is still found, as long as each match of a rule is at
+// most window bytes long.  Call Close to flush the remaining text.
+type Writer struct {
+	w io.Writer
+	s stream
+}
+
+// NewWriter returns a Writer that writes the text written to it, as
+// redacted by r, to w.  If window is not positive, DefaultWindow is
+// used.
+func NewWriter(w io.Writer, r *Redactor, window int) *Writer {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Writer{w: w, s: stream{r: r, window: window}}
+}
+
+// Report arranges for f to be called with each redaction, with spans
+// measured from the start of all the text written.
+func (w *Writer) Report(f func(Redaction)) {
+	w.s.report = f
+}
+
+// Write redacts p and writes the text that is ready to the underlying
+// writer.  It returns len(p) unless the underlying writer fails.
+func (w *Writer) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(w.s.process(p, false)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close redacts and writes any remaining text.  It does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	_, err := w.w.Write(w.s.process(nil, true))
+	return err
+}
+
+// A Reader redacts the text read from an underlying io.Reader; see
+// Writer for how text split across reads is handled.
+type Reader struct {
+	r     io.Reader
+	s     stream
+	chunk []byte // Space for reading from r
+	buf   []byte // Redacted text not yet returned
+	err   error  // Error from r
+}
+
+// NewReader returns a Reader that returns the text read from src, as
+// redacted by r.  If window is not positive, DefaultWindow is used.
+func NewReader(src io.Reader, r *Redactor, window int) *Reader {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Reader{r: src, s: stream{r: r, window: window}}
+}
+
+// Report arranges for f to be called with each redaction, with spans
+// measured from the start of the text read.
+func (r *Reader) Report(f func(Redaction)) {
+	r.s.report = f
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.chunk == nil {
+			r.chunk = make([]byte, r.s.window)
+		}
+		n, err := r.r.Read(r.chunk)
+		r.err = err
+		r.buf = r.s.process(r.chunk[:n], err != nil)
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
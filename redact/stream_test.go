@@ -0,0 +1,62 @@
+package redact_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/ghemawat/re/redact"
+)
+
+func TestWriter(t *testing.T) {
+	input := strings.Repeat("mail bob@example.com card 4111-1111-1111-1111 ok\n", 20)
+	r, err := redact.New(redact.Email, redact.CreditCard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, wantFound := r.Redact([]byte(input))
+
+	for _, size := range []int{1, 7, 64, len(input)} {
+		var buf bytes.Buffer
+		w := redact.NewWriter(&buf, r, 32)
+		var found []redact.Redaction
+		w.Report(func(x redact.Redaction) { found = append(found, x) })
+		for i := 0; i < len(input); i += size {
+			end := i + size
+			if end > len(input) {
+				end = len(input)
+			}
+			if n, err := w.Write([]byte(input[i:end])); err != nil || n != end-i {
+				t.Fatalf("Write returned %d, %v", n, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if buf.String() != string(want) {
+			t.Errorf("writes of %d bytes produced %q; expected %q", size, buf.String(), want)
+		}
+		if !reflect.DeepEqual(found, wantFound) {
+			t.Errorf("writes of %d bytes reported %v; expected %v", size, found, wantFound)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	input := strings.Repeat("x=bob@example.com ", 50)
+	r, err := redact.New(redact.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := r.Redact([]byte(input))
+	got, err := ioutil.ReadAll(redact.NewReader(iotest.HalfReader(strings.NewReader(input)), r, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Reader produced %q; expected %q", got, want)
+	}
+}
@@ -0,0 +1,40 @@
+package re
+
+import (
+	"reflect"
+	"sync"
+)
+
+// registry holds the parsers added by RegisterParser.  It maps a type T
+// to a function that returns the assigner for a *T.
+var registry struct {
+	sync.RWMutex
+	parsers map[reflect.Type]func(interface{}) assigner
+}
+
+// register arranges for outputs of type *t to be filled in by the
+// assigner returned by newAssigner.
+func register(t reflect.Type, newAssigner func(interface{}) assigner) {
+	registry.Lock()
+	defer registry.Unlock()
+	if registry.parsers == nil {
+		registry.parsers = map[reflect.Type]func(interface{}) assigner{}
+	}
+	registry.parsers[t] = newAssigner
+}
+
+// registeredAssigner returns the assigner for r if r is a pointer to a
+// type with a registered parser.
+func registeredAssigner(r interface{}) (assigner, bool) {
+	t := reflect.TypeOf(r)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, false
+	}
+	registry.RLock()
+	newAssigner, ok := registry.parsers[t.Elem()]
+	registry.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return newAssigner(r), true
+}
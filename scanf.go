@@ -0,0 +1,189 @@
+package re
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Scanf is like Scan, but instead of a regular expression it takes a
+// format in the style of fmt.Sscanf, which is translated into a regular
+// expression with one sub-match per verb:
+//
+//	var host string
+//	var port int
+//	err := re.Scanf("%s:%d", input, &host, &port)
+//
+// The verbs are:
+//
+//	%s  a run of non-space characters
+//	%d  a decimal integer with an optional sign; leading zeros do not
+//	    make it octal
+//	%x  a hexadecimal integer with an optional sign and 0x prefix,
+//	    which need not be present for it to be parsed as hexadecimal
+//	%f  a decimal floating-point number with an optional exponent
+//	%q  a double-quoted Go string literal, or a back-quoted raw one,
+//	    which is unquoted before being stored
+//	%%  a literal percent sign
+//
+// A run of white space in format matches one or more white space
+// characters in input; all other text matches itself.  The match must
+// start at the beginning of input, but may end before its end.  The
+// sub-matches are stored into output as by Scan, so %s can be stored
+// into a *net.IP or %d into a *uint8.  An error is returned if format
+// is malformed or the number of outputs differs from the number of
+// verbs.  Recently translated formats are cached, so repeated calls with
+// the same format do not compile it again.
+func Scanf(format string, input []byte, output ...interface{}) error {
+	f, err := compileFormat(format)
+	if err != nil {
+		return err
+	}
+	if len(output) != len(f.verbs) {
+		return fmt.Errorf("re.Scanf: format %q has %d verbs; got %d outputs", format, len(f.verbs), len(output))
+	}
+	wrapped := make([]interface{}, len(output))
+	for i, r := range output {
+		wrapped[i] = scanfOutput(f.verbs[i], r)
+	}
+	return Scan(f.re, input, wrapped...)
+}
+
+// CompileScanf returns the regular expression that Scanf uses for
+// format.  It can be passed to the other functions of this package,
+// but note that sub-matches of %d, %x and %q verbs are only converted
+// as described for Scanf when passed to Scanf itself.
+func CompileScanf(format string) (*regexp.Regexp, error) {
+	f, err := compileFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	return f.re, nil
+}
+
+// A scanfFormat is a compiled Scanf format.
+type scanfFormat struct {
+	re    *regexp.Regexp
+	verbs []byte // Verb letter of each sub-match
+}
+
+var scanfVerbs = map[byte]string{
+	's': `(\S+)`,
+	'd': `([-+]?\d+)`,
+	'x': `([-+]?(?:0[xX])?[0-9a-fA-F]+)`,
+	'f': `([-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?)`,
+	'q': "(\"(?:[^\"\\\\\\n]|\\\\.)*\"|`[^`]*`)",
+}
+
+// scanfCache holds recently compiled formats keyed by format string.
+var scanfCache = &fifoMap{size: 256}
+
+func compileFormat(format string) (*scanfFormat, error) {
+	if f, ok := scanfCache.load(format); ok {
+		return f.(*scanfFormat), nil
+	}
+	var b strings.Builder
+	b.WriteString(`\A`)
+	f := &scanfFormat{}
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch {
+		case c == '%':
+			if i+1 == len(format) {
+				return nil, fmt.Errorf("re.Scanf: format %q ends with %%", format)
+			}
+			i++
+			v := format[i]
+			if v == '%' {
+				b.WriteString("%")
+				continue
+			}
+			p, ok := scanfVerbs[v]
+			if !ok {
+				return nil, fmt.Errorf("re.Scanf: unsupported verb %%%c in format %q", v, format)
+			}
+			b.WriteString(p)
+			f.verbs = append(f.verbs, v)
+		case unicode.IsSpace(rune(c)):
+			for i+1 < len(format) && unicode.IsSpace(rune(format[i+1])) {
+				i++
+			}
+			b.WriteString(`\s+`)
+		default:
+			j := i + 1
+			for j < len(format) && format[j] != '%' && !unicode.IsSpace(rune(format[j])) {
+				j++
+			}
+			b.WriteString(regexp.QuoteMeta(format[i:j]))
+			i = j - 1
+		}
+	}
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, err
+	}
+	f.re = re
+	scanfCache.store(format, f)
+	return f, nil
+}
+
+// scanfOutput returns an output argument that converts the sub-match of verb
+// as described for Scanf before storing it into output r.
+func scanfOutput(verb byte, r interface{}) interface{} {
+	switch r.(type) {
+	case nil, *Span:
+		return r
+	case *string, *[]byte:
+		if verb != 'q' {
+			return r // Keep the text as written.
+		}
+	}
+	var convert func(b []byte) ([]byte, error)
+	switch verb {
+	case 'd':
+		convert = func(b []byte) ([]byte, error) {
+			sign, digits := splitSign(b)
+			digits = strings.TrimLeft(digits, "0")
+			if digits == "" {
+				digits = "0"
+			}
+			return []byte(sign + digits), nil
+		}
+	case 'x':
+		convert = func(b []byte) ([]byte, error) {
+			sign, digits := splitSign(b)
+			if !strings.HasPrefix(digits, "0x") && !strings.HasPrefix(digits, "0X") {
+				digits = "0x" + digits
+			}
+			return []byte(sign + digits), nil
+		}
+	case 'q':
+		convert = func(b []byte) ([]byte, error) {
+			s, err := strconv.Unquote(string(b))
+			return []byte(s), err
+		}
+	default:
+		return r
+	}
+	return func(b []byte) error {
+		if b == nil {
+			return assign(r, nil, wrappedSpan(nil))
+		}
+		c, err := convert(b)
+		if err != nil {
+			return parseError(err.Error(), b)
+		}
+		return assign(r, c, wrappedSpan(b))
+	}
+}
+
+// splitSign splits an optional leading sign off b.
+func splitSign(b []byte) (sign, rest string) {
+	s := string(b)
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		return s[:1], s[1:]
+	}
+	return "", s
+}
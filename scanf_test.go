@@ -0,0 +1,84 @@
+package re_test
+
+import (
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestScanf(t *testing.T) {
+	var host, name, raw string
+	var port int
+	var mode uint16
+	var ratio float64
+	var ip net.IP
+	var id big.Int
+	if err := re.Scanf("%s:%d", []byte("example.com:080 rest"), &host, &port); err != nil {
+		t.Fatal(err)
+	}
+	if host != "example.com" || port != 80 {
+		t.Errorf("got %q, %d; expected example.com, 80", host, port)
+	}
+
+	input := []byte(`user=ff  ratio=-1.5e3 name="a \"b\"" id=-0xDEADBEEFDEADBEEF from 10.0.0.1 100%`)
+	err := re.Scanf(`user=%x ratio=%f name=%q id=%x from %s %d%%`, input, &mode, &ratio, &name, &id, &ip, &port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode != 0xff || ratio != -1500 || name != `a "b"` || id.String() != "-16045690984833335023" ||
+		!ip.Equal(net.IPv4(10, 0, 0, 1)) || port != 100 {
+		t.Errorf("got %d %v %q %v %v %d", mode, ratio, name, &id, ip, port)
+	}
+
+	// %d and %x sub-matches are stored as written into strings.
+	if err := re.Scanf("%d %x %q", []byte("007 ff `raw`"), &host, &name, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if host != "007" || name != "ff" || raw != "raw" {
+		t.Errorf("got %q %q %q", host, name, raw)
+	}
+
+	var span re.Span
+	if err := re.Scanf("[%s]", []byte("[abc]"), &span); err != nil || span != (re.Span{1, 4}) {
+		t.Errorf("Scanf into Span: got %v, %v", span, err)
+	}
+}
+
+func TestScanfErrors(t *testing.T) {
+	var s string
+	var i int8
+	for _, c := range []struct {
+		format, input string
+		output        []interface{}
+		notFound      bool
+	}{
+		{"%s:%d", "nocolon", []interface{}{&s, &i}, true},
+		{"x=%d", "y x=1", []interface{}{&i}, true}, // Anchored at the start
+		{"%d", "300", []interface{}{&i}, false},
+		{"%q", `"\z"`, []interface{}{&s}, false},
+		{"%s %d", "a 1", []interface{}{&s}, false},
+		{"%y", "a", []interface{}{&s}, false},
+		{"abc%", "abc", nil, false},
+	} {
+		err := re.Scanf(c.format, []byte(c.input), c.output...)
+		if err == nil || errors.Is(err, re.NotFound) != c.notFound {
+			t.Errorf("Scanf(%q, %q): error %v; expected notFound=%v", c.format, c.input, err, c.notFound)
+		}
+	}
+}
+
+func TestCompileScanf(t *testing.T) {
+	r, err := re.CompileScanf("a.b %s\t %%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.String(), `\Aa\.b\s+(\S+)\s+%`; got != want {
+		t.Errorf("CompileScanf = %s; expected %s", got, want)
+	}
+	if _, err := re.CompileScanf("%v"); err == nil {
+		t.Errorf("CompileScanf(%%v) succeeded")
+	}
+}
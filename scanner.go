@@ -0,0 +1,120 @@
+package re
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// A Scanner holds a regular expression together with output arguments
+// that were bound to it ahead of time by Bind.  Each call to Scan
+// stores the sub-matches of a new input into those outputs.
+//
+// Bind does the work that Scan repeats on every call (checking that the
+// regular expression has enough sub-matches and that every output has a
+// supported type) exactly once, which makes a Scanner a good fit for hot
+// loops that process many inputs, e.g., the lines of a log file:
+//
+//	var host string
+//	var port int
+//	s := re.MustBind(regexp.MustCompile(`^(\w+):(\d+)$`), &host, &port)
+//	for _, line := range lines {
+//		if err := s.Scan(line); err == nil {
+//			Process(host, port)
+//		}
+//	}
+//
+// Bind also determines a few literal strings at least one of which
+// occurs in every match (e.g., "sshd[" for `^\S+ sshd\[(\d+)\]`), and
+// Scan rejects inputs that contain none of them without running the
+// regular expression.  This makes scanning inputs that mostly do not
+// match, such as the lines of a large log, considerably cheaper.
+//
+// A Scanner is not safe for concurrent use since all calls share the
+// same outputs.
+type Scanner struct {
+	re        *regexp.Regexp
+	groups    []int // Index of the sub-match for each output
+	assigners []assigner
+	floats    []bool   // Is each output a *float32 or *float64?
+	literals  [][]byte // Required literals; see requiredLiterals
+
+	// FiniteOnly, if true, makes Scan return an error for sub-matches
+	// stored into *float32 or *float64 outputs that parse as NaN or an
+	// infinity, such as "NaN" or "-Inf", which strconv.ParseFloat
+	// accepts but which rarely make sense in the data being scanned.
+	FiniteOnly bool
+}
+
+// Bind returns a Scanner that matches re and stores sub-matches into
+// output.  The outputs are interpreted exactly as they are by Scan.
+// An error is returned if re has fewer sub-matches than the number of
+// outputs, or if some output has an unsupported type.
+func Bind(re *regexp.Regexp, output ...interface{}) (*Scanner, error) {
+	if n := positional(output); re.NumSubexp() < n {
+		return nil, fmt.Errorf(`re.Bind: only got %d matches from "%s"; need at least %d`,
+			re.NumSubexp(), re, n)
+	}
+	s := &Scanner{
+		re:        re,
+		groups:    make([]int, len(output)),
+		assigners: make([]assigner, len(output)),
+		floats:    make([]bool, len(output)),
+	}
+	for i, r := range output {
+		j, r, err := outputGroup(re, i, r)
+		if err != nil {
+			return nil, err
+		}
+		a, err := newAssigner(r)
+		if err != nil {
+			return nil, err
+		}
+		s.groups[i], s.assigners[i] = j, a
+		switch r.(type) {
+		case *float32, *float64:
+			s.floats[i] = true
+		}
+	}
+	for _, lit := range requiredLiterals(re) {
+		s.literals = append(s.literals, []byte(lit))
+	}
+	return s, nil
+}
+
+// MustBind is like Bind but panics if the outputs cannot be bound.  It
+// simplifies safe initialization of global variables holding Scanners.
+func MustBind(re *regexp.Regexp, output ...interface{}) *Scanner {
+	s, err := Bind(re, output...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Scan returns nil if s's regular expression matches somewhere in input
+// and every sub-match is successfully stored into the corresponding
+// bound output.  It returns an error wrapping NotFound if there is no
+// match.
+func (s *Scanner) Scan(input []byte) error {
+	if !containsAny(input, s.literals) {
+		return fmt.Errorf("regular expression %q: %w", s.re, NotFound)
+	}
+	matches := s.re.FindSubmatchIndex(input)
+	if matches == nil {
+		return fmt.Errorf("regular expression %q: %w", s.re, NotFound)
+	}
+	for i, a := range s.assigners {
+		submatch, span := submatchAt(input, matches, s.groups[i])
+		if s.FiniteOnly && s.floats[i] {
+			if f, err := strconv.ParseFloat(string(submatch), 64); err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+				return parseError("not a finite number", submatch)
+			}
+		}
+		if err := a(submatch, span); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package re_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestScanner(t *testing.T) {
+	type mytype int
+
+	var host string
+	var port int
+	s, err := re.Bind(regexp.MustCompile(`^(\w+):(\d+)$`), &host, &port)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, c := range []struct {
+		input string
+		host  string
+		port  int
+	}{
+		{"a:1", "a", 1},
+		{"bb:22", "bb", 22},
+	} {
+		if err := s.Scan([]byte(c.input)); err != nil {
+			t.Errorf("Scan(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		if host != c.host || port != c.port {
+			t.Errorf("Scan(%q) = %s, %d; expected %s, %d", c.input, host, port, c.host, c.port)
+		}
+	}
+	if err := s.Scan([]byte("junk")); !errors.Is(err, re.NotFound) {
+		t.Errorf("Scan error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+	if err := s.Scan([]byte("a:99999999999999999999999")); err == nil {
+		t.Errorf("Scan of out of range port succeeded unexpectedly")
+	}
+
+	// Errors are reported by Bind, not Scan.
+	if _, err := re.Bind(regexp.MustCompile(`(\w+)`), &host, &port); err == nil {
+		t.Errorf("Bind with too few sub-matches succeeded unexpectedly")
+	}
+	if _, err := re.Bind(regexp.MustCompile(`(\w+)`), new(mytype)); err == nil {
+		t.Errorf("Bind with unsupported type succeeded unexpectedly")
+	}
+}
+
+func TestMustBind(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustBind did not panic")
+		}
+	}()
+	re.MustBind(regexp.MustCompile(`\w+`), new(string))
+}
+
+func TestScannerPrefilter(t *testing.T) {
+	// Inputs without a required literal are rejected without matching;
+	// the results must be the same as for re.Scan.
+	for _, pattern := range []string{
+		`^\S+ sshd\[(\d+)\]: (?:Accepted|Failed) password`,
+		`(\d+)(?:ms|s)\b`,
+		`(?i)took (\d+)`,
+		`(\d+)?x*`,
+		`(?:a|b|)(\d+)`,
+	} {
+		reg := regexp.MustCompile(pattern)
+		var got, want string
+		s := re.MustBind(reg, &got)
+		for _, input := range []string{
+			"", "host sshd[12]: Accepted password", "host sshd[12]: Rejected password",
+			"host sshd: Failed password", "took 30ms", "TOOK 5", "30 s", "7", "a9",
+		} {
+			got, want = "", ""
+			gotErr := s.Scan([]byte(input))
+			wantErr := re.Scan(reg, []byte(input), &want)
+			if (gotErr == nil) != (wantErr == nil) || got != want {
+				t.Errorf("%s: Scan(%q) = %q, %v; re.Scan gave %q, %v", pattern, input, got, gotErr, want, wantErr)
+			}
+		}
+	}
+}
+
+func TestScannerFiniteOnly(t *testing.T) {
+	var f float64
+	var g float32
+	s := re.MustBind(regexp.MustCompile(`^(\S+) (\S+)$`), &f, &g)
+	for _, input := range []string{"NaN 1", "1 -Inf", "+infinity 1"} {
+		if err := s.Scan([]byte(input)); err != nil {
+			t.Errorf("Scan(%q) = %v; expected success without FiniteOnly", input, err)
+		}
+	}
+	s.FiniteOnly = true
+	for _, input := range []string{"NaN 1", "1 -Inf", "+infinity 1"} {
+		if err := s.Scan([]byte(input)); err == nil {
+			t.Errorf("Scan(%q) = %v, %v; expected error with FiniteOnly", input, f, g)
+		}
+	}
+	if err := s.Scan([]byte("1.5 1e3")); err != nil || f != 1.5 || g != 1000 {
+		t.Errorf("Scan = %v, %v, %v; expected 1.5, 1000", f, g, err)
+	}
+	if err := s.Scan([]byte("1e400 1")); err == nil {
+		t.Errorf("Scan(1e400) succeeded; expected range error")
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Package secrets is a curated set of regular expressions for finding
+credentials, such as cloud access keys and API tokens, in text.
+
+	for _, f := range secrets.Find(line) {
+		fmt.Printf("%s %s at %d\n", f.Provider, f.Rule, f.Span.Start)
+	}
+
+Each Rule's pattern captures the whole secret in a group named "secret",
+and, where the format has one, a non-secret identifying part (such as an
+AWS access key ID or the kind of private key) in a group named "id".
+The rules aim for few false positives: they match fixed prefixes and
+lengths, or require a keyword such as "aws_secret_access_key" nearby.
+They will not find secrets without a recognizable format.
+*/
+package secrets
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/redact"
+)
+
+// A Rule describes one secret format.
+type Rule struct {
+	Name     string // E.g., "aws-access-key-id"
+	Provider string // E.g., "aws"
+	Pattern  *regexp.Regexp
+}
+
+// A Finding is a secret found by Find.
+type Finding struct {
+	Rule     string
+	Provider string
+	ID       string  // Identifying part of the secret, if any
+	Span     re.Span // Extent of the secret in the input
+}
+
+// Rules lists the rules used by Find.
+var Rules = []Rule{
+	{
+		Name:     "aws-access-key-id",
+		Provider: "aws",
+		Pattern:  regexp.MustCompile(`\b(?P<secret>(?P<id>(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}))\b`),
+	},
+	{
+		Name:     "aws-secret-access-key",
+		Provider: "aws",
+		Pattern:  regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9/+]{40})\b`),
+	},
+	{
+		Name:     "github-token",
+		Provider: "github",
+		Pattern:  regexp.MustCompile(`\b(?P<secret>(?P<id>gh[pousr])_[A-Za-z0-9]{36,251})\b`),
+	},
+	{
+		Name:     "github-fine-grained-token",
+		Provider: "github",
+		Pattern:  regexp.MustCompile(`\b(?P<secret>(?P<id>github_pat)_[A-Za-z0-9_]{82})\b`),
+	},
+	{
+		Name:     "gitlab-token",
+		Provider: "gitlab",
+		Pattern:  regexp.MustCompile(`\b(?P<secret>(?P<id>glpat)-[A-Za-z0-9_-]{20})\b`),
+	},
+	{
+		Name:     "slack-token",
+		Provider: "slack",
+		Pattern:  regexp.MustCompile(`\b(?P<secret>(?P<id>xox[abprs])-[A-Za-z0-9-]{10,})\b`),
+	},
+	{
+		Name:     "stripe-key",
+		Provider: "stripe",
+		Pattern:  regexp.MustCompile(`\b(?P<secret>(?P<id>(?:sk|rk)_(?:live|test))_[A-Za-z0-9]{24,})\b`),
+	},
+	{
+		Name:     "google-api-key",
+		Provider: "google",
+		Pattern:  regexp.MustCompile(`\b(?P<secret>AIza[0-9A-Za-z_-]{35})`),
+	},
+	{
+		Name:     "private-key",
+		Provider: "pem",
+		// The secret spans the whole block, up to the END line, or to the
+		// end of the input if the block is truncated.
+		Pattern: regexp.MustCompile(`(?s)(?P<secret>-----BEGIN (?P<id>` + pemLabel + `)-----(?:.*?-----END ` + pemLabel + `-----|.*))`),
+	},
+}
+
+// pemLabel matches the label of a PEM private key block.  It occurs in
+// both the BEGIN and END lines, since RE2 has no backreferences.
+const pemLabel = `(?:RSA |DSA |EC |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?`
+
+// find returns the secrets in input found by rule.
+func find(rule Rule, input []byte) []Finding {
+	var result []Finding
+	for _, m := range rule.Pattern.FindAllSubmatchIndex(input, -1) {
+		f := Finding{Rule: rule.Name, Provider: rule.Provider}
+		for i, name := range rule.Pattern.SubexpNames() {
+			if m[2*i] < 0 {
+				continue
+			}
+			switch name {
+			case "secret":
+				f.Span = re.Span{Start: m[2*i], End: m[2*i+1]}
+			case "id":
+				f.ID = string(input[m[2*i]:m[2*i+1]])
+			}
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// Find returns the secrets in input found by Rules, in order of
+// position.
+func Find(input []byte) []Finding {
+	var result []Finding
+	for _, r := range Rules {
+		result = append(result, find(r, input)...)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Span.Start < result[j].Span.Start })
+	return result
+}
+
+// Redactor returns a Redactor that masks the secrets found by Rules.
+// The redactions are labeled with the rule names.  It panics if the
+// pattern of some rule has no group named "secret".
+func Redactor() *redact.Redactor {
+	var rules []redact.Rule
+	for _, r := range Rules {
+		rules = append(rules, redact.Rule{Label: r.Name, Pattern: r.Pattern, Groups: []string{"secret"}})
+	}
+	r, err := redact.New(rules...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
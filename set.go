@@ -0,0 +1,199 @@
+package re
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+)
+
+// A PatternSet matches an input against many regular expressions at
+// once, and stores the sub-matches of every one that matches into
+// outputs bound to it ahead of time, as Bind does:
+//
+//	var set re.PatternSet
+//	var user, addr string
+//	set.Add(regexp.MustCompile(`Failed password for (\w+) from (\S+)`), func() error {
+//		return failures.Add(user, addr)
+//	}, &user, &addr)
+//	// ... many more patterns ...
+//	for _, line := range lines {
+//		if _, err := set.Dispatch(line); err != nil {
+//			return err
+//		}
+//	}
+//
+// Go's regexp package has no equivalent of RE2::Set, and a single
+// alternation of many patterns is slower to match than the patterns
+// themselves.  Instead, a PatternSet determines for each pattern a few
+// literal strings, at least one of which occurs in every match (e.g.,
+// "Failed password for " above), and searches the input for each
+// distinct literal once.  Only the patterns whose literals are present
+// are run.  When most inputs match few patterns, as is typical of log
+// processing, this avoids running nearly all of them.  Patterns without
+// such literals (e.g., `^\d+$`) are run on every input.
+//
+// The zero value is an empty PatternSet.  Since each pattern stores into
+// outputs bound ahead of time, Dispatch is not safe for concurrent use;
+// Match is, provided no patterns are being added.  Reload, however, may
+// be called while other goroutines run Match or Dispatch.
+type PatternSet struct {
+	state atomic.Value // *setState; nil until the first Add or Reload
+}
+
+// setState holds the compiled patterns of a PatternSet.  Reload replaces
+// it as a whole, so that scans in progress keep using the old one.
+type setState struct {
+	patterns []setPattern
+	literals [][]byte       // Distinct required literals of all patterns
+	index    map[string]int // Index in literals of each literal
+}
+
+type setPattern struct {
+	scanner  *Scanner
+	handler  func() error
+	literals []int // Indices in PatternSet.literals; nil if none
+}
+
+// A SetRule describes one pattern of a PatternSet, as passed to Reload.
+type SetRule struct {
+	Pattern *regexp.Regexp
+	Handler func() error  // Called after a match, if non-nil
+	Output  []interface{} // Outputs bound to Pattern, as by Bind
+}
+
+// Add adds re to the set.  When re matches an input passed to
+// Dispatch, its sub-matches are stored into output as by Scan, and
+// then handler (if non-nil) is called.  An error is returned if the
+// outputs cannot be bound to re; see Bind.
+func (s *PatternSet) Add(re *regexp.Regexp, handler func() error, output ...interface{}) error {
+	st, _ := s.state.Load().(*setState)
+	if st == nil {
+		st = &setState{}
+		s.state.Store(st)
+	}
+	return st.add(re, handler, output)
+}
+
+// Reload replaces the patterns of s with rules, as if s were emptied and
+// each rule then added with Add.  The replacement is atomic: a call to
+// Match or Dispatch already in progress finishes with the old patterns,
+// and later calls use the new ones.  If some rule is invalid, Reload
+// returns an error identifying it and leaves the old patterns in place.
+func (s *PatternSet) Reload(rules []SetRule) error {
+	st := &setState{}
+	for i, r := range rules {
+		if err := st.add(r.Pattern, r.Handler, r.Output); err != nil {
+			return fmt.Errorf("re.PatternSet: rule %d: %w", i, err)
+		}
+	}
+	s.state.Store(st)
+	return nil
+}
+
+// load returns the current patterns of s.
+func (s *PatternSet) load() *setState {
+	if st, _ := s.state.Load().(*setState); st != nil {
+		return st
+	}
+	return &setState{}
+}
+
+func (s *setState) add(re *regexp.Regexp, handler func() error, output []interface{}) error {
+	if re == nil {
+		return errors.New("nil pattern")
+	}
+	sc, err := Bind(re, output...)
+	if err != nil {
+		return err
+	}
+	p := setPattern{scanner: sc, handler: handler}
+	for _, lit := range requiredLiterals(re) {
+		i, ok := s.index[lit]
+		if !ok {
+			if s.index == nil {
+				s.index = map[string]int{}
+			}
+			i = len(s.literals)
+			s.index[lit] = i
+			s.literals = append(s.literals, []byte(lit))
+		}
+		p.literals = append(p.literals, i)
+	}
+	s.patterns = append(s.patterns, p)
+	return nil
+}
+
+// Len returns the number of patterns in s.
+func (s *PatternSet) Len() int {
+	return len(s.load().patterns)
+}
+
+// Match returns the indices, in the order they were added, of the
+// patterns in s that match input.  It does not store any sub-matches.
+func (s *PatternSet) Match(input []byte) []int {
+	var matched []int
+	st := s.load()
+	present := st.presence(input)
+	for i, p := range st.patterns {
+		if present(p) && p.scanner.re.Match(input) {
+			matched = append(matched, i)
+		}
+	}
+	return matched
+}
+
+// Dispatch stores the sub-matches of every pattern in s that matches
+// input into its outputs and calls its handler, in the order the
+// patterns were added.  It returns the number of patterns that matched.
+// Dispatch stops at the first sub-match that cannot be parsed, or the
+// first handler that returns an error, and returns that error along
+// with the number of patterns that matched before it.
+func (s *PatternSet) Dispatch(input []byte) (int, error) {
+	n := 0
+	st := s.load()
+	present := st.presence(input)
+	for _, p := range st.patterns {
+		if !present(p) {
+			continue
+		}
+		err := p.scanner.Scan(input)
+		if errors.Is(err, NotFound) {
+			continue
+		} else if err != nil {
+			return n, err
+		}
+		if p.handler != nil {
+			if err := p.handler(); err != nil {
+				return n, err
+			}
+		}
+		n++
+	}
+	return n, nil
+}
+
+// presence returns a function reporting whether input contains one of
+// the required literals of a pattern, and so might match it.  Each
+// literal is searched for at most once.
+func (s *setState) presence(input []byte) func(setPattern) bool {
+	found := make([]int8, len(s.literals)) // 0: unknown, 1: present, -1: absent
+	return func(p setPattern) bool {
+		if p.literals == nil {
+			return true
+		}
+		for _, i := range p.literals {
+			if found[i] == 0 {
+				found[i] = -1
+				if bytes.Contains(input, s.literals[i]) {
+					found[i] = 1
+				}
+			}
+			if found[i] > 0 {
+				return true
+			}
+		}
+		return false
+	}
+}
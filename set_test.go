@@ -0,0 +1,152 @@
+package re_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestPatternSet(t *testing.T) {
+	var set re.PatternSet
+	var got []string
+	var user, addr string
+	var port int
+	must := func(err error) {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	must(set.Add(regexp.MustCompile(`Failed password for (\w+) from (\S+)`), func() error {
+		got = append(got, "failed:"+user+"@"+addr)
+		return nil
+	}, &user, &addr))
+	must(set.Add(regexp.MustCompile(`(?:port|PORT)=(\d+)`), func() error {
+		got = append(got, fmt.Sprint("port:", port))
+		return nil
+	}, &port))
+	must(set.Add(regexp.MustCompile(`^\w+$`), nil)) // No required literal
+	must(set.Add(regexp.MustCompile(`(?i)error`), func() error {
+		got = append(got, "error")
+		return nil
+	}))
+	if set.Len() != 4 {
+		t.Errorf("Len() = %d; expected 4", set.Len())
+	}
+
+	for _, c := range []struct {
+		input string
+		match []int
+		got   []string
+	}{
+		{"Failed password for root from 10.0.0.1 port=22", []int{0, 1}, []string{"failed:root@10.0.0.1", "port:22"}},
+		{"PORT=80 ERROR", []int{1, 3}, []string{"port:80", "error"}},
+		{"hello", []int{2}, nil},
+		{"nothing here", nil, nil},
+	} {
+		if m := set.Match([]byte(c.input)); !reflect.DeepEqual(m, c.match) {
+			t.Errorf("Match(%q) = %v; expected %v", c.input, m, c.match)
+		}
+		got = nil
+		n, err := set.Dispatch([]byte(c.input))
+		if err != nil || n != len(c.match) || !reflect.DeepEqual(got, c.got) {
+			t.Errorf("Dispatch(%q) = %d, %v, ran %v; expected %d, %v", c.input, n, err, got, len(c.match), c.got)
+		}
+	}
+
+	// Errors stop dispatch.
+	var small int8
+	must(set.Add(regexp.MustCompile(`size=(\d+)`), nil, &small))
+	must(set.Add(regexp.MustCompile(`stop`), func() error { return errors.New("stopped") }))
+	if n, err := set.Dispatch([]byte("port=1 size=1000")); n != 1 || err == nil {
+		t.Errorf("Dispatch with parse error = %d, %v", n, err)
+	}
+	if n, err := set.Dispatch([]byte("stop")); n != 1 || err == nil || err.Error() != "stopped" {
+		t.Errorf("Dispatch with handler error = %d, %v", n, err)
+	}
+	if err := set.Add(regexp.MustCompile(`x`), nil, &port); err == nil {
+		t.Errorf("Add with too many outputs succeeded")
+	}
+}
+
+// TestPatternSetLiterals checks that patterns are matched correctly
+// whatever literals are derived from them.
+func TestPatternSetLiterals(t *testing.T) {
+	patterns := []string{
+		`abc`, `a+bc`, `(?:foo|bar)baz`, `foo|foobar`, `x*`, `(ab){2}`, `a.c`,
+		`[ab]cd`, `(?:x|)y`, `héllo`, `\bword\b`, `(?s)a.*z`, `(?i)ABC`, `1{0}z`,
+	}
+	inputs := []string{"", "abc", "aabc", "barbaz", "foobar", "abab", "axc", "bcd", "xy", "y", "héllo", "a word", "a\nz", "aBc", "z"}
+	var set re.PatternSet
+	for _, p := range patterns {
+		if err := set.Add(regexp.MustCompile(p), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, in := range inputs {
+		var want []int
+		for i, p := range patterns {
+			if regexp.MustCompile(p).MatchString(in) {
+				want = append(want, i)
+			}
+		}
+		if got := set.Match([]byte(in)); !reflect.DeepEqual(got, want) {
+			t.Errorf("Match(%q) = %v; expected %v", in, got, want)
+		}
+	}
+}
+
+func TestPatternSetReload(t *testing.T) {
+	var set re.PatternSet
+	var port int
+	if err := set.Add(regexp.MustCompile(`port=(\d+)`), nil, &port); err != nil {
+		t.Fatal(err)
+	}
+
+	// A bad rule set is rejected, and the old patterns stay in use.
+	for _, rules := range [][]re.SetRule{
+		{{Pattern: regexp.MustCompile(`user=(\w+)`)}, {Pattern: regexp.MustCompile(`x`), Output: []interface{}{&port}}},
+		{{Pattern: nil}},
+	} {
+		if err := set.Reload(rules); err == nil {
+			t.Errorf("Reload(%v) succeeded unexpectedly", rules)
+		}
+	}
+	if m := set.Match([]byte("port=8 user=bob")); !reflect.DeepEqual(m, []int{0}) || set.Len() != 1 {
+		t.Errorf("Match after failed Reload = %v; expected [0]", m)
+	}
+
+	var user string
+	if err := set.Reload([]re.SetRule{
+		{Pattern: regexp.MustCompile(`user=(\w+)`), Output: []interface{}{&user}},
+		{Pattern: regexp.MustCompile(`(?i)error`)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := set.Dispatch([]byte("port=8 user=bob ERROR")); n != 2 || err != nil || user != "bob" {
+		t.Errorf("Dispatch after Reload = %d, %v, user %q; expected 2, bob", n, err, user)
+	}
+
+	// Reload does not disturb concurrent matching.
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			if m := set.Match([]byte("user=x error")); len(m) != 2 {
+				t.Errorf("Match during Reload = %v; expected two matches", m)
+			}
+		}
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		if err := set.Reload([]re.SetRule{
+			{Pattern: regexp.MustCompile(`user=`)},
+			{Pattern: regexp.MustCompile(`error`)},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+}
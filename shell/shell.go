@@ -0,0 +1,168 @@
+/*
+Package shell splits command lines, such as those recorded in audit
+logs, into words following the quoting rules of the POSIX shell, and
+extracts flag values from the resulting words.
+
+Words returns an output argument for re.Scan:
+
+	var argv []string
+	err := re.Scan(regexp.MustCompile(`cmd="((?:[^"\\]|\\.)*)"`), line, shell.Words(&argv))
+
+Flags then picks out the values of interesting flags:
+
+	var output string
+	var verbose bool
+	args, err := shell.Flags(argv[1:], map[string]interface{}{
+		"-o": &output, "--output": &output,
+		"-v": &verbose,
+	})
+
+Only quoting is interpreted: variables, globs, command substitutions and
+operators such as | and ; are left in the words as literal text.
+*/
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ghemawat/re"
+)
+
+// Split splits s into words.  Unquoted spaces, tabs and newlines separate
+// words.  Text inside single quotes is taken literally.  Inside double
+// quotes, a backslash only escapes $, `, ", \ and newline; elsewhere it
+// escapes any character.  A backslash-newline pair is removed entirely.
+// Quotes may produce empty words, e.g., `a "" b` has three words.  An
+// error is returned for an unterminated quote or a trailing backslash.
+func Split(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case ' ', '\t', '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		case '\\':
+			i++
+			if i == len(s) {
+				return nil, errors.New("shell: trailing backslash")
+			}
+			if s[i] == '\n' {
+				continue // Line continuation
+			}
+			word.WriteByte(s[i])
+		case '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("shell: unterminated single quote at offset %d", i)
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += 1 + end
+		case '"':
+			start := i
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, fmt.Errorf("shell: unterminated double quote at offset %d", start)
+			}
+		default:
+			word.WriteByte(c)
+		}
+		inWord = true
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// Words returns an output argument for re.Scan that splits the
+// sub-match into words as Split does and stores them into *output.
+func Words(output *[]string) func([]byte) error {
+	return func(b []byte) error {
+		words, err := Split(string(b))
+		if err != nil {
+			return err
+		}
+		*output = words
+		return nil
+	}
+}
+
+// wholeValue matches an entire flag value.
+var wholeValue = regexp.MustCompile(`(?s)^(.*)$`)
+
+// Flags extracts the values of the flags named in flags from args and
+// returns the remaining arguments in order.  Keys of flags are flag
+// names including their leading dashes, e.g., "-o" or "--output"; values
+// are outputs of any type accepted by re.Scan, into which the flag value
+// is parsed.
+//
+// A *bool output marks a flag that takes no value; it is set to true
+// when the flag is present, unless a value is attached with "=" (e.g.,
+// "--verbose=false").  Other flags take their value from the same word
+// after "=" (e.g., "--output=x"), from the rest of the word for a
+// single-dash, single-letter flag (e.g., "-ox"), or else from the next
+// word.  Combined single-letter flags such as "-la" are not split.
+// Unknown flags are returned among the remaining arguments, and
+// the word "--" ends flag processing (it is not returned).
+//
+// An error is returned if a flag is missing its value or the value
+// cannot be parsed.
+func Flags(args []string, flags map[string]interface{}) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(rest, args[i+1:]...), nil
+		}
+		name, value, hasValue := arg, "", false
+		if eq := strings.IndexByte(arg, '='); eq > 0 && strings.HasPrefix(arg, "-") {
+			name, value, hasValue = arg[:eq], arg[eq+1:], true
+		}
+		output, ok := flags[name]
+		if !ok && !hasValue && len(arg) > 2 && arg[0] == '-' && arg[1] != '-' {
+			// Short flag with attached value, e.g., "-ofile".
+			name, value, hasValue = arg[:2], arg[2:], true
+			output, ok = flags[name]
+			if _, isBool := output.(*bool); isBool {
+				ok = false // Combined flags such as "-la" are not split
+			}
+		}
+		if !ok {
+			rest = append(rest, arg)
+			continue
+		}
+		if b, isBool := output.(*bool); isBool && !hasValue {
+			*b = true
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("shell: flag %s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		if err := re.Scan(wholeValue, []byte(value), output); err != nil {
+			return nil, fmt.Errorf("shell: flag %s: %w", name, err)
+		}
+	}
+	return rest, nil
+}
@@ -0,0 +1,91 @@
+package shell_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/shell"
+)
+
+func TestSplit(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"  ls  -l\t/tmp\n", []string{"ls", "-l", "/tmp"}},
+		{`echo 'a  b' "c  d"`, []string{"echo", "a  b", "c  d"}},
+		{`echo it'"'s`, []string{"echo", `it"s`}},
+		{`a "" ''`, []string{"a", "", ""}},
+		{`a\ b c\\d \'`, []string{"a b", `c\d`, "'"}},
+		{`"\$HOME \"q\" \n"`, []string{`$HOME "q" \n`}},
+		{"a\\\nb", []string{"ab"}},
+		{"\"a\\\nb\"", []string{"ab"}},
+		{`'\n' $x`, []string{`\n`, "$x"}},
+		{`pre"mid"'post'`, []string{"premidpost"}},
+	} {
+		got, err := shell.Split(c.input)
+		if err != nil {
+			t.Errorf("Split(%q): unexpected error: %s", c.input, err)
+		} else if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Split(%q) = %q; expected %q", c.input, got, c.want)
+		}
+	}
+	for _, bad := range []string{`'abc`, `"abc`, `abc\`, `"a\"`} {
+		if got, err := shell.Split(bad); err == nil {
+			t.Errorf("Split(%q) = %q; expected an error", bad, got)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	var argv []string
+	line := []byte(`type=EXECVE cmd="tar -czf 'my backup.tgz' /home"`)
+	if err := re.Scan(regexp.MustCompile(`cmd="(.*)"`), line, shell.Words(&argv)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []string{"tar", "-czf", "my backup.tgz", "/home"}; !reflect.DeepEqual(argv, want) {
+		t.Errorf("got %q; expected %q", argv, want)
+	}
+	if err := re.Scan(regexp.MustCompile(`(.*)`), []byte(`'x`), shell.Words(&argv)); err == nil {
+		t.Errorf("Words accepted an unterminated quote")
+	}
+}
+
+func TestFlags(t *testing.T) {
+	var output string
+	var verbose, force bool
+	var timeout time.Duration
+	var count int
+	flags := map[string]interface{}{
+		"-o": &output, "--output": &output,
+		"-v":        &verbose,
+		"--force":   &force,
+		"--timeout": &timeout,
+		"-n":        &count,
+	}
+	args := []string{"-v", "in", "--output=out", "-la", "--timeout", "5s", "-n3", "--force=false", "--", "-o", "x"}
+	rest, err := shell.Flags(args, flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []string{"in", "-la", "-o", "x"}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("rest = %q; expected %q", rest, want)
+	}
+	if output != "out" || !verbose || force || timeout != 5*time.Second || count != 3 {
+		t.Errorf("got output=%q verbose=%v force=%v timeout=%v count=%d", output, verbose, force, timeout, count)
+	}
+
+	for _, bad := range [][]string{
+		{"-o"},
+		{"-n", "many"},
+		{"--force=maybe"},
+	} {
+		if _, err := shell.Flags(bad, flags); err == nil {
+			t.Errorf("Flags(%q) succeeded unexpectedly", bad)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+//go:build !re_minimal
+
+package re
+
+import (
+	"database/sql"
+	"reflect"
+)
+
+// sqlAssigner returns the assigner for r if r points to one of the
+// nullable types of package database/sql.
+func sqlAssigner(r interface{}) (assigner, bool) {
+	switch v := r.(type) {
+	case *sql.NullString:
+		return nullAssigner(&v.Valid, &v.String), true
+	case *sql.NullInt64:
+		return nullAssigner(&v.Valid, &v.Int64), true
+	case *sql.NullInt32:
+		return nullAssigner(&v.Valid, &v.Int32), true
+	case *sql.NullFloat64:
+		return nullAssigner(&v.Valid, &v.Float64), true
+	case *sql.NullBool:
+		return nullAssigner(&v.Valid, &v.Bool), true
+	case *sql.NullTime:
+		return nullAssigner(&v.Valid, &v.Time), true
+	}
+	return nil, false
+}
+
+// nullAssigner returns an assigner that stores a sub-match into value
+// and sets *valid, or, if the group did not participate in the match,
+// zeroes value and clears *valid.
+func nullAssigner(valid *bool, value interface{}) assigner {
+	a, err := newAssigner(value)
+	if err != nil {
+		panic(err) // The value types of the sql.Null types are all supported.
+	}
+	v := reflect.ValueOf(value).Elem()
+	return func(b []byte, s Span) error {
+		if s.Start < 0 {
+			v.Set(reflect.Zero(v.Type()))
+			*valid = false
+			return nil
+		}
+		if err := a(b, s); err != nil {
+			return err
+		}
+		*valid = true
+		return nil
+	}
+}
+
+// sqlScannerAssigner returns the assigner for r if r implements
+// sql.Scanner.  The assigner calls the Scan method of r with the
+// sub-match as a string, or with nil, standing for SQL NULL, if the
+// group did not participate in the match.
+func sqlScannerAssigner(r interface{}) (assigner, bool) {
+	sc, ok := r.(sql.Scanner)
+	if !ok {
+		return nil, false
+	}
+	return func(b []byte, s Span) error {
+		if s.Start < 0 {
+			return sc.Scan(nil)
+		}
+		return sc.Scan(string(b))
+	}, true
+}
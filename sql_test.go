@@ -0,0 +1,128 @@
+//go:build !re_minimal
+
+package re_test
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+func TestSQLNull(t *testing.T) {
+	reg := regexp.MustCompile(`^(\w+)(?: uid=(\d+))?(?: ratio=(\S+))?(?: admin=(\w+))?(?: at=(\S+))?$`)
+	var name sql.NullString
+	var uid sql.NullInt64
+	var ratio sql.NullFloat64
+	var admin sql.NullBool
+	var at sql.NullTime
+	if err := re.Scan(reg, []byte("ann uid=7 ratio=0.5 admin=true at=2024-01-02T03:04:05Z"), &name, &uid, &ratio, &admin, &at); err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if name != (sql.NullString{String: "ann", Valid: true}) ||
+		uid != (sql.NullInt64{Int64: 7, Valid: true}) ||
+		ratio != (sql.NullFloat64{Float64: 0.5, Valid: true}) ||
+		admin != (sql.NullBool{Bool: true, Valid: true}) ||
+		!at.Valid || !at.Time.Equal(want) {
+		t.Errorf("Scan = %v, %v, %v, %v, %v", name, uid, ratio, admin, at)
+	}
+	if err := re.Scan(reg, []byte("bob"), &name, &uid, &ratio, &admin, &at); err != nil {
+		t.Fatal(err)
+	}
+	if name.String != "bob" || uid != (sql.NullInt64{}) || ratio != (sql.NullFloat64{}) ||
+		admin != (sql.NullBool{}) || at.Valid || !at.Time.IsZero() {
+		t.Errorf("Scan of absent groups = %v, %v, %v, %v, %v", name, uid, ratio, admin, at)
+	}
+	var small sql.NullInt32
+	if err := re.Scan(reg, []byte("bob uid=9999999999"), nil, &small); err == nil || small.Valid {
+		t.Errorf("Scan out of range into NullInt32 = %v, %v; expected error", small, err)
+	}
+}
+
+// cents is a sql.Scanner that parses amounts such as "12.34".
+type cents struct {
+	n    int64
+	null bool
+}
+
+func (c *cents) Scan(src interface{}) error {
+	if src == nil {
+		*c = cents{null: true}
+		return nil
+	}
+	s, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("cents: unexpected %T", src)
+	}
+	var whole, frac int64
+	if _, err := fmt.Sscanf(s, "%d.%02d", &whole, &frac); err != nil {
+		return err
+	}
+	*c = cents{n: whole*100 + frac}
+	return nil
+}
+
+func TestSQLScanner(t *testing.T) {
+	reg := regexp.MustCompile(`^total(?: (\S+))?$`)
+	var c cents
+	if err := re.Scan(reg, []byte("total 12.34"), &c); err != nil || c != (cents{n: 1234}) {
+		t.Errorf("Scan = %+v, %v; expected 1234 cents", c, err)
+	}
+	if err := re.Scan(reg, []byte("total"), &c); err != nil || !c.null {
+		t.Errorf("Scan of absent group = %+v, %v; expected null", c, err)
+	}
+	if err := re.Scan(reg, []byte("total x"), &c); err == nil {
+		t.Errorf("Scan(x) = %+v; expected error", c)
+	}
+}
+
+// TestSQLNullWrappers checks that wrapped outputs pass on whether a
+// group participated in the match.
+func TestSQLNullWrappers(t *testing.T) {
+	tag := regexp.MustCompile(`(<[^>]*>)`)
+	var ns sql.NullString
+	if err := re.Scan(tag, []byte(`<input disabled>`), re.Attr("disabled", &ns)); err != nil || ns != (sql.NullString{Valid: true}) {
+		t.Errorf("Attr(disabled) into NullString = %+v, %v; expected valid empty string", ns, err)
+	}
+
+	opt := regexp.MustCompile(`^(\w+)(?: (.*))?$`)
+	var ni sql.NullInt64
+	if err := re.Scan(opt, []byte("x"), nil, re.Lowered(&ns)); err != nil || ns.Valid {
+		t.Errorf("Lowered into NullString of absent group = %+v, %v; expected invalid", ns, err)
+	}
+	if err := re.Scan(opt, []byte("x"), nil, re.Trimmed(&ni)); err != nil || ni.Valid {
+		t.Errorf("Trimmed into NullInt64 of absent group = %+v, %v; expected invalid", ni, err)
+	}
+	if err := re.Scan(opt, []byte("x   "), nil, re.Trimmed(&ns)); err != nil || ns != (sql.NullString{Valid: true}) {
+		t.Errorf("Trimmed into NullString of blank group = %+v, %v; expected valid empty string", ns, err)
+	}
+	if err := re.Scan(opt, []byte("x"), nil, re.Unquoted(&ns)); err != nil || ns.Valid {
+		t.Errorf("Unquoted into NullString of absent group = %+v, %v; expected invalid", ns, err)
+	}
+	if err := re.Scan(opt, []byte(`x ""`), nil, re.Unquoted(&ns)); err != nil || ns != (sql.NullString{Valid: true}) {
+		t.Errorf("Unquoted into NullString of empty string = %+v, %v; expected valid", ns, err)
+	}
+
+	get := regexp.MustCompile(`^GET ([^?]*)(?:\?q=(\S*))?$`)
+	if err := re.Scan(get, []byte("GET /"), nil, re.PathDecoded(&ns)); err != nil || ns.Valid {
+		t.Errorf("PathDecoded into NullString of absent group = %+v, %v; expected invalid", ns, err)
+	}
+	if err := re.Scan(get, []byte("GET /?q=a+b"), nil, re.URLDecoded(&ns)); err != nil || ns != (sql.NullString{String: "a b", Valid: true}) {
+		t.Errorf("URLDecoded into NullString = %+v, %v; expected valid %q", ns, err, "a b")
+	}
+}
+
+func TestScanfNullable(t *testing.T) {
+	var p *int
+	var ns sql.NullString
+	if err := re.Scanf("%d %q", []byte(`007 ""`), &p, &ns); err != nil {
+		t.Fatal(err)
+	}
+	if p == nil || *p != 7 || ns != (sql.NullString{Valid: true}) {
+		t.Errorf("Scanf = %v, %+v; expected 7 and a valid empty string", p, ns)
+	}
+}
@@ -0,0 +1,159 @@
+//go:build !re_minimal
+
+package re
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"unicode/utf8"
+)
+
+// A Stream finds successive matches of a regular expression in an
+// io.Reader without reading the whole input into memory.  Each call to
+// Scan reads from the underlying reader just far enough to determine
+// the next match and stores its sub-matches into outputs as Scan does:
+//
+//	s := re.NewStream(file, regexp.MustCompile(`(\w+):(\d+)`))
+//	for {
+//		var host string
+//		var port int
+//		err := s.Scan(&host, &port)
+//		if errors.Is(err, re.NotFound) {
+//			break
+//		} else if err != nil {
+//			return err
+//		}
+//		Process(host, port)
+//	}
+//
+// A Stream retains the text between the end of the previous match and
+// the end of the next one (plus a small amount of read-ahead), so memory
+// use is bounded by the distance between matches.  A stream that does
+// not contain a match is read to the end, and retained, by the failing
+// call to Scan.
+//
+// Each call to Scan matches the remainder of the stream as if it were a
+// new input, so ^ and \A match at the end of the previous match.
+type Stream struct {
+	re      *regexp.Regexp
+	src     io.Reader
+	err     error  // Error (including io.EOF) returned by src
+	buf     []byte // Text read from src but not yet consumed
+	pos     int64  // Offset in the stream of buf[0]
+	matched bool   // Did the previous call to Scan find a match?
+}
+
+// minRead is the minimum amount of space offered to each Read call.
+const minRead = 4096
+
+// NewStream returns a Stream that finds matches of re in r.
+func NewStream(r io.Reader, re *regexp.Regexp) *Stream {
+	return &Stream{re: re, src: r}
+}
+
+// ScanReader is like Scan, but matches re against the text read from r
+// instead of a byte slice; see Stream for details.  Text read from r
+// beyond the end of the match is discarded; use a Stream to find
+// multiple matches.
+func ScanReader(r io.Reader, re *regexp.Regexp, output ...interface{}) error {
+	return NewStream(r, re).Scan(output...)
+}
+
+// Scan finds the next match in the stream and stores its sub-matches
+// into output following the rules of re.Scan.  Spans stored into *Span
+// outputs are offsets from the start of the stream, and []byte outputs
+// hold copies rather than aliases.  Scan returns an error wrapping
+// NotFound once there are no more matches, or the error (other than
+// io.EOF) returned by the underlying reader.
+func (s *Stream) Scan(output ...interface{}) error {
+	for {
+		matches := s.re.FindReaderSubmatchIndex(&streamRunes{s: s})
+		if s.err != nil && s.err != io.EOF {
+			return s.err
+		}
+		if matches == nil {
+			s.consume(len(s.buf))
+			s.matched = false
+			return fmt.Errorf("regular expression %q: %w", s.re, NotFound)
+		}
+		start, end := matches[0], matches[1]
+		if start == end && start == 0 && s.matched {
+			// Like FindAll, ignore an empty match abutting the
+			// preceding match, and try again one rune later.
+			if len(s.buf) == 0 {
+				s.matched = false
+				return fmt.Errorf("regular expression %q: %w", s.re, NotFound)
+			}
+			_, size := utf8.DecodeRune(s.buf)
+			s.consume(size)
+			s.matched = false
+			continue
+		}
+
+		// Copy the match so that []byte outputs are not aliases of
+		// buf, which is overwritten by later reads.
+		text := append([]byte(nil), s.buf[start:end]...)
+		for i := range matches {
+			if matches[i] >= 0 {
+				matches[i] -= start
+			}
+		}
+		offset := s.pos + int64(start)
+		s.consume(end)
+		s.matched = true
+		if err := scanMatch(s.re, text, matches, output); err != nil {
+			return err
+		}
+		// Make spans relative to the start of the stream.
+		for i, r := range output {
+			j, r, _ := outputGroup(s.re, i, r)
+			if sp, ok := r.(*Span); ok && matches[2+2*j] >= 0 {
+				sp.Start += int(offset)
+				sp.End += int(offset)
+			}
+		}
+		return nil
+	}
+}
+
+// consume discards the first n bytes of buf.
+func (s *Stream) consume(n int) {
+	s.buf = s.buf[n:]
+	s.pos += int64(n)
+}
+
+// fill appends the next chunk of text from src to buf.
+func (s *Stream) fill() {
+	if cap(s.buf)-len(s.buf) < minRead {
+		b := make([]byte, len(s.buf), 2*len(s.buf)+minRead)
+		copy(b, s.buf)
+		s.buf = b
+	}
+	n, err := s.src.Read(s.buf[len(s.buf):cap(s.buf)])
+	s.buf = s.buf[:len(s.buf)+n]
+	if err != nil {
+		s.err = err
+	}
+}
+
+// streamRunes is the io.RuneReader through which the regexp package
+// reads a Stream.  It returns successive runes of the stream's buf
+// starting at its beginning, filling buf as needed.
+type streamRunes struct {
+	s   *Stream
+	pos int // Offset in buf of the next rune
+}
+
+func (r *streamRunes) ReadRune() (rune, int, error) {
+	s := r.s
+	for !utf8.FullRune(s.buf[r.pos:]) && s.err == nil {
+		s.fill()
+	}
+	if r.pos >= len(s.buf) {
+		return 0, 0, io.EOF
+	}
+	c, size := utf8.DecodeRune(s.buf[r.pos:])
+	r.pos += size
+	return c, size, nil
+}
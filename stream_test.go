@@ -0,0 +1,93 @@
+//go:build !re_minimal
+
+package re_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/ghemawat/re"
+)
+
+func TestStream(t *testing.T) {
+	input := "host:1234 www.google.com:2345 é:1"
+	// OneByteReader exercises matches and runes split across reads.
+	s := re.NewStream(iotest.OneByteReader(strings.NewReader(input)), regexp.MustCompile(`((\S+):(\d+))`))
+	var hosts []string
+	var spans []re.Span
+	var raw [][]byte
+	for {
+		var span re.Span
+		var host []byte
+		var port int
+		err := s.Scan(&span, &host, &port)
+		if errors.Is(err, re.NotFound) {
+			break
+		} else if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		hosts = append(hosts, string(host))
+		spans = append(spans, span)
+		raw = append(raw, host)
+	}
+	if want := []string{"host", "www.google.com", "é"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %q; expected %q", hosts, want)
+	}
+	want := []re.Span{{Start: 0, End: 9}, {Start: 10, End: 29}, {Start: 30, End: 34}}
+	if !reflect.DeepEqual(spans, want) {
+		t.Errorf("spans = %v; expected %v", spans, want)
+	}
+	for i, b := range raw {
+		if string(b) != hosts[i] {
+			t.Errorf("[]byte output %d was overwritten: %q", i, b)
+		}
+	}
+	for i, sp := range spans {
+		if got := input[sp.Start:sp.End]; !strings.HasPrefix(got, hosts[i]) {
+			t.Errorf("span %v covers %q", sp, got)
+		}
+	}
+}
+
+func TestStreamEmptyMatches(t *testing.T) {
+	// Same sequence of matches as FindAll.
+	pattern := regexp.MustCompile(`(a*)`)
+	input := "baaacab"
+	var got []string
+	s := re.NewStream(strings.NewReader(input), pattern)
+	for i := 0; i < 10; i++ {
+		var m string
+		if err := s.Scan(&m); err != nil {
+			break
+		}
+		got = append(got, m)
+	}
+	if want := pattern.FindAllString(input, -1); !reflect.DeepEqual(got, want) {
+		t.Errorf("Stream matches = %q; expected %q", got, want)
+	}
+}
+
+func TestScanReader(t *testing.T) {
+	var port int
+	if err := re.ScanReader(strings.NewReader("x host:80 y"), regexp.MustCompile(`:(\d+)`), &port); err != nil || port != 80 {
+		t.Errorf("ScanReader extracted %d, %v; expected 80", port, err)
+	}
+	if err := re.ScanReader(strings.NewReader("none"), regexp.MustCompile(`:(\d+)`), &port); !errors.Is(err, re.NotFound) {
+		t.Errorf("ScanReader error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+	failure := errors.New("failure")
+	r := io.MultiReader(strings.NewReader("no match yet"), errReader{failure})
+	if err := re.ScanReader(r, regexp.MustCompile(`:(\d+)`), &port); !errors.Is(err, failure) {
+		t.Errorf("ScanReader error was %v, want %v", err, failure)
+	}
+}
+
+// errReader is an io.Reader that always fails with err.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
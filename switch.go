@@ -0,0 +1,79 @@
+package re
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// A Switch dispatches inputs to the handler of the first of several
+// regular expressions that matches, replacing chains of if/else
+// statements around calls to Scan:
+//
+//	var user string
+//	var port int
+//	var sw re.Switch
+//	sw.Case(regexp.MustCompile(`^Accepted password for (\w+)`), func() error {
+//		return logins.Add(user)
+//	}, &user)
+//	sw.Case(regexp.MustCompile(`^Server listening on .* port (\d+)`), func() error {
+//		return ports.Add(port)
+//	}, &port)
+//	for _, line := range lines {
+//		if err := sw.Dispatch(line); err != nil && !errors.Is(err, re.NotFound) {
+//			return err
+//		}
+//	}
+//
+// The zero value is an empty Switch.  Since each case stores into
+// outputs bound ahead of time, a Switch is not safe for concurrent use.
+type Switch struct {
+	cases []switchCase
+
+	// Default, if non-nil, is called with inputs that no case matches.
+	Default func(input []byte) error
+}
+
+type switchCase struct {
+	scanner *Scanner
+	handler func() error
+}
+
+// Case adds a case that matches re.  When it is the first case to match
+// an input, its sub-matches are stored into output as by Scan, and then
+// handler is called.  An error is returned if handler is nil or if the
+// outputs cannot be bound to re; see Bind.
+func (sw *Switch) Case(re *regexp.Regexp, handler func() error, output ...interface{}) error {
+	if handler == nil {
+		return fmt.Errorf("re.Switch: nil handler for case %s", re)
+	}
+	s, err := Bind(re, output...)
+	if err != nil {
+		return err
+	}
+	sw.cases = append(sw.cases, switchCase{s, handler})
+	return nil
+}
+
+// Dispatch finds the first case whose regular expression matches input,
+// in the order the cases were added, and returns the result of its
+// handler.  If a sub-match cannot be parsed, Dispatch returns the parse
+// error without trying later cases or calling the handler.  If no case
+// matches, Dispatch returns the result of Default, or an error wrapping
+// NotFound if Default is nil.
+func (sw *Switch) Dispatch(input []byte) error {
+	for _, c := range sw.cases {
+		err := c.scanner.Scan(input)
+		if errors.Is(err, NotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return c.handler()
+	}
+	if sw.Default != nil {
+		return sw.Default(input)
+	}
+	return fmt.Errorf("re.Switch: no case matches %q: %w", input, NotFound)
+}
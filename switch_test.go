@@ -0,0 +1,77 @@
+package re_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestSwitch(t *testing.T) {
+	var got []string
+	var user string
+	var port int
+	var code uint8
+	var sw re.Switch
+	must := func(err error) {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	must(sw.Case(regexp.MustCompile(`login (\w+)`), func() error {
+		got = append(got, "login:"+user)
+		return nil
+	}, &user))
+	must(sw.Case(regexp.MustCompile(`port (\d+)`), func() error {
+		got = append(got, fmt.Sprint("port:", port))
+		return nil
+	}, &port))
+	must(sw.Case(regexp.MustCompile(`exit (\d+)`), func() error {
+		return fmt.Errorf("exited with %d", code)
+	}, &code))
+	must(sw.Case(regexp.MustCompile(`\w+`), func() error {
+		got = append(got, "word")
+		return nil
+	}))
+
+	for _, line := range []string{"login bob on port 22", "port 80", "hello", "  "} {
+		if err := sw.Dispatch([]byte(line)); err != nil && !errors.Is(err, re.NotFound) {
+			t.Errorf("Dispatch(%q): unexpected error: %s", line, err)
+		}
+	}
+	if want := fmt.Sprint([]string{"login:bob", "port:80", "word"}); fmt.Sprint(got) != want {
+		t.Errorf("got %v; expected %v", got, want)
+	}
+	if err := sw.Dispatch([]byte("  ")); !errors.Is(err, re.NotFound) {
+		t.Errorf("Dispatch error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+	if err := sw.Dispatch([]byte("exit 3")); err == nil || err.Error() != "exited with 3" {
+		t.Errorf("Dispatch did not return the handler error: %v", err)
+	}
+	// A parse error stops dispatch instead of falling through to "word".
+	got = nil
+	if err := sw.Dispatch([]byte("exit 300")); err == nil || errors.Is(err, re.NotFound) {
+		t.Errorf("Dispatch of out of range code: got error %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Dispatch after parse error ran %v", got)
+	}
+
+	var unmatched string
+	sw.Default = func(input []byte) error {
+		unmatched = string(input)
+		return nil
+	}
+	if err := sw.Dispatch([]byte("  ")); err != nil || unmatched != "  " {
+		t.Errorf("Default got %q, %v", unmatched, err)
+	}
+
+	if err := sw.Case(regexp.MustCompile(`x`), func() error { return nil }, &user); err == nil {
+		t.Errorf("Case with too many outputs succeeded unexpectedly")
+	}
+	if err := sw.Case(regexp.MustCompile(`x`), nil); err == nil {
+		t.Errorf("Case with nil handler succeeded unexpectedly")
+	}
+}
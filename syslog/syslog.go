@@ -0,0 +1,311 @@
+/*
+Package syslog parses syslog messages in the two formats in common use:
+the traditional BSD format described by RFC 3164, e.g.,
+
+	<34>Oct 11 22:14:15 mymachine su[123]: 'su root' failed for lonvick
+
+and the structured format of RFC 5424, e.g.,
+
+	<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"] An application event
+
+Parse accepts either format.  The priority of RFC 3164 messages is
+optional, so lines copied from files such as /var/log/syslog, which
+usually lack it, are accepted too.
+
+A *Message implements encoding.TextUnmarshaler, so a syslog line
+embedded in other text can be extracted with re.Scan:
+
+	var m syslog.Message
+	err := re.Scan(regexp.MustCompile(`^\S+ relay: (.*)$`), line, &m)
+*/
+package syslog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+// A Message is one parsed syslog message.
+type Message struct {
+	// Priority is the value between the angle brackets at the start of
+	// the message, or -1 if the message has no priority.
+	Priority int
+
+	// Version is 0 for an RFC 3164 message, and the protocol version
+	// (currently always 1) for an RFC 5424 message.
+	Version int
+
+	Time     time.Time // Zero if the message has no timestamp
+	Hostname string    // Empty if absent or "-"
+	AppName  string    // The tag of an RFC 3164 message; empty if absent or "-"
+	ProcID   string    // The bracketed pid of an RFC 3164 tag; empty if absent or "-"
+	MsgID    string    // Only in RFC 5424 messages; empty if absent or "-"
+
+	// StructuredData holds the SD-ELEMENTs of an RFC 5424 message.
+	StructuredData []Element
+
+	// Message is the free-form text of the message, without a leading
+	// byte order mark.
+	Message string
+}
+
+// An Element is one SD-ELEMENT of an RFC 5424 message, such as
+// [exampleSDID@32473 iut="3" eventSource="Application"].
+type Element struct {
+	ID     string
+	Params map[string]string // Parameter values, with escapes removed
+}
+
+// Facility returns the facility encoded in m's priority, or -1 if m has
+// no priority.
+func (m *Message) Facility() Facility {
+	if m.Priority < 0 {
+		return -1
+	}
+	return Facility(m.Priority >> 3)
+}
+
+// Severity returns the severity encoded in m's priority, or -1 if m has
+// no priority.
+func (m *Message) Severity() Severity {
+	if m.Priority < 0 {
+		return -1
+	}
+	return Severity(m.Priority & 7)
+}
+
+// A Facility identifies the part of the system that sent a message.
+type Facility int
+
+var facilityNames = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// String returns the conventional name of f, e.g., "daemon" or "local0".
+func (f Facility) String() string {
+	if f >= 0 && int(f) < len(facilityNames) {
+		return facilityNames[f]
+	}
+	return fmt.Sprintf("Facility(%d)", int(f))
+}
+
+// A Severity is the importance of a message.  Lower values are more
+// severe.
+type Severity int
+
+// Severities, in order of decreasing importance.
+const (
+	Emergency Severity = iota
+	Alert
+	Critical
+	Error
+	Warning
+	Notice
+	Informational
+	Debug
+)
+
+var severityNames = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// String returns the conventional name of s, e.g., "err" or "info".
+func (s Severity) String() string {
+	if s >= 0 && int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Parse parses a syslog message in either format, choosing RFC 5424 if
+// the priority is followed by a version number.  Timestamps of RFC 3164
+// messages, which lack a year, are placed relative to the current time;
+// see ParseRFC3164.
+func Parse(line []byte) (Message, error) {
+	if version.Match(line) {
+		return ParseRFC5424(line)
+	}
+	return ParseRFC3164(line, time.Now())
+}
+
+// version matches the start of an RFC 5424 message.
+var version = regexp.MustCompile(`^<\d{1,3}>\d{1,2} `)
+
+// UnmarshalText implements encoding.TextUnmarshaler by calling Parse.
+func (m *Message) UnmarshalText(text []byte) error {
+	p, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*m = p
+	return nil
+}
+
+// Pattern5424 matches an RFC 5424 message, capturing the priority,
+// version, timestamp, hostname, app name, process id, message id,
+// structured data and message.
+var Pattern5424 = regexp.MustCompile(`(?s)^<(\d{1,3})>(\d{1,2}) (\S+) (\S+) (\S+) (\S+) (\S+) ` +
+	`(-|(?:\[[^\s\]]+(?:\s+[^\s=\]]+="(?:[^"\\]|\\.)*")*\s*\])+)(?: (.*))?$`)
+
+var (
+	sdElement = regexp.MustCompile(`\[([^\s\]]+)((?:\s+[^\s=\]]+="(?:[^"\\]|\\.)*")*)\s*\]`)
+	sdParam   = regexp.MustCompile(`([^\s=\]]+)="((?:[^"\\]|\\.)*)"`)
+	sdEscape  = regexp.MustCompile(`\\(["\\\]])`)
+)
+
+// ParseRFC5424 parses an RFC 5424 message.
+func ParseRFC5424(line []byte) (Message, error) {
+	m := Message{Priority: -1}
+	var sd []byte
+	err := re.Scan(Pattern5424, line,
+		priority(&m.Priority),
+		&m.Version,
+		func(b []byte) error {
+			if string(b) == "-" {
+				return nil
+			}
+			t, err := time.Parse(time.RFC3339Nano, string(b))
+			if err != nil {
+				return err
+			}
+			m.Time = t
+			return nil
+		},
+		nilValue(&m.Hostname),
+		nilValue(&m.AppName),
+		nilValue(&m.ProcID),
+		nilValue(&m.MsgID),
+		&sd,
+		func(b []byte) error {
+			m.Message = string(bytes.TrimPrefix(b, []byte("\uFEFF")))
+			return nil
+		},
+	)
+	if errors.Is(err, re.NotFound) {
+		return Message{}, fmt.Errorf("syslog: malformed RFC 5424 message %q", line)
+	} else if err != nil {
+		return Message{}, fmt.Errorf("syslog: %w", err)
+	}
+	for _, e := range sdElement.FindAllSubmatch(sd, -1) {
+		el := Element{ID: string(e[1]), Params: map[string]string{}}
+		for _, p := range sdParam.FindAllSubmatch(e[2], -1) {
+			el.Params[string(p[1])] = string(sdEscape.ReplaceAll(p[2], []byte("$1")))
+		}
+		m.StructuredData = append(m.StructuredData, el)
+	}
+	return m, nil
+}
+
+// Pattern3164 matches an RFC 3164 message, capturing the priority (if
+// any), the timestamp, and the remainder of the message.  The timestamp
+// is either the traditional "Mmm dd hh:mm:ss" or, as written by some
+// modern daemons, an RFC 3339 timestamp.
+var Pattern3164 = regexp.MustCompile(`(?s)^(?:<(\d{1,3})>)?` +
+	`([A-Z][a-z]{2} [ \d]\d \d\d:\d\d:\d\d|\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d(?:\.\d+)?(?:Z|[-+]\d\d:\d\d)) (.*)$`)
+
+// tag matches the tag at the start of the text of an RFC 3164 message,
+// capturing the app name and process id.
+var tag = regexp.MustCompile(`(?s)^([^\s:\[\]]+)(?:\[([^\s\]]*)\])?: ?(.*)$`)
+
+// ParseRFC3164 parses an RFC 3164 message.  Traditional timestamps lack
+// a year and a time zone; they are interpreted in the location of now
+// and given the year that places them closest to now, so that messages
+// logged in late December and read in early January are not placed in
+// the future.
+//
+// The hostname is optional: if the word after the timestamp looks like a
+// tag (e.g., "sshd[42]:"), the message is taken to have no hostname.
+func ParseRFC3164(line []byte, now time.Time) (Message, error) {
+	m := Message{Priority: -1}
+	var rest []byte
+	err := re.Scan(Pattern3164, line,
+		priority(&m.Priority),
+		func(b []byte) error {
+			t, err := parseTimestamp(string(b), now)
+			m.Time = t
+			return err
+		},
+		&rest,
+	)
+	if errors.Is(err, re.NotFound) {
+		return Message{}, fmt.Errorf("syslog: malformed RFC 3164 message %q", line)
+	} else if err != nil {
+		return Message{}, fmt.Errorf("syslog: %w", err)
+	}
+
+	if !tag.Match(rest) {
+		if i := bytes.IndexByte(rest, ' '); i >= 0 {
+			m.Hostname, rest = string(rest[:i]), rest[i+1:]
+		}
+	}
+	var msg []byte
+	if re.Scan(tag, rest, &m.AppName, &m.ProcID, &msg) != nil {
+		msg = rest
+	}
+	m.Message = string(msg)
+	return m, nil
+}
+
+// parseTimestamp parses an RFC 3164 timestamp; see ParseRFC3164.
+func parseTimestamp(s string, now time.Time) (time.Time, error) {
+	if strings.Contains(s, "T") {
+		return time.Parse(time.RFC3339Nano, s)
+	}
+	t, err := time.ParseInLocation("Jan _2 15:04:05", s, now.Location())
+	if err != nil {
+		return time.Time{}, err
+	}
+	best := t.AddDate(now.Year()-t.Year(), 0, 0)
+	for _, dy := range []int{-1, 1} {
+		c := t.AddDate(now.Year()+dy-t.Year(), 0, 0)
+		if abs(c.Sub(now)) < abs(best.Sub(now)) {
+			best = c
+		}
+	}
+	return best, nil
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// priority returns an output argument for re.Scan that parses a
+// priority into *p, leaving it unchanged if the priority is absent.
+func priority(p *int) func([]byte) error {
+	return func(b []byte) error {
+		if b == nil {
+			return nil
+		}
+		var v int
+		if err := re.Scan(digits, b, &v); err != nil {
+			return err
+		}
+		if v > 191 {
+			return fmt.Errorf("priority %d out of range", v)
+		}
+		*p = v
+		return nil
+	}
+}
+
+var digits = regexp.MustCompile(`^(\d+)$`)
+
+// nilValue returns an output argument for re.Scan that stores a header
+// field into *s, leaving *s empty for the nil value "-".
+func nilValue(s *string) func([]byte) error {
+	return func(b []byte) error {
+		if string(b) != "-" {
+			*s = string(b)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,141 @@
+package syslog_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/syslog"
+)
+
+func TestParseRFC5424(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  syslog.Message
+	}{
+		{
+			`<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - 'su root' failed for lonvick on /dev/pts/8`,
+			syslog.Message{Priority: 34, Version: 1, Time: time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC),
+				Hostname: "mymachine.example.com", AppName: "su", MsgID: "ID47",
+				Message: "'su root' failed for lonvick on /dev/pts/8"},
+		},
+		{
+			"<165>1 2003-08-24T05:14:15.000003-07:00 192.0.2.1 myproc 8710 - - \uFEFF%% It's time to make the do-nuts.",
+			syslog.Message{Priority: 165, Version: 1, Time: time.Date(2003, 8, 24, 12, 14, 15, 3000, time.UTC),
+				Hostname: "192.0.2.1", AppName: "myproc", ProcID: "8710",
+				Message: "%% It's time to make the do-nuts."},
+		},
+		{
+			`<165>1 - - - - - [exampleSDID@32473 iut="3" eventSource="Application"][examplePriority@32473 class="high" note="a \"b\" \] c"]`,
+			syslog.Message{Priority: 165, Version: 1, StructuredData: []syslog.Element{
+				{"exampleSDID@32473", map[string]string{"iut": "3", "eventSource": "Application"}},
+				{"examplePriority@32473", map[string]string{"class": "high", "note": `a "b" ] c`}},
+			}},
+		},
+	} {
+		got, err := syslog.ParseRFC5424([]byte(c.input))
+		if err != nil {
+			t.Errorf("ParseRFC5424(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		if !got.Time.Equal(c.want.Time) {
+			t.Errorf("ParseRFC5424(%q).Time = %v; expected %v", c.input, got.Time, c.want.Time)
+		}
+		got.Time, c.want.Time = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseRFC5424(%q) =\n%+v\nexpected\n%+v", c.input, got, c.want)
+		}
+	}
+	for _, bad := range []string{
+		"",
+		"<34>1 2003-10-11T22:14:15Z host app - -",
+		"<34>1 yesterday host app - - - msg",
+		"<192>1 - - - - - - msg",
+		`<34>1 - - - - - [id x=1] msg`,
+	} {
+		if m, err := syslog.ParseRFC5424([]byte(bad)); err == nil {
+			t.Errorf("ParseRFC5424(%q) = %+v; expected an error", bad, m)
+		}
+	}
+}
+
+func TestParseRFC3164(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		input string
+		want  syslog.Message
+	}{
+		{
+			`<34>Oct 11 22:14:15 mymachine su[123]: 'su root' failed`,
+			syslog.Message{Priority: 34, Time: time.Date(2023, 10, 11, 22, 14, 15, 0, time.UTC),
+				Hostname: "mymachine", AppName: "su", ProcID: "123", Message: "'su root' failed"},
+		},
+		{
+			// No priority, no hostname, and a date just after now.
+			`Jan  3 01:02:03 kernel: [ 0.000000] Linux version 6.1`,
+			syslog.Message{Priority: -1, Time: time.Date(2024, 1, 3, 1, 2, 3, 0, time.UTC),
+				AppName: "kernel", Message: "[ 0.000000] Linux version 6.1"},
+		},
+		{
+			`<13>2024-01-01T10:00:00.5+01:00 host.example.com CRON[42]: (root) CMD (run-parts)`,
+			syslog.Message{Priority: 13, Time: time.Date(2024, 1, 1, 9, 0, 0, 500000000, time.UTC),
+				Hostname: "host.example.com", AppName: "CRON", ProcID: "42", Message: "(root) CMD (run-parts)"},
+		},
+		{
+			`<13>Dec 31 23:59:59 host no tag here`,
+			syslog.Message{Priority: 13, Time: time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC),
+				Hostname: "host", Message: "no tag here"},
+		},
+	} {
+		got, err := syslog.ParseRFC3164([]byte(c.input), now)
+		if err != nil {
+			t.Errorf("ParseRFC3164(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		if !got.Time.Equal(c.want.Time) {
+			t.Errorf("ParseRFC3164(%q).Time = %v; expected %v", c.input, got.Time, c.want.Time)
+		}
+		got.Time, c.want.Time = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseRFC3164(%q) =\n%+v\nexpected\n%+v", c.input, got, c.want)
+		}
+	}
+	for _, bad := range []string{"", "hello world", "<34>Oct 32 22:14:15 host x: y", "<999>Oct 11 22:14:15 host x: y"} {
+		if m, err := syslog.ParseRFC3164([]byte(bad), now); err == nil {
+			t.Errorf("ParseRFC3164(%q) = %+v; expected an error", bad, m)
+		}
+	}
+}
+
+func TestPriority(t *testing.T) {
+	m, err := syslog.Parse([]byte(`<165>1 - - - - - -`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, s := m.Facility(), m.Severity(); f.String() != "local4" || s != syslog.Notice || s.String() != "notice" {
+		t.Errorf("Facility, Severity = %v, %v; expected local4, notice", f, s)
+	}
+	m, err = syslog.Parse([]byte(`Oct 11 22:14:15 host app: msg`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != 0 || m.Facility() != -1 || m.Severity() != -1 {
+		t.Errorf("Parse of RFC 3164 message without priority = %+v", m)
+	}
+	if s := syslog.Severity(9).String(); s != "Severity(9)" {
+		t.Errorf("Severity(9).String() = %q", s)
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+	var m syslog.Message
+	line := []byte(`relay: <86>1 2024-01-15T10:00:00Z gw sshd 99 - - Accepted publickey`)
+	if err := re.Scan(regexp.MustCompile(`^relay: (.*)$`), line, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.AppName != "sshd" || m.ProcID != "99" || m.Severity() != syslog.Informational || m.Message != "Accepted publickey" {
+		t.Errorf("got %+v", m)
+	}
+}
@@ -0,0 +1,111 @@
+package systemd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+// An Entry is one journal entry, mapping field names such as "MESSAGE"
+// or "_SYSTEMD_UNIT" to their values.  Binary values are stored as is.
+type Entry map[string]string
+
+// Time returns the wall-clock time at which the entry was recorded,
+// taken from its __REALTIME_TIMESTAMP field.
+func (e Entry) Time() (time.Time, error) {
+	usec, err := strconv.ParseInt(e["__REALTIME_TIMESTAMP"], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("systemd: bad __REALTIME_TIMESTAMP %q", e["__REALTIME_TIMESTAMP"])
+	}
+	return time.Unix(usec/1e6, usec%1e6*1e3), nil
+}
+
+// Scan matches pattern against the value of the named field and stores
+// the sub-matches into output, as re.Scan does.  A missing field is
+// treated as an empty value.
+func (e Entry) Scan(field string, pattern *regexp.Regexp, output ...interface{}) error {
+	return re.Scan(pattern, []byte(e[field]), output...)
+}
+
+// A Reader reads journal entries in the export format produced by
+// "journalctl -o export": fields are either "NAME=value" lines or, for
+// values that contain newlines or binary data, a "NAME" line followed by
+// a little-endian 64-bit length, the value and a newline.  Entries are
+// separated by blank lines.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader returns a Reader that reads entries from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// maxBinaryField limits the length of a binary field, so that a corrupt
+// length does not cause a huge allocation.
+const maxBinaryField = 1 << 30
+
+// Next returns the next entry.  It returns io.EOF when there are no more
+// entries, and io.ErrUnexpectedEOF if the input ends inside a field.
+func (r *Reader) Next() (Entry, error) {
+	var e Entry
+	for {
+		line, err := r.r.ReadString('\n')
+		if err == io.EOF && line == "" {
+			if e == nil {
+				return nil, io.EOF
+			}
+			return e, nil // Final entry without a trailing blank line
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if e == nil {
+				continue // Extra separator
+			}
+			return e, nil
+		}
+		if e == nil {
+			e = Entry{}
+		}
+		if i := strings.IndexByte(line, '='); i >= 0 {
+			e[line[:i]] = line[i+1:]
+			continue
+		}
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		var n uint64
+		if err := binary.Read(r.r, binary.LittleEndian, &n); err != nil {
+			return nil, unexpected(err)
+		}
+		if n > maxBinaryField {
+			return nil, fmt.Errorf("systemd: field %s has length %d", line, n)
+		}
+		value := make([]byte, n+1)
+		if _, err := io.ReadFull(r.r, value); err != nil {
+			return nil, unexpected(err)
+		}
+		if value[n] != '\n' {
+			return nil, errors.New("systemd: binary field " + line + " not followed by newline")
+		}
+		e[line] = string(value[:n])
+	}
+}
+
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
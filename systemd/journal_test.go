@@ -0,0 +1,74 @@
+package systemd_test
+
+import (
+	"encoding/binary"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re/systemd"
+)
+
+// binaryField returns name and value in the binary field encoding.
+func binaryField(name, value string) string {
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], uint64(len(value)))
+	return name + "\n" + string(n[:]) + value + "\n"
+}
+
+func TestReader(t *testing.T) {
+	input := "__REALTIME_TIMESTAMP=1700000000123456\n" +
+		"_SYSTEMD_UNIT=nginx.service\n" +
+		"MESSAGE=started on port 8080\n" +
+		"\n\n" +
+		"__REALTIME_TIMESTAMP=1700000001000000\n" +
+		binaryField("MESSAGE", "line one\nline two") +
+		"EMPTY=\n"
+	r := systemd.NewReader(strings.NewReader(input))
+
+	e, err := r.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if e["_SYSTEMD_UNIT"] != "nginx.service" || e["MESSAGE"] != "started on port 8080" {
+		t.Errorf("first entry = %v", e)
+	}
+	if ts, err := e.Time(); err != nil || !ts.Equal(time.Unix(1700000000, 123456000)) {
+		t.Errorf("Time() = %v, %v", ts, err)
+	}
+	var port int
+	if err := e.Scan("MESSAGE", regexp.MustCompile(`port (\d+)`), &port); err != nil || port != 8080 {
+		t.Errorf("Scan = %d, %v", port, err)
+	}
+
+	e, err = r.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := systemd.Entry{"__REALTIME_TIMESTAMP": "1700000001000000", "MESSAGE": "line one\nline two", "EMPTY": ""}
+	if !reflect.DeepEqual(e, want) {
+		t.Errorf("second entry = %q; expected %q", e, want)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next at end returned %v; expected io.EOF", err)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	for _, input := range []string{
+		"MESSAGE\n\x05\x00",                              // Truncated length
+		"MESSAGE\n\x05\x00\x00\x00\x00\x00\x00\x00ab",    // Truncated value
+		"MESSAGE\n\x02\x00\x00\x00\x00\x00\x00\x00abc\n", // Missing newline
+		"MESSAGE",
+	} {
+		if e, err := systemd.NewReader(strings.NewReader(input)).Next(); err == nil {
+			t.Errorf("Next(%q) = %q; expected an error", input, e)
+		}
+	}
+	if _, err := (systemd.Entry{}).Time(); err == nil {
+		t.Errorf("Time() without a timestamp succeeded unexpectedly")
+	}
+}
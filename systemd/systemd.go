@@ -0,0 +1,180 @@
+/*
+Package systemd parses values written in the systemd configuration
+grammar, and reads entries in the systemd journal export format.
+
+Timespans (e.g., "1week 2d", "90", "infinity"), sizes (e.g., "512K",
+"infinity") and booleans (e.g., "yes", "off") in unit files differ from
+their Go counterparts.  Timespan, Size and Bool return output arguments
+for re.Scan that parse them:
+
+	var timeout time.Duration
+	err := re.Scan(systemd.KeyPattern, line, nil, systemd.Timespan(&timeout))
+
+Reader reads the output of "journalctl -o export".
+*/
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// SectionPattern matches a unit-file section header such as
+	// "[Service]", capturing the section name.
+	SectionPattern = regexp.MustCompile(`^\s*\[([^\]]+)\]\s*$`)
+
+	// KeyPattern matches a unit-file assignment such as
+	// "TimeoutStartSec = 90s", capturing the key and the value with
+	// surrounding whitespace removed.
+	KeyPattern = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+)\s*=\s*(.*?)\s*$`)
+)
+
+// Infinity is the timespan "infinity".
+const Infinity = time.Duration(math.MaxInt64)
+
+// timeUnits maps the unit names accepted in timespans to their lengths.
+var timeUnits = map[string]time.Duration{
+	"us": time.Microsecond, "usec": time.Microsecond, "µs": time.Microsecond, "μs": time.Microsecond,
+	"ms": time.Millisecond, "msec": time.Millisecond,
+	"s": time.Second, "sec": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+	"M": 2629800 * time.Second, "month": 2629800 * time.Second, "months": 2629800 * time.Second,
+	"y": 31557600 * time.Second, "year": 31557600 * time.Second, "years": 31557600 * time.Second,
+}
+
+// timespanTerm matches one term of a timespan: a number and an optional
+// unit.
+var timespanTerm = regexp.MustCompile(`^\s*(\d+(?:\.\d+)?)\s*([a-zA-Zµμ]*)`)
+
+// ParseTimespan parses a systemd timespan: a sequence of numbers, each
+// followed by an optional unit, e.g., "1week 2d", "5min 30s" or "1.5h".
+// Months are 30.44 days and years 365.25 days, as in systemd.  A number
+// without a unit is in units of defaultUnit (time.Second for most
+// settings, such as TimeoutSec=).  "infinity" yields Infinity.
+func ParseTimespan(s string, defaultUnit time.Duration) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "infinity" {
+		return Infinity, nil
+	}
+	if s == "" {
+		return 0, errors.New("systemd: empty timespan")
+	}
+	var total float64
+	for rest := s; strings.TrimSpace(rest) != ""; {
+		m := timespanTerm.FindStringSubmatch(rest)
+		if m == nil {
+			return 0, fmt.Errorf("systemd: bad timespan %q", s)
+		}
+		unit := defaultUnit
+		if m[2] != "" {
+			var ok bool
+			if unit, ok = timeUnits[m[2]]; !ok {
+				return 0, fmt.Errorf("systemd: unknown unit %q in timespan %q", m[2], s)
+			}
+		}
+		v, _ := strconv.ParseFloat(m[1], 64)
+		total += v * float64(unit)
+		rest = rest[len(m[0]):]
+	}
+	if total >= float64(math.MaxInt64) {
+		return 0, fmt.Errorf("systemd: timespan %q out of range", s)
+	}
+	return time.Duration(total), nil
+}
+
+// sizeUnits maps size suffixes to their multipliers; systemd uses base
+// 1024 for all of them.
+var sizeUnits = map[byte]uint{'K': 10, 'M': 20, 'G': 30, 'T': 40, 'P': 50, 'E': 60}
+
+// ParseSize parses a systemd size, such as "512", "64K" or "1.5G".  The
+// suffixes K, M, G, T, P and E denote powers of 1024.  "infinity" yields
+// math.MaxUint64.
+func ParseSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if s == "infinity" {
+		return math.MaxUint64, nil
+	}
+	num, shift := s, uint(0)
+	if n := len(s); n > 0 {
+		if sh, ok := sizeUnits[s[n-1]]; ok {
+			num, shift = strings.TrimSpace(s[:n-1]), sh
+		}
+	}
+	if v, err := strconv.ParseUint(num, 10, 64); err == nil {
+		if shift > 0 && v > math.MaxUint64>>shift {
+			return 0, fmt.Errorf("systemd: size %q out of range", s)
+		}
+		return v << shift, nil
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 || strings.ContainsAny(num, "eEnN+") {
+		return 0, fmt.Errorf("systemd: bad size %q", s)
+	}
+	v = math.Ldexp(v, int(shift))
+	if v >= math.MaxUint64 {
+		return 0, fmt.Errorf("systemd: size %q out of range", s)
+	}
+	return uint64(v), nil
+}
+
+// ParseBool parses a systemd boolean: "1", "yes", "y", "true", "t" and
+// "on" are true, and "0", "no", "n", "false", "f" and "off" are false,
+// ignoring case.
+func ParseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "yes", "y", "true", "t", "on":
+		return true, nil
+	case "0", "no", "n", "false", "f", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("systemd: bad boolean %q", s)
+}
+
+// Timespan returns an output argument for re.Scan that parses the
+// sub-match with ParseTimespan, using seconds as the default unit, and
+// stores the result into *d.
+func Timespan(d *time.Duration) func([]byte) error {
+	return func(b []byte) error {
+		v, err := ParseTimespan(string(b), time.Second)
+		if err != nil {
+			return err
+		}
+		*d = v
+		return nil
+	}
+}
+
+// Size returns an output argument for re.Scan that parses the sub-match
+// with ParseSize and stores the result into *n.
+func Size(n *uint64) func([]byte) error {
+	return func(b []byte) error {
+		v, err := ParseSize(string(b))
+		if err != nil {
+			return err
+		}
+		*n = v
+		return nil
+	}
+}
+
+// Bool returns an output argument for re.Scan that parses the sub-match
+// with ParseBool and stores the result into *v.
+func Bool(v *bool) func([]byte) error {
+	return func(b []byte) error {
+		x, err := ParseBool(string(b))
+		if err != nil {
+			return err
+		}
+		*v = x
+		return nil
+	}
+}
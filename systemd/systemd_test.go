@@ -0,0 +1,110 @@
+package systemd_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/systemd"
+)
+
+func TestParseTimespan(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  time.Duration
+	}{
+		{"90", 90 * time.Second},
+		{"90s", 90 * time.Second},
+		{"5min 30s", 5*time.Minute + 30*time.Second},
+		{"1week 2d", 9 * 24 * time.Hour},
+		{"2h30m", 2*time.Hour + 30*time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"20ms", 20 * time.Millisecond},
+		{"1M", 2629800 * time.Second},
+		{"1y", 31557600 * time.Second},
+		{"infinity", systemd.Infinity},
+	} {
+		got, err := systemd.ParseTimespan(c.input, time.Second)
+		if err != nil {
+			t.Errorf("ParseTimespan(%q): unexpected error: %s", c.input, err)
+		} else if got != c.want {
+			t.Errorf("ParseTimespan(%q) = %v; expected %v", c.input, got, c.want)
+		}
+	}
+	if got, _ := systemd.ParseTimespan("500", time.Millisecond); got != 500*time.Millisecond {
+		t.Errorf("ParseTimespan with default unit ms = %v", got)
+	}
+	for _, bad := range []string{"", "abc", "5 fortnights", "-5s", "1000000y"} {
+		if got, err := systemd.ParseTimespan(bad, time.Second); err == nil {
+			t.Errorf("ParseTimespan(%q) = %v; expected an error", bad, got)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  uint64
+	}{
+		{"512", 512},
+		{"64K", 64 << 10},
+		{"1.5G", 3 << 29},
+		{"2 T", 2 << 40},
+		{"infinity", math.MaxUint64},
+	} {
+		got, err := systemd.ParseSize(c.input)
+		if err != nil {
+			t.Errorf("ParseSize(%q): unexpected error: %s", c.input, err)
+		} else if got != c.want {
+			t.Errorf("ParseSize(%q) = %d; expected %d", c.input, got, c.want)
+		}
+	}
+	for _, bad := range []string{"", "K", "12X", "-1K", "1e3", "NaN", "32E"} {
+		if got, err := systemd.ParseSize(bad); err == nil {
+			t.Errorf("ParseSize(%q) = %d; expected an error", bad, got)
+		}
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	for _, s := range []string{"1", "yes", "Y", "true", "t", "ON"} {
+		if v, err := systemd.ParseBool(s); err != nil || !v {
+			t.Errorf("ParseBool(%q) = %v, %v; expected true", s, v, err)
+		}
+	}
+	for _, s := range []string{"0", "no", "n", "FALSE", "f", "off"} {
+		if v, err := systemd.ParseBool(s); err != nil || v {
+			t.Errorf("ParseBool(%q) = %v, %v; expected false", s, v, err)
+		}
+	}
+	if _, err := systemd.ParseBool("maybe"); err == nil {
+		t.Errorf("ParseBool(maybe) succeeded unexpectedly")
+	}
+}
+
+func TestScanUnitFile(t *testing.T) {
+	var section, key string
+	if err := re.Scan(systemd.SectionPattern, []byte(" [Service] "), &section); err != nil || section != "Service" {
+		t.Errorf("section = %q, %v", section, err)
+	}
+
+	var timeout time.Duration
+	if err := re.Scan(systemd.KeyPattern, []byte("TimeoutStartSec = 1min 30s "), &key, systemd.Timespan(&timeout)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if key != "TimeoutStartSec" || timeout != 90*time.Second {
+		t.Errorf("got %s = %v", key, timeout)
+	}
+	var limit uint64
+	if err := re.Scan(systemd.KeyPattern, []byte("MemoryMax=512M"), nil, systemd.Size(&limit)); err != nil || limit != 512<<20 {
+		t.Errorf("MemoryMax = %d, %v", limit, err)
+	}
+	var restart bool
+	if err := re.Scan(systemd.KeyPattern, []byte("RemainAfterExit=yes"), nil, systemd.Bool(&restart)); err != nil || !restart {
+		t.Errorf("RemainAfterExit = %v, %v", restart, err)
+	}
+	if err := re.Scan(systemd.KeyPattern, []byte("RemainAfterExit=sure"), nil, systemd.Bool(&restart)); err == nil {
+		t.Errorf("Bool accepted a bad value")
+	}
+}
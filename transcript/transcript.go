@@ -0,0 +1,275 @@
+/*
+Package transcript splits captures of line-oriented text protocol
+sessions, such as SMTP or FTP dialogues and redis-cli sessions, into
+typed turns:
+
+	turns, err := transcript.SMTP().Frame(file)
+	if err != nil {
+		return err
+	}
+	for _, t := range turns {
+		if t.Direction == transcript.Response && t.Status >= 500 {
+			fmt.Printf("line %d: error %d: %s\n", t.Line, t.Status, t.Text)
+		}
+	}
+
+A Framer classifies each line with a re.Switch whose cases are added by
+Rule.  The presets SMTP, FTP and Redis cover common transcript formats;
+other protocols are handled by adding rules to a new Framer.
+*/
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/shell"
+)
+
+// A Direction tells who sent a turn.
+type Direction int
+
+const (
+	Unknown  Direction = iota // Line matched no rule
+	Request                   // Sent by the client
+	Response                  // Sent by the server
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Request:
+		return "request"
+	case Response:
+		return "response"
+	}
+	return "unknown"
+}
+
+// A Turn is one request or response, which may span several lines.
+type Turn struct {
+	Direction Direction
+	Line      int      // Line number, counting from one, of the first line
+	Verb      string   // Upper-cased request verb, e.g., "MAIL"
+	Args      []string // Request arguments
+	Status    int      // Response status code, or 0 if there is none
+	Text      string   // Text of the turn; lines are joined with "\n"
+}
+
+// A Framer splits transcripts into turns.  The zero value has no rules,
+// so every line becomes a turn of Unknown direction.
+type Framer struct {
+	sw re.Switch
+
+	// SplitArgs splits the arguments of a request into words.  If nil,
+	// arguments are separated by white space.
+	SplitArgs func(args string) ([]string, error)
+
+	// DataStatus, if non-zero, is the status of a response after which
+	// requests are message text rather than commands, up to and
+	// including a request consisting of ".", as after the DATA command
+	// of SMTP.  Message text becomes requests without a verb.
+	DataStatus int
+
+	// State of the line being classified.
+	line   []byte
+	m      match
+	turns  []Turn
+	more   bool // Does the last turn continue on the next line?
+	inData bool // Are requests message text?
+}
+
+// match holds the named groups of a rule that matched a line.
+type match struct {
+	dir                    Direction
+	verb, args, text, more string
+	status                 int
+}
+
+// Rule adds a rule that classifies lines matching pattern as turns in
+// direction dir.  Rules are tried in the order they were added, and the
+// first match wins.  The pattern may contain any of the following named
+// groups, whose sub-matches fill in the turn:
+//
+//	verb    the request verb, which is upper-cased
+//	args    the request arguments, split into words by SplitArgs
+//	status  the response status code
+//	text    the text of the turn; if absent, the whole line is used
+//	more    if non-empty, the turn continues on the next line
+//
+// A response line without a status also continues the preceding
+// response, which accommodates servers that send continuation lines
+// without repeating the code, and multi-line redis-cli replies.  An
+// error is returned if the groups cannot be bound to pattern; see
+// re.Bind.
+func (f *Framer) Rule(dir Direction, pattern *regexp.Regexp) error {
+	outputs := []interface{}{}
+	hasText := false
+	for _, name := range pattern.SubexpNames() {
+		switch name {
+		case "verb":
+			outputs = append(outputs, re.Named(name, &f.m.verb))
+		case "args":
+			outputs = append(outputs, re.Named(name, &f.m.args))
+		case "text":
+			outputs = append(outputs, re.Named(name, &f.m.text))
+			hasText = true
+		case "more":
+			outputs = append(outputs, re.Named(name, &f.m.more))
+		case "status":
+			outputs = append(outputs, re.Named(name, func(b []byte) error {
+				f.m.status = 0
+				if b == nil {
+					return nil
+				}
+				return re.Scan(digits, b, &f.m.status)
+			}))
+		}
+	}
+	return f.sw.Case(pattern, func() error {
+		f.m.dir = dir
+		if !hasText {
+			f.m.text = string(f.line)
+		}
+		return nil
+	}, outputs...)
+}
+
+var digits = regexp.MustCompile(`^(\d+)$`)
+
+// Frame reads a transcript from r and returns its turns.  Blank lines
+// are ignored.  An error is returned if r cannot be read, if a status
+// is not a valid integer, or if SplitArgs fails; the turns preceding
+// the offending line are returned along with it.
+func (f *Framer) Frame(r io.Reader) ([]Turn, error) {
+	f.turns, f.more, f.inData = nil, false, false
+	f.sw.Default = func(input []byte) error {
+		f.m = match{dir: Unknown, text: string(input)}
+		return nil
+	}
+	br := bufio.NewReader(r)
+	for lineno := 1; ; lineno++ {
+		b, err := br.ReadBytes('\n')
+		if len(b) == 0 && err != nil {
+			if err == io.EOF {
+				return f.turns, nil
+			}
+			return f.turns, err
+		}
+		b = bytes.TrimRight(b, "\r\n")
+		if len(bytes.TrimSpace(b)) == 0 {
+			continue
+		}
+		if err := f.add(b, lineno); err != nil {
+			return f.turns, fmt.Errorf("transcript: line %d: %w", lineno, err)
+		}
+	}
+}
+
+// add classifies one line and appends it to the turns.
+func (f *Framer) add(b []byte, lineno int) error {
+	f.line, f.m = b, match{}
+	if err := f.sw.Dispatch(b); err != nil {
+		return err
+	}
+	m := f.m
+
+	if n := len(f.turns); n > 0 && m.dir == Response {
+		last := &f.turns[n-1]
+		if last.Direction == Response && (f.more || m.status == 0) {
+			last.Text += "\n" + m.text
+			if m.status != 0 || !f.more {
+				f.more = m.more != ""
+			}
+			return nil
+		}
+	}
+	if f.inData && m.dir == Request {
+		f.inData = m.text != "."
+		f.turns = append(f.turns, Turn{Direction: Request, Line: lineno, Text: m.text})
+		f.more = false
+		return nil
+	}
+	t := Turn{Direction: m.dir, Line: lineno, Verb: strings.ToUpper(m.verb), Status: m.status, Text: m.text}
+	if m.args != "" {
+		split := f.SplitArgs
+		if split == nil {
+			split = func(s string) ([]string, error) { return strings.Fields(s), nil }
+		}
+		args, err := split(m.args)
+		if err != nil {
+			return err
+		}
+		t.Args = args
+	}
+	f.turns = append(f.turns, t)
+	f.more = m.dir == Response && m.more != ""
+	if m.dir == Response && f.DataStatus != 0 {
+		f.inData = m.status == f.DataStatus
+	}
+	return nil
+}
+
+// Prefixes recognized by the SMTP and FTP presets: "C:" and "S:" as in
+// the RFCs, "->" and "<-" as printed by swaks, and ">>>" and "<<<".
+const (
+	clientPrefix = `^(?:C:|->|>>>)[ \t]?`
+	serverPrefix = `^(?:S:|<-|<<<)[ \t]?`
+)
+
+// SMTP returns a Framer for SMTP transcripts in which each line starts
+// with a prefix telling who sent it, e.g.,
+//
+//	S: 220 mail.example.com ESMTP
+//	C: EHLO client.example.com
+//	S: 250-mail.example.com
+//	S: 250 PIPELINING
+//
+// Replies with the same code joined by "-" form a single turn.  Message
+// text sent by the client after the 354 reply to DATA, up to the line
+// holding a lone ".", becomes requests without a verb.
+func SMTP() *Framer {
+	f := &Framer{DataStatus: 354}
+	f.mustRule(Request, clientPrefix+`(?P<text>(?P<verb>[A-Za-z]+)(?:[ \t]+(?P<args>.*?))?)[ \t]*$`)
+	f.mustRule(Request, clientPrefix+`(?P<text>.*)$`)
+	f.mustRule(Response, serverPrefix+`(?P<status>\d{3})(?:(?P<more>-)|[ \t]|$)(?P<text>.*)$`)
+	f.mustRule(Response, serverPrefix+`(?P<text>.*)$`)
+	return f
+}
+
+// FTP returns a Framer for FTP transcripts, which use the same prefixes
+// and reply format as the transcripts handled by SMTP.
+func FTP() *Framer {
+	f := SMTP()
+	f.DataStatus = 0
+	return f
+}
+
+// Redis returns a Framer for redis-cli sessions, in which commands
+// follow a prompt such as "127.0.0.1:6379>" or "redis[1]>" and every
+// other line is part of a reply, e.g.,
+//
+//	127.0.0.1:6379> SET greeting "hello world"
+//	OK
+//	127.0.0.1:6379> LRANGE list 0 -1
+//	1) "a"
+//	2) "b"
+//
+// Arguments are split following the quoting rules of the shell, and
+// multi-line replies form a single turn.
+func Redis() *Framer {
+	f := &Framer{SplitArgs: shell.Split}
+	f.mustRule(Request, `^(?:[\w.\-:\[\]]+|not connected)> [ \t]*(?P<text>(?P<verb>\S+)(?:[ \t]+(?P<args>.*?))?)[ \t]*$`)
+	f.mustRule(Response, `^(?P<text>.*)$`)
+	return f
+}
+
+func (f *Framer) mustRule(dir Direction, pattern string) {
+	if err := f.Rule(dir, regexp.MustCompile(pattern)); err != nil {
+		panic(err)
+	}
+}
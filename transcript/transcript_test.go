@@ -0,0 +1,117 @@
+package transcript_test
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re/transcript"
+)
+
+func TestSMTP(t *testing.T) {
+	input := `S: 220 mail.example.com ESMTP
+C: EHLO client.example.com
+S: 250-mail.example.com
+S: 250-PIPELINING
+S: 250 SIZE 10240000
+C: MAIL FROM:<alice@example.com>
+S: 250 OK
+
+C: DATA
+S: 354 Start mail input
+C: Subject: hi
+C: Hello Bob
+C: .
+S: 550 5.7.1 Rejected
+C: quit
+`
+	got, err := transcript.SMTP().Frame(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []transcript.Turn{
+		{Direction: transcript.Response, Line: 1, Status: 220, Text: "mail.example.com ESMTP"},
+		{Direction: transcript.Request, Line: 2, Verb: "EHLO", Args: []string{"client.example.com"}, Text: "EHLO client.example.com"},
+		{Direction: transcript.Response, Line: 3, Status: 250, Text: "mail.example.com\nPIPELINING\nSIZE 10240000"},
+		{Direction: transcript.Request, Line: 6, Verb: "MAIL", Args: []string{"FROM:<alice@example.com>"}, Text: "MAIL FROM:<alice@example.com>"},
+		{Direction: transcript.Response, Line: 7, Status: 250, Text: "OK"},
+		{Direction: transcript.Request, Line: 9, Verb: "DATA", Text: "DATA"},
+		{Direction: transcript.Response, Line: 10, Status: 354, Text: "Start mail input"},
+		{Direction: transcript.Request, Line: 11, Text: "Subject: hi"},
+		{Direction: transcript.Request, Line: 12, Text: "Hello Bob"},
+		{Direction: transcript.Request, Line: 13, Text: "."},
+		{Direction: transcript.Response, Line: 14, Status: 550, Text: "5.7.1 Rejected"},
+		{Direction: transcript.Request, Line: 15, Verb: "QUIT", Text: "quit"},
+	}
+	checkTurns(t, got, want)
+}
+
+func TestFTPContinuation(t *testing.T) {
+	input := "<- 230-Welcome\r\n<-    to the server\r\n<- 230 Logged in\r\n-> PWD\r\nnoise\r\n"
+	got, err := transcript.FTP().Frame(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []transcript.Turn{
+		{Direction: transcript.Response, Line: 1, Status: 230, Text: "Welcome\n   to the server\nLogged in"},
+		{Direction: transcript.Request, Line: 4, Verb: "PWD", Text: "PWD"},
+		{Direction: transcript.Unknown, Line: 5, Text: "noise"},
+	}
+	checkTurns(t, got, want)
+}
+
+func TestRedis(t *testing.T) {
+	input := `127.0.0.1:6379> SET greeting "hello world"
+OK
+127.0.0.1:6379[1]> lrange list 0 -1
+1) "a"
+2) "b"
+redis> GET 'x
+`
+	got, err := transcript.Redis().Frame(strings.NewReader(input))
+	if err == nil || !strings.Contains(err.Error(), "line 6") {
+		t.Errorf("Frame with unterminated quote: error %v", err)
+	}
+	want := []transcript.Turn{
+		{Direction: transcript.Request, Line: 1, Verb: "SET", Args: []string{"greeting", "hello world"}, Text: `SET greeting "hello world"`},
+		{Direction: transcript.Response, Line: 2, Text: "OK"},
+		{Direction: transcript.Request, Line: 3, Verb: "LRANGE", Args: []string{"list", "0", "-1"}, Text: "lrange list 0 -1"},
+		{Direction: transcript.Response, Line: 4, Text: "1) \"a\"\n2) \"b\""},
+	}
+	checkTurns(t, got, want)
+}
+
+func TestRule(t *testing.T) {
+	var f transcript.Framer
+	if err := f.Rule(transcript.Request, regexp.MustCompile(`^\+ (?P<verb>\w+) ?(?P<args>.*)$`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Rule(transcript.Response, regexp.MustCompile(`^\* (?P<status>\d+)`)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.Frame(strings.NewReader("+ get a b\n* 200 done\n* 99999999999999999999\n"))
+	want := []transcript.Turn{
+		{Direction: transcript.Request, Line: 1, Verb: "GET", Args: []string{"a", "b"}, Text: "+ get a b"},
+		{Direction: transcript.Response, Line: 2, Status: 200, Text: "* 200 done"},
+	}
+	checkTurns(t, got, want)
+	if err == nil || !strings.Contains(err.Error(), "transcript: line 3") {
+		t.Errorf("Frame with out of range status: error %v", err)
+	}
+	if s := transcript.Response.String(); s != "response" {
+		t.Errorf("Response.String() = %q", s)
+	}
+}
+
+func checkTurns(t *testing.T, got, want []transcript.Turn) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d turns; expected %d:\n%+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("turn %d =\n%+v\nexpected\n%+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package re
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ScanTyped matches re against input and returns the sub-matches of all
+// named groups with each value parsed according to the type annotation
+// at the end of the group name; see GroupValue.  Values are keyed by
+// GroupKey, so for example the value of (?P<port_int>\d+) is stored as
+// an int64 under the key "port", while the value of a group without an
+// annotation is stored as a string under the full group name.  Named
+// groups that did not participate in the match are omitted from the
+// result.  It returns an error wrapping NotFound if re does not match
+// input.
+func ScanTyped(re *regexp.Regexp, input []byte) (map[string]interface{}, error) {
+	matches := re.FindSubmatchIndex(input)
+	if matches == nil {
+		return nil, fmt.Errorf("regular expression %q: %w", re, NotFound)
+	}
+	result := map[string]interface{}{}
+	for i, name := range re.SubexpNames() {
+		if i == 0 || name == "" {
+			continue
+		}
+		submatch, span := submatchAt(input, matches, i-1)
+		if span.Start < 0 {
+			continue
+		}
+		value, err := GroupValue(name, submatch)
+		if err != nil {
+			return nil, fmt.Errorf("re.ScanTyped: group %s: %w", name, err)
+		}
+		result[GroupKey(name)] = value
+	}
+	return result, nil
+}
+
+// ScanNamed matches re against input and returns the sub-matches of all
+// named groups, keyed by group name.  Named groups that did not
+// participate in the match are omitted from the result.  It returns an
+// error wrapping NotFound if re does not match input.
+func ScanNamed(re *regexp.Regexp, input []byte) (map[string]string, error) {
+	b, err := ScanNamedBytes(re, input)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(b))
+	for k, v := range b {
+		result[k] = string(v)
+	}
+	return result, nil
+}
+
+// ScanNamedBytes is like ScanNamed, but returns the sub-matches as byte
+// slices that alias input.
+func ScanNamedBytes(re *regexp.Regexp, input []byte) (map[string][]byte, error) {
+	matches := re.FindSubmatchIndex(input)
+	if matches == nil {
+		return nil, fmt.Errorf("regular expression %q: %w", re, NotFound)
+	}
+	result := map[string][]byte{}
+	for i, name := range re.SubexpNames() {
+		if i == 0 || name == "" {
+			continue
+		}
+		submatch, span := submatchAt(input, matches, i-1)
+		if _, ok := result[name]; ok || span.Start < 0 {
+			continue
+		}
+		result[name] = submatch
+	}
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package re_test
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+func TestScanTyped(t *testing.T) {
+	pattern := regexp.MustCompile(`^(?P<ts_time_rfc3339>\S+) (?P<host>\w+):(?P<port_int>\d+) ` +
+		`(?P<ok_bool>\w+) (?P<load_float>\S+) (?P<took_duration>\S+) (?P<user_id>\w+)(?: (?P<extra_uint>\d+))?$`)
+
+	got, err := re.ScanTyped(pattern, []byte("2020-01-02T03:04:05Z h:80 true 0.5 2s u1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]interface{}{
+		"ts":      time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		"host":    "h",
+		"port":    int64(80),
+		"ok":      true,
+		"load":    0.5,
+		"took":    2 * time.Second,
+		"user_id": "u1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanTyped result is %v; expected %v", got, want)
+	}
+
+	if _, err := re.ScanTyped(pattern, []byte("2020-01-02T03:04:05Z h:80 maybe 0.5 2s u1")); err == nil {
+		t.Errorf("ScanTyped of bad bool succeeded unexpectedly")
+	}
+	if _, err := re.ScanTyped(pattern, []byte("junk")); !errors.Is(err, re.NotFound) {
+		t.Errorf("ScanTyped error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+}
+
+func TestScanNamed(t *testing.T) {
+	pattern := regexp.MustCompile(`^(?P<host>\w+):(?P<port>\d+)(?: (?P<extra>\w+))?$`)
+	got, err := re.ScanNamed(pattern, []byte("h:80"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := map[string]string{"host": "h", "port": "80"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanNamed result is %q; expected %q", got, want)
+	}
+	if _, err := re.ScanNamed(pattern, []byte("junk")); !errors.Is(err, re.NotFound) {
+		t.Errorf("ScanNamed error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+
+	input := []byte("h:80")
+	b, err := re.ScanNamedBytes(pattern, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	input[0] = 'j'
+	if string(b["host"]) != "j" || len(b) != 2 {
+		t.Errorf("ScanNamedBytes result is %q; expected an alias of the input", b)
+	}
+}
@@ -0,0 +1,142 @@
+package re
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Unmarshal matches re against input and stores named sub-matches into
+// the fields of the struct pointed to by v.  It returns an error
+// wrapping NotFound if re does not match input.
+//
+// A sub-match captured by a named group such as (?P<host>...) is stored
+// into the field whose "re" struct tag holds the group name, e.g.
+//
+//	type Addr struct {
+//		Host string `re:"host"`
+//		Port int    `re:"port"`
+//	}
+//
+// A field without an "re" tag is matched against the group with the
+// same name, ignoring case.  A field tagged `re:"-"` is ignored, as are
+// unexported fields.  The sub-match is parsed following the same rules
+// Scan uses for a pointer to the field type; e.g., the sub-match for an
+// int field is parsed as a number.
+//
+// It is an error for a tag to name a group that does not exist in re.
+// Fields without a tag that do not correspond to a group, and named
+// groups that do not correspond to a field, are ignored.
+func Unmarshal(re *regexp.Regexp, input []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("re.Unmarshal: need a non-nil pointer to a struct; got %T", v)
+	}
+	fields, err := bindFields(re, rv.Elem().Type())
+	if err != nil {
+		return err
+	}
+	matches := re.FindSubmatchIndex(input)
+	if matches == nil {
+		return fmt.Errorf("regular expression %q: %w", re, NotFound)
+	}
+	return unmarshalMatch(input, matches, fields, rv.Elem())
+}
+
+// UnmarshalAll is like Unmarshal, but it processes every non-overlapping
+// match of re in input, appending one struct per match to the slice
+// pointed to by v.  The slice elements may be structs or pointers to
+// structs.  It returns the number of matches processed.
+//
+// If no match is found, an error wrapping NotFound is returned.  If a
+// sub-match cannot be parsed, UnmarshalAll stops and returns the number
+// of matches that were completely processed before the failure; the
+// slice gets exactly one element per completely processed match.
+func UnmarshalAll(re *regexp.Regexp, input []byte, v interface{}) (int, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return 0, fmt.Errorf("re.UnmarshalAll: need a non-nil pointer to a slice; got %T", v)
+	}
+	slice := rv.Elem()
+	et := slice.Type().Elem()
+	st, isPtr := et, false
+	if st.Kind() == reflect.Ptr {
+		st, isPtr = st.Elem(), true
+	}
+	if st.Kind() != reflect.Struct {
+		return 0, fmt.Errorf("re.UnmarshalAll: need a slice of structs or pointers to structs; got %T", v)
+	}
+	fields, err := bindFields(re, st)
+	if err != nil {
+		return 0, err
+	}
+	all := re.FindAllSubmatchIndex(input, -1)
+	if all == nil {
+		return 0, fmt.Errorf("regular expression %q: %w", re, NotFound)
+	}
+	for n, matches := range all {
+		e := reflect.New(st)
+		if err := unmarshalMatch(input, matches, fields, e.Elem()); err != nil {
+			return n, err
+		}
+		if !isPtr {
+			e = e.Elem()
+		}
+		slice.Set(reflect.Append(slice, e))
+	}
+	return len(all), nil
+}
+
+// fieldBinding connects a capture group to a struct field.
+type fieldBinding struct {
+	group int // Index of the sub-match (counting from zero)
+	field int // Index of the struct field
+}
+
+// bindFields returns the bindings between the named groups in re and
+// the fields of struct type t.
+func bindFields(re *regexp.Regexp, t reflect.Type) ([]fieldBinding, error) {
+	names := re.SubexpNames()
+	var fields []fieldBinding
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // Unexported
+		}
+		tag, tagged := f.Tag.Lookup("re")
+		if tag == "-" {
+			continue
+		}
+		group := -1
+		for j, name := range names {
+			if j == 0 || name == "" {
+				continue
+			}
+			if (tagged && name == tag) || (!tagged && strings.EqualFold(name, f.Name)) {
+				group = j - 1
+				break
+			}
+		}
+		if group < 0 {
+			if tagged {
+				return nil, fmt.Errorf(`re.Unmarshal: field %s: no group named "%s" in "%s"`, f.Name, tag, re)
+			}
+			continue
+		}
+		fields = append(fields, fieldBinding{group: group, field: i})
+	}
+	return fields, nil
+}
+
+// unmarshalMatch stores the sub-matches recorded in matches into the
+// fields of struct value sv as directed by fields.
+func unmarshalMatch(input []byte, matches []int, fields []fieldBinding, sv reflect.Value) error {
+	for _, f := range fields {
+		submatch, span := submatchAt(input, matches, f.group)
+		if err := assign(sv.Field(f.field).Addr().Interface(), submatch, span); err != nil {
+			return fmt.Errorf("re.Unmarshal: field %s: %w", sv.Type().Field(f.field).Name, err)
+		}
+	}
+	return nil
+}
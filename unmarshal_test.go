@@ -0,0 +1,85 @@
+package re_test
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestUnmarshal(t *testing.T) {
+	type addr struct {
+		Host    string `re:"host"`
+		Port    int    `re:"port"`
+		Scheme  string
+		Path    string `re:"-"`
+		Ignored bool
+		ignored string
+	}
+	pattern := regexp.MustCompile(`^(?P<scheme>\w+)://(?P<host>[^/:]+):(?P<port>\d+)(?P<path>/.*)?$`)
+
+	var a addr
+	if err := re.Unmarshal(pattern, []byte("http://example.com:8080/x"), &a); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := (addr{Host: "example.com", Port: 8080, Scheme: "http"}); a != want {
+		t.Errorf("Unmarshal result is %+v; expected %+v", a, want)
+	}
+
+	if err := re.Unmarshal(pattern, []byte("junk"), &a); !errors.Is(err, re.NotFound) {
+		t.Errorf("Unmarshal error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+	if err := re.Unmarshal(pattern, []byte("http://h:99999999999999999999999"), &a); err == nil {
+		t.Errorf("Unmarshal of out of range port succeeded unexpectedly")
+	}
+	if err := re.Unmarshal(pattern, []byte("http://h:80"), a); err == nil {
+		t.Errorf("Unmarshal into non-pointer succeeded unexpectedly")
+	}
+
+	var bad struct {
+		Host string `re:"hots"`
+	}
+	if err := re.Unmarshal(pattern, []byte("http://h:80"), &bad); err == nil {
+		t.Errorf("Unmarshal with unknown tag succeeded unexpectedly")
+	}
+}
+
+func TestUnmarshalAll(t *testing.T) {
+	type entry struct {
+		Level string `re:"level"`
+		Code  uint8  `re:"code"`
+	}
+	pattern := regexp.MustCompile(`(?P<level>[A-Z]+) (?P<code>\d+)`)
+	input := []byte("INFO 1; WARN 2; ERROR 300; INFO 4")
+
+	var entries []entry
+	n, err := re.UnmarshalAll(pattern, input, &entries)
+	if err == nil {
+		t.Fatalf("UnmarshalAll of out of range code succeeded unexpectedly")
+	}
+	want := []entry{{"INFO", 1}, {"WARN", 2}}
+	if n != 2 || !reflect.DeepEqual(entries, want) {
+		t.Errorf("UnmarshalAll = %d, %+v; expected 2, %+v", n, entries, want)
+	}
+
+	var ptrs []*entry
+	n, err = re.UnmarshalAll(pattern, []byte("INFO 1; INFO 4"), &ptrs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 2 || len(ptrs) != 2 || *ptrs[0] != (entry{"INFO", 1}) || *ptrs[1] != (entry{"INFO", 4}) {
+		t.Errorf("UnmarshalAll into pointers = %d, %v", n, ptrs)
+	}
+
+	if _, err := re.UnmarshalAll(pattern, []byte("nothing"), &entries); !errors.Is(err, re.NotFound) {
+		t.Errorf("UnmarshalAll error was %v, want an error that wraps %v", err, re.NotFound)
+	}
+	if _, err := re.UnmarshalAll(pattern, input, entries); err == nil {
+		t.Errorf("UnmarshalAll into non-pointer succeeded unexpectedly")
+	}
+	if _, err := re.UnmarshalAll(pattern, input, &[]string{}); err == nil {
+		t.Errorf("UnmarshalAll into slice of strings succeeded unexpectedly")
+	}
+}
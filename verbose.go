@@ -0,0 +1,106 @@
+package re
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CompileVerbose is like regexp.Compile, but pattern is written in
+// free-spacing form, like the /x mode of Perl: white space is ignored
+// and # starts a comment that runs to the end of the line, so that long
+// patterns can be laid out and annotated:
+//
+//	reg := re.MustCompileVerbose(`
+//		^(\S+)       # Host
+//		\s+ (\d{3})  # Status
+//		\s+ (\d+)$   # Bytes
+//	`)
+//
+// White space and # are taken literally inside character classes and
+// \Q...\E quoting, and when escaped with a backslash.
+func CompileVerbose(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(stripVerbose(pattern))
+}
+
+// MustCompileVerbose is like CompileVerbose but panics if pattern cannot
+// be compiled.  It simplifies safe initialization of global variables
+// holding compiled regular expressions.
+func MustCompileVerbose(pattern string) *regexp.Regexp {
+	reg, err := CompileVerbose(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return reg
+}
+
+// stripVerbose removes the white space and comments from a free-spacing
+// pattern.
+func stripVerbose(pattern string) string {
+	var b strings.Builder
+	inClass := false
+	for i := 0; i < len(pattern); {
+		c, size := utf8.DecodeRuneInString(pattern[i:])
+		switch {
+		case c == '\\' && strings.HasPrefix(pattern[i:], `\Q`):
+			// Copy quoted text, including the \E, verbatim.
+			end := strings.Index(pattern[i:], `\E`)
+			if end < 0 {
+				b.WriteString(pattern[i:])
+				return b.String()
+			}
+			b.WriteString(pattern[i : i+end+2])
+			i += end + 2
+			continue
+		case c == '\\' && i+1 < len(pattern):
+			e, esize := utf8.DecodeRuneInString(pattern[i+1:])
+			if unicode.IsSpace(e) {
+				// Go's syntax does not allow escaped white space.
+				fmt.Fprintf(&b, `\x{%x}`, e)
+			} else {
+				b.WriteString(pattern[i : i+1+esize])
+			}
+			i += 1 + esize
+			continue
+		case inClass:
+			if c == '[' && strings.HasPrefix(pattern[i:], "[:") {
+				// ASCII class such as [:alpha:].
+				if end := strings.Index(pattern[i:], ":]"); end >= 0 {
+					b.WriteString(pattern[i : i+end+2])
+					i += end + 2
+					continue
+				}
+			}
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+			b.WriteByte('[')
+			i++
+			// A ] at the start of a class is literal.
+			if strings.HasPrefix(pattern[i:], "^") {
+				b.WriteByte('^')
+				i++
+			}
+			if strings.HasPrefix(pattern[i:], "]") {
+				b.WriteByte(']')
+				i++
+			}
+			continue
+		case c == '#':
+			for i < len(pattern) && pattern[i] != '\n' {
+				i++
+			}
+			continue
+		case unicode.IsSpace(c):
+			i += size
+			continue
+		}
+		b.WriteString(pattern[i : i+size])
+		i += size
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package re_test
+
+import (
+	"testing"
+
+	"github.com/ghemawat/re"
+)
+
+func TestCompileVerbose(t *testing.T) {
+	for _, c := range []struct {
+		pattern, want string
+	}{
+		{"a b\tc\n d", "abcd"},
+		{"a # comment\n b # another", "ab"},
+		{`a\ b\#c\	d`, `a\x{20}b\#c\x{9}d`},
+		{"[ #] x", "[ #]x"},
+		{"[] #] x", "[] #]x"},
+		{"[^] ] x", "[^] ]x"},
+		{"[[:space:] #] x", "[[:space:] #]x"},
+		{`\Q a # b \E c`, `\Q a # b \Ec`},
+		{`\Q a # b`, `\Q a # b`},
+		{`(?P<host> \S+ ) \s+ (\d+)`, `(?P<host>\S+)\s+(\d+)`},
+	} {
+		r, err := re.CompileVerbose(c.pattern)
+		if err != nil {
+			t.Errorf("CompileVerbose(%q): unexpected error: %s", c.pattern, err)
+			continue
+		}
+		if got := r.String(); got != c.want {
+			t.Errorf("CompileVerbose(%q) = %q; expected %q", c.pattern, got, c.want)
+		}
+	}
+	if _, err := re.CompileVerbose("a ( # unbalanced"); err == nil {
+		t.Errorf("CompileVerbose of a malformed pattern succeeded")
+	}
+}
+
+func TestMustCompileVerbose(t *testing.T) {
+	reg := re.MustCompileVerbose(`
+		^(\S+)       # Host
+		\s+ (\d{3})  # Status
+		\s+ (\d+)$   # Bytes
+	`)
+	var host string
+	var status, bytes int
+	if err := re.Scan(reg, []byte("example.com 200 512"), &host, &status, &bytes); err != nil {
+		t.Fatal(err)
+	}
+	if host != "example.com" || status != 200 || bytes != 512 {
+		t.Errorf("got %q %d %d", host, status, bytes)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustCompileVerbose of a malformed pattern did not panic")
+		}
+	}()
+	re.MustCompileVerbose("(")
+}
@@ -0,0 +1,301 @@
+/*
+Package whois extracts fields from WHOIS responses.  Registries and
+registrars format their responses differently, but most use "key: value"
+lines; Parse maps the many spellings of common keys onto the fields of a
+Record:
+
+	r, err := whois.Parse(response)
+	if err != nil {
+		return err
+	}
+	if time.Until(r.Expires) < 30*24*time.Hour {
+		Warn(r.DomainName, r.Expires)
+	}
+
+Every field of the response, recognized or not, is also kept in
+Record.Fields, so that registrar-specific fields can be found with Get.
+*/
+package whois
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+// A Record holds the fields extracted from a WHOIS response.  Fields
+// missing from the response are left empty.
+type Record struct {
+	DomainName  string // Lower-cased
+	Registrar   string
+	WhoisServer string
+	Created     time.Time
+	Updated     time.Time
+	Expires     time.Time
+	NameServers []string // Lower-cased, without trailing dots
+	Status      []string // E.g., "clientTransferProhibited"
+	DNSSEC      string
+
+	// Fields holds every key/value pair of the response in order.
+	Fields []Field
+}
+
+// A Field is one key/value pair of a WHOIS response.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Get returns the value of the first field of r whose key matches key,
+// ignoring case, or "" if there is no such field.
+func (r *Record) Get(key string) string {
+	for _, f := range r.Fields {
+		if strings.EqualFold(f.Key, key) {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Aliases maps lower-cased keys used by registries and registrars to the
+// names of the Record fields they are stored in.  Callers may add entries
+// before calling Parse to support additional formats.
+var Aliases = map[string]string{
+	"domain":      "DomainName",
+	"domain name": "DomainName",
+	"domainname":  "DomainName",
+
+	"registrar":            "Registrar",
+	"registrar name":       "Registrar",
+	"sponsoring registrar": "Registrar",
+
+	"whois":                  "WhoisServer",
+	"whois server":           "WhoisServer",
+	"registrar whois server": "WhoisServer",
+
+	"created":                  "Created",
+	"created on":               "Created",
+	"creation date":            "Created",
+	"domain registration date": "Created",
+	"registered":               "Created",
+	"registered on":            "Created",
+	"registration time":        "Created",
+
+	"changed":       "Updated",
+	"last modified": "Updated",
+	"last updated":  "Updated",
+	"last-update":   "Updated",
+	"modified":      "Updated",
+	"updated":       "Updated",
+	"updated date":  "Updated",
+
+	"expiration date":                        "Expires",
+	"expires":                                "Expires",
+	"expires on":                             "Expires",
+	"expiry date":                            "Expires",
+	"paid-till":                              "Expires",
+	"registrar registration expiration date": "Expires",
+	"registry expiry date":                   "Expires",
+	"renewal date":                           "Expires",
+
+	"name server":  "NameServers",
+	"name servers": "NameServers",
+	"nameserver":   "NameServers",
+	"nameservers":  "NameServers",
+	"nserver":      "NameServers",
+
+	"domain status": "Status",
+	"state":         "Status",
+	"status":        "Status",
+
+	"dnssec": "DNSSEC",
+}
+
+// fieldPattern matches a "key: value" line.  The key may not contain a
+// colon, so that URLs in values are left intact.
+var fieldPattern = regexp.MustCompile(`^\s*([^:\s][^:]*?)\s*:\s*(.*?)\s*$`)
+
+// continuationPattern matches an indented line without a key, which some
+// registries (e.g., Nominet) use for the values of a key on a line of
+// its own.
+var continuationPattern = regexp.MustCompile(`^\s+(\S.*?)\s*$`)
+
+// Parse extracts the fields of a WHOIS response.  Comment lines (those
+// starting with "%" or "#") and everything from the customary ">>> Last
+// update of WHOIS database" line onwards are ignored.  When a field
+// occurs more than once, as in responses containing both registry and
+// registrar data, the first occurrence wins, except that name servers
+// and statuses are accumulated.  Dates that cannot be parsed with
+// ParseDate are left zero; the raw value remains available in Fields.
+//
+// An error is returned only if the response contains no fields at all,
+// which usually means the domain is not registered.
+func Parse(response []byte) (Record, error) {
+	var r Record
+	key := "" // Key of a preceding line without a value
+	for _, line := range bytes.Split(response, []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			key = ""
+			continue
+		}
+		if trimmed[0] == '%' || trimmed[0] == '#' {
+			continue
+		}
+		if bytes.HasPrefix(trimmed, []byte(">>>")) {
+			break
+		}
+		var k, v string
+		if key != "" && re.Scan(continuationPattern, line, &v) == nil &&
+			!strings.Contains(v, ": ") && !strings.HasSuffix(v, ":") {
+			k = key
+		} else if re.Scan(fieldPattern, line, &k, &v) != nil {
+			key = ""
+			continue
+		} else if v == "" {
+			key = k
+			continue
+		} else {
+			key = ""
+		}
+		r.Fields = append(r.Fields, Field{k, v})
+		r.set(k, v)
+	}
+	if len(r.Fields) == 0 {
+		return Record{}, errors.New("whois: no fields in response")
+	}
+	return r, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by calling Parse.
+func (r *Record) UnmarshalText(text []byte) error {
+	p, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*r = p
+	return nil
+}
+
+// set stores value into the field of r that key is an alias of.
+func (r *Record) set(key, value string) {
+	first := func(s *string) {
+		if *s == "" {
+			*s = value
+		}
+	}
+	date := func(t *time.Time) {
+		if t.IsZero() {
+			*t, _ = ParseDate(value)
+		}
+	}
+	word := strings.Fields(value)[0]
+	switch Aliases[strings.ToLower(key)] {
+	case "DomainName":
+		first(&r.DomainName)
+		r.DomainName = strings.ToLower(r.DomainName)
+	case "Registrar":
+		first(&r.Registrar)
+	case "WhoisServer":
+		first(&r.WhoisServer)
+	case "Created":
+		date(&r.Created)
+	case "Updated":
+		date(&r.Updated)
+	case "Expires":
+		date(&r.Expires)
+	case "NameServers":
+		// Some registries list addresses after the name.
+		r.NameServers = appendNew(r.NameServers, strings.ToLower(strings.TrimSuffix(word, ".")))
+	case "Status":
+		// ICANN requires a URL after the status code, while some
+		// registries list several comma-separated statuses.
+		if !strings.Contains(value, ",") {
+			r.Status = appendNew(r.Status, word)
+			break
+		}
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				r.Status = appendNew(r.Status, s)
+			}
+		}
+	case "DNSSEC":
+		first(&r.DNSSEC)
+	}
+}
+
+// appendNew appends s to list unless it is already present.
+func appendNew(list []string, s string) []string {
+	for _, x := range list {
+		if strings.EqualFold(x, s) {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// DateLayouts lists the layouts tried, in order, by ParseDate.  Layouts
+// without a time zone are interpreted as UTC.
+var DateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006.01.02 15:04:05",
+	"2006.01.02",
+	"2006/01/02",
+	"02-Jan-2006",
+	"02-January-2006",
+	"02.01.2006 15:04:05",
+	"02.01.2006",
+	"02/01/2006",
+	"January 2 2006",
+	"Jan 2 2006",
+	"2 January 2006",
+	"Mon Jan 2 15:04:05 MST 2006",
+	"Mon Jan 2 2006",
+}
+
+// ParseDate parses a WHOIS date by trying each of DateLayouts in turn.
+// Commas and a trailing parenthesized comment, e.g., "(YYYY-MM-DD)", are
+// ignored, as is the case of month names.
+func ParseDate(s string) (time.Time, error) {
+	v := strings.TrimSpace(s)
+	if i := strings.Index(v, " ("); i >= 0 && strings.HasSuffix(v, ")") {
+		v = v[:i]
+	}
+	v = strings.Join(strings.Fields(strings.Replace(v, ",", " ", -1)), " ")
+	v = monthPattern.ReplaceAllStringFunc(v, func(m string) string {
+		return strings.ToUpper(m[:1]) + strings.ToLower(m[1:])
+	})
+	for _, layout := range DateLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("whois: unrecognized date %q", s)
+}
+
+var monthPattern = regexp.MustCompile(`(?i)\b(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec|mon|tue|wed|thu|fri|sat|sun)[a-z]*\b`)
+
+// Date returns an output argument for re.Scan that parses a sub-match
+// with ParseDate and stores the result into *t.
+func Date(t *time.Time) func([]byte) error {
+	return func(b []byte) error {
+		v, err := ParseDate(string(b))
+		if err != nil {
+			return err
+		}
+		*t = v
+		return nil
+	}
+}
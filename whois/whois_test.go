@@ -0,0 +1,166 @@
+package whois_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+	"github.com/ghemawat/re/whois"
+)
+
+const verisign = `   Domain Name: EXAMPLE.COM
+   Registry Domain ID: 2336799_DOMAIN_COM-VRSN
+   Registrar WHOIS Server: whois.iana.org
+   Registrar URL: http://res-dom.iana.org
+   Updated Date: 2024-08-14T07:01:34Z
+   Creation Date: 1995-08-14T04:00:00Z
+   Registry Expiry Date: 2025-08-13T04:00:00Z
+   Registrar: RESERVED-Internet Assigned Numbers Authority
+   Domain Status: clientDeleteProhibited https://icann.org/epp#clientDeleteProhibited
+   Domain Status: clientTransferProhibited https://icann.org/epp#clientTransferProhibited
+   Name Server: A.IANA-SERVERS.NET
+   Name Server: B.IANA-SERVERS.NET
+   DNSSEC: signedDelegation
+   URL of the ICANN Whois Inaccuracy Complaint Form: https://www.icann.org/wicf/
+>>> Last update of whois database: 2024-09-01T00:00:00Z <<<
+
+NOTICE: The expiration date displayed in this record is the date the
+`
+
+const nominet = `
+    Domain name:
+        example.co.uk
+
+    Registrar:
+        Example Registrar Ltd [Tag = EXAMPLE]
+
+    Relevant dates:
+        Registered on: 26-Aug-1996
+        Expiry date:  26-AUG-2026
+        Last updated:  10-Jul-2024
+
+    Name servers:
+        ns1.example.net.      192.0.2.1
+        ns2.example.net.
+
+    WHOIS lookup made at 10:00:00 01-Sep-2024
+`
+
+const ripn = `% TCI Whois Service.
+
+domain:        EXAMPLE.RU
+nserver:       ns1.example.ru.
+nserver:       ns1.example.ru.
+state:         REGISTERED, DELEGATED, VERIFIED
+registrar:     RU-CENTER-RU
+created:       2001.01.01
+paid-till:     2025.01.01
+`
+
+func TestParse(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	for _, c := range []struct {
+		name, input string
+		want        whois.Record
+	}{
+		{"verisign", verisign, whois.Record{
+			DomainName:  "example.com",
+			Registrar:   "RESERVED-Internet Assigned Numbers Authority",
+			WhoisServer: "whois.iana.org",
+			Created:     time.Date(1995, 8, 14, 4, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2024, 8, 14, 7, 1, 34, 0, time.UTC),
+			Expires:     time.Date(2025, 8, 13, 4, 0, 0, 0, time.UTC),
+			NameServers: []string{"a.iana-servers.net", "b.iana-servers.net"},
+			Status:      []string{"clientDeleteProhibited", "clientTransferProhibited"},
+			DNSSEC:      "signedDelegation",
+		}},
+		{"nominet", nominet, whois.Record{
+			DomainName:  "example.co.uk",
+			Registrar:   "Example Registrar Ltd [Tag = EXAMPLE]",
+			Created:     day(1996, 8, 26),
+			Updated:     day(2024, 7, 10),
+			Expires:     day(2026, 8, 26),
+			NameServers: []string{"ns1.example.net", "ns2.example.net"},
+		}},
+		{"ripn", ripn, whois.Record{
+			DomainName:  "example.ru",
+			Registrar:   "RU-CENTER-RU",
+			Created:     day(2001, 1, 1),
+			Expires:     day(2025, 1, 1),
+			NameServers: []string{"ns1.example.ru"},
+			Status:      []string{"REGISTERED", "DELEGATED", "VERIFIED"},
+		}},
+	} {
+		got, err := whois.Parse([]byte(c.input))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.name, err)
+			continue
+		}
+		if len(got.Fields) == 0 {
+			t.Errorf("%s: no fields", c.name)
+		}
+		got.Fields = nil
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got\n%+v\nexpected\n%+v", c.name, got, c.want)
+		}
+	}
+
+	if _, err := whois.Parse([]byte("No match for \"NONEXISTENT.COM\".\n")); err == nil {
+		t.Errorf("Parse of a response without fields succeeded")
+	}
+}
+
+func TestGet(t *testing.T) {
+	r, err := whois.Parse([]byte(verisign))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Get("registrar url"); got != "http://res-dom.iana.org" {
+		t.Errorf("Get(registrar url) = %q", got)
+	}
+	if got := r.Get("missing"); got != "" {
+		t.Errorf("Get(missing) = %q", got)
+	}
+}
+
+func TestAliases(t *testing.T) {
+	whois.Aliases["holder-c"] = "Registrar"
+	defer delete(whois.Aliases, "holder-c")
+	r, err := whois.Parse([]byte("holder-c: EXAMPLE-1\n"))
+	if err != nil || r.Registrar != "EXAMPLE-1" {
+		t.Errorf("Parse with custom alias = %+v, %v", r, err)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	for s, want := range map[string]time.Time{
+		"2024-01-15T10:00:00Z":         time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		"2024-01-15T10:00:00.5+01:00":  time.Date(2024, 1, 15, 9, 0, 0, 500000000, time.UTC),
+		"2024-01-15 10:00:00":          time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		"2024-01-15 10:00:00 UTC":      time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		"2024-01-15 (YYYY-MM-DD)":      time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		"15-JAN-2024":                  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		"15.01.2024":                   time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		"January 15, 2024":             time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		"Mon Jan 15 10:00:00 UTC 2024": time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		"  2024/01/15  ":               time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+	} {
+		got, err := whois.ParseDate(s)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseDate(%q) = %v, %v; expected %v", s, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "before Aug-1996", "2024-13-01"} {
+		if got, err := whois.ParseDate(bad); err == nil {
+			t.Errorf("ParseDate(%q) = %v; expected an error", bad, got)
+		}
+	}
+
+	var created time.Time
+	err := re.Scan(regexp.MustCompile(`created (.*)`), []byte("created 2024.01.15"), whois.Date(&created))
+	if err != nil || !created.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Date = %v, %v", created, err)
+	}
+}
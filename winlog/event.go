@@ -0,0 +1,129 @@
+package winlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+// An Event is one event log entry.
+type Event struct {
+	LogName     string
+	Source      string
+	Time        time.Time // From the "Date" field
+	EventID     int
+	Level       string // e.g., "Information" or "Error"
+	Computer    string
+	Description string // Possibly several lines
+
+	// Fields holds every "Name: value" field of the entry, including the
+	// ones stored above (but not the Description), keyed by name.
+	Fields map[string]string
+}
+
+// Scan matches pattern against the description of e and stores the
+// sub-matches into output, as re.Scan does.
+func (e Event) Scan(pattern *regexp.Regexp, output ...interface{}) error {
+	return re.Scan(pattern, []byte(e.Description), output...)
+}
+
+// An EventReader reads the output of "wevtutil qe <log> /f:text", in
+// which each event starts with an "Event[N]:" line followed by indented
+// "Name: value" lines, the last of which is the Description.  The
+// description runs until the next event.
+type EventReader struct {
+	s       *bufio.Scanner
+	pending bool // s holds an "Event[N]:" line that has not been processed
+}
+
+// NewEventReader returns an EventReader that reads from r.
+func NewEventReader(r io.Reader) *EventReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	return &EventReader{s: s}
+}
+
+var (
+	eventStart = regexp.MustCompile(`^Event\[\d+\]:\s*$`)
+	eventField = regexp.MustCompile(`^\s+([A-Za-z][A-Za-z ]*?):\s?(.*)$`)
+)
+
+// eventLayouts lists the layouts seen in the Date field.
+var eventLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02T15:04:05"}
+
+// Next returns the next event.  It returns io.EOF at the end of the
+// input, and an error if the input does not start with an event or a
+// field cannot be parsed.
+func (r *EventReader) Next() (Event, error) {
+	if !r.pending {
+		for {
+			if !r.s.Scan() {
+				if err := r.s.Err(); err != nil {
+					return Event{}, err
+				}
+				return Event{}, io.EOF
+			}
+			line := strings.TrimRight(r.s.Text(), "\r")
+			if eventStart.MatchString(line) {
+				break
+			}
+			if strings.TrimSpace(line) != "" {
+				return Event{}, fmt.Errorf("winlog: expected an Event line; got %q", line)
+			}
+		}
+	}
+	r.pending = false
+	e := Event{Fields: map[string]string{}}
+	var desc []string
+	inDesc := false
+	for r.s.Scan() {
+		line := strings.TrimRight(r.s.Text(), "\r")
+		if eventStart.MatchString(line) {
+			r.pending = true
+			break
+		}
+		if inDesc {
+			desc = append(desc, line)
+			continue
+		}
+		var name, value string
+		if re.Scan(eventField, []byte(line), &name, &value) != nil {
+			continue
+		}
+		if name == "Description" {
+			inDesc = true
+			if value = strings.TrimSpace(value); value != "" {
+				desc = append(desc, value)
+			}
+			continue
+		}
+		e.Fields[name] = value
+	}
+	if err := r.s.Err(); err != nil {
+		return Event{}, err
+	}
+	e.Description = strings.TrimSpace(strings.Join(desc, "\n"))
+	e.LogName = e.Fields["Log Name"]
+	e.Source = e.Fields["Source"]
+	e.Level = e.Fields["Level"]
+	e.Computer = e.Fields["Computer"]
+	if v, ok := e.Fields["Event ID"]; ok {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return Event{}, fmt.Errorf("winlog: bad Event ID %q", v)
+		}
+		e.EventID = id
+	}
+	if v, ok := e.Fields["Date"]; ok {
+		if err := re.Time(&e.Time, eventLayouts...)([]byte(v)); err != nil {
+			return Event{}, fmt.Errorf("winlog: bad Date %q", v)
+		}
+	}
+	return e, nil
+}
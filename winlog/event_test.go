@@ -0,0 +1,84 @@
+package winlog_test
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re/winlog"
+)
+
+const events = "Event[0]:\r\n" +
+	"  Log Name: Security\r\n" +
+	"  Source: Microsoft-Windows-Security-Auditing\r\n" +
+	"  Date: 2024-01-15T10:00:00.1230000Z\r\n" +
+	"  Event ID: 4625\r\n" +
+	"  Task: Logon\r\n" +
+	"  Level: Information\r\n" +
+	"  Keyword: Audit Failure\r\n" +
+	"  User: N/A\r\n" +
+	"  Computer: DC01.corp.example.com\r\n" +
+	"  Description: \r\n" +
+	"An account failed to log on.\r\n" +
+	"\r\n" +
+	"Account For Which Logon Failed:\r\n" +
+	"\tAccount Name:\t\tmallory\r\n" +
+	"\tAccount Domain:\t\tCORP\r\n" +
+	"\r\n" +
+	"Event[1]:\n" +
+	"  Log Name: System\n" +
+	"  Date: 2024-01-15T11:00:00\n" +
+	"  Event ID: 7036\n" +
+	"  Description: The Windows Update service entered the running state.\n"
+
+func TestEventReader(t *testing.T) {
+	r := winlog.NewEventReader(strings.NewReader(events))
+	e, err := r.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if e.LogName != "Security" || e.EventID != 4625 || e.Level != "Information" || e.Computer != "DC01.corp.example.com" {
+		t.Errorf("first event = %+v", e)
+	}
+	if !e.Time.Equal(time.Date(2024, 1, 15, 10, 0, 0, 123000000, time.UTC)) {
+		t.Errorf("first event time = %v", e.Time)
+	}
+	if e.Fields["Keyword"] != "Audit Failure" {
+		t.Errorf("Fields = %v", e.Fields)
+	}
+	if !strings.HasPrefix(e.Description, "An account failed to log on.\n") {
+		t.Errorf("Description = %q", e.Description)
+	}
+	var account string
+	if err := e.Scan(regexp.MustCompile(`Account Name:\s+(\S+)`), &account); err != nil || account != "mallory" {
+		t.Errorf("Scan = %q, %v", account, err)
+	}
+
+	e, err = r.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if e.EventID != 7036 || e.Description != "The Windows Update service entered the running state." {
+		t.Errorf("second event = %+v", e)
+	}
+	if e.Time != time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC) {
+		t.Errorf("second event time = %v", e.Time)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next at end returned %v; expected io.EOF", err)
+	}
+}
+
+func TestEventReaderErrors(t *testing.T) {
+	for _, input := range []string{
+		"garbage\n",
+		"Event[0]:\n  Event ID: many\n",
+		"Event[0]:\n  Date: yesterday\n",
+	} {
+		if e, err := winlog.NewEventReader(strings.NewReader(input)).Next(); err == nil {
+			t.Errorf("Next(%q) = %+v; expected an error", input, e)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package winlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A W3CEntry maps the field names of a W3C extended log line, such as
+// "cs-method" or "sc-status", to their values.  Fields logged as "-"
+// (meaning no value) are omitted.
+type W3CEntry map[string]string
+
+// A W3CReader reads W3C extended log lines.  Directive lines, which
+// start with "#", are consumed; #Fields directives set the names of the
+// columns of the lines that follow them.
+type W3CReader struct {
+	r      *bufio.Reader
+	fields []string
+	line   int
+}
+
+// NewW3CReader returns a W3CReader that reads from r.
+func NewW3CReader(r io.Reader) *W3CReader {
+	return &W3CReader{r: bufio.NewReader(r)}
+}
+
+// Fields returns the field names set by the last #Fields directive.
+func (r *W3CReader) Fields() []string {
+	return r.fields
+}
+
+// Next returns the next log entry.  It returns io.EOF at the end of the
+// input, and an error for a line whose number of columns differs from
+// the number of fields, or that precedes any #Fields directive.
+func (r *W3CReader) Next() (W3CEntry, error) {
+	for {
+		line, err := r.r.ReadString('\n')
+		if line == "" && err != nil {
+			return nil, err
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		r.line++
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "#") {
+			if strings.HasPrefix(line, "#Fields:") {
+				r.fields = strings.Fields(line[len("#Fields:"):])
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if r.fields == nil {
+			return nil, fmt.Errorf("winlog: line %d: no #Fields directive", r.line)
+		}
+		cols := strings.Fields(line)
+		if len(cols) != len(r.fields) {
+			return nil, fmt.Errorf("winlog: line %d has %d columns; expected %d", r.line, len(cols), len(r.fields))
+		}
+		e := W3CEntry{}
+		for i, c := range cols {
+			if c != "-" {
+				e[r.fields[i]] = c
+			}
+		}
+		return e, nil
+	}
+}
+
+// An IISEntry holds the usual fields of an IIS log entry.  Fields that
+// were not logged have their zero value.
+type IISEntry struct {
+	Time        time.Time     // From "date" and "time", in UTC
+	ServerIP    string        // s-ip
+	Method      string        // cs-method
+	URIStem     string        // cs-uri-stem
+	URIQuery    string        // cs-uri-query
+	Port        int           // s-port
+	Username    string        // cs-username
+	ClientIP    string        // c-ip
+	UserAgent   string        // cs(User-Agent), with "+" decoded to space
+	Referer     string        // cs(Referer)
+	Status      int           // sc-status
+	SubStatus   int           // sc-substatus
+	Win32Status int           // sc-win32-status
+	TimeTaken   time.Duration // time-taken, logged in milliseconds
+}
+
+// IIS converts e to an IISEntry.  An error is returned if a numeric
+// field or the timestamp cannot be parsed.
+func (e W3CEntry) IIS() (IISEntry, error) {
+	x := IISEntry{
+		ServerIP: e["s-ip"],
+		Method:   e["cs-method"],
+		URIStem:  e["cs-uri-stem"],
+		URIQuery: e["cs-uri-query"],
+		Username: e["cs-username"],
+		ClientIP: e["c-ip"],
+		Referer:  e["cs(Referer)"],
+	}
+	if ua, ok := e["cs(User-Agent)"]; ok {
+		x.UserAgent = strings.Replace(ua, "+", " ", -1)
+	}
+	if d, ok := e["date"]; ok {
+		t, err := time.Parse("2006-01-02 15:04:05", d+" "+e["time"])
+		if err != nil {
+			return IISEntry{}, fmt.Errorf("winlog: bad timestamp: %w", err)
+		}
+		x.Time = t
+	}
+	for _, f := range []struct {
+		name string
+		dst  *int
+	}{
+		{"s-port", &x.Port},
+		{"sc-status", &x.Status},
+		{"sc-substatus", &x.SubStatus},
+		{"sc-win32-status", &x.Win32Status},
+	} {
+		if v, ok := e[f.name]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return IISEntry{}, fmt.Errorf("winlog: field %s: %w", f.name, err)
+			}
+			*f.dst = n
+		}
+	}
+	if v, ok := e["time-taken"]; ok {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return IISEntry{}, fmt.Errorf("winlog: field time-taken: %w", err)
+		}
+		x.TimeTaken = time.Duration(ms) * time.Millisecond
+	}
+	return x, nil
+}
+
+// Query parses the cs-uri-query field of e.
+func (e W3CEntry) Query() (url.Values, error) {
+	q, err := url.ParseQuery(e["cs-uri-query"])
+	if err != nil {
+		return nil, fmt.Errorf("winlog: bad cs-uri-query: %w", err)
+	}
+	return q, nil
+}
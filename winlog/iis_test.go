@@ -0,0 +1,91 @@
+package winlog_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re/winlog"
+)
+
+const iisLog = `#Software: Microsoft Internet Information Services 10.0
+#Version: 1.0
+#Date: 2024-01-15 10:00:00
+#Fields: date time s-ip cs-method cs-uri-stem cs-uri-query s-port cs-username c-ip cs(User-Agent) cs(Referer) sc-status sc-substatus sc-win32-status time-taken
+2024-01-15 10:00:01 10.0.0.5 GET /index.html a=1&b=two 443 - 203.0.113.9 Mozilla/5.0+(Windows+NT+10.0) - 200 0 0 15
+2024-01-15 10:00:02 10.0.0.5 POST /api - 443 CORP\bob 203.0.113.9 curl/8.0 https://example.com/ 500 19 5 1200
+
+#Fields: date time c-ip sc-status
+2024-01-15 10:00:03 198.51.100.1 404
+`
+
+func TestW3CReader(t *testing.T) {
+	r := winlog.NewW3CReader(strings.NewReader(iisLog))
+	var entries []winlog.IISEntry
+	var raw []winlog.W3CEntry
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		x, err := e.IIS()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		raw = append(raw, e)
+		entries = append(entries, x)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries; expected 3", len(entries))
+	}
+	want := winlog.IISEntry{
+		Time:      time.Date(2024, 1, 15, 10, 0, 1, 0, time.UTC),
+		ServerIP:  "10.0.0.5",
+		Method:    "GET",
+		URIStem:   "/index.html",
+		URIQuery:  "a=1&b=two",
+		Port:      443,
+		ClientIP:  "203.0.113.9",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0)",
+		Status:    200,
+		TimeTaken: 15 * time.Millisecond,
+	}
+	if entries[0] != want {
+		t.Errorf("first entry = %+v; expected %+v", entries[0], want)
+	}
+	if q, err := raw[0].Query(); err != nil || q.Get("b") != "two" {
+		t.Errorf("Query() = %v, %v", q, err)
+	}
+	if e := entries[1]; e.Username != `CORP\bob` || e.URIQuery != "" || e.SubStatus != 19 || e.Win32Status != 5 || e.TimeTaken != 1200*time.Millisecond || e.Referer != "https://example.com/" {
+		t.Errorf("second entry = %+v", e)
+	}
+	if e := entries[2]; e.ClientIP != "198.51.100.1" || e.Status != 404 || e.Method != "" {
+		t.Errorf("third entry = %+v", e)
+	}
+	if got := strings.Join(r.Fields(), " "); got != "date time c-ip sc-status" {
+		t.Errorf("Fields() = %s", got)
+	}
+}
+
+func TestW3CReaderErrors(t *testing.T) {
+	for _, input := range []string{
+		"2024-01-15 10:00:00 GET\n",
+		"#Fields: date time cs-method\n2024-01-15 10:00:00\n",
+	} {
+		if e, err := winlog.NewW3CReader(strings.NewReader(input)).Next(); err == nil {
+			t.Errorf("Next(%q) = %v; expected an error", input, e)
+		}
+	}
+	for _, e := range []winlog.W3CEntry{
+		{"date": "2024-13-01", "time": "00:00:00"},
+		{"sc-status": "OK"},
+		{"time-taken": "fast"},
+	} {
+		if x, err := e.IIS(); err == nil {
+			t.Errorf("IIS(%v) = %+v; expected an error", e, x)
+		}
+	}
+}
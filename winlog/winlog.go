@@ -0,0 +1,27 @@
+/*
+Package winlog reads logs written on Windows: web server logs in the
+W3C extended format used by IIS, and event log entries rendered as text
+by "wevtutil qe <log> /f:text".
+
+W3CReader follows the #Fields directives that define the columns of a
+W3C log, and IISEntry gives typed access to the usual IIS columns:
+
+	r := winlog.NewW3CReader(file)
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		entry, err := e.IIS()
+		...
+	}
+
+EventReader splits wevtutil output into Events, whose Description can be
+searched with a pattern:
+
+	var account string
+	err := event.Scan(regexp.MustCompile(`Account Name:\s+(\S+)`), &account)
+*/
+package winlog
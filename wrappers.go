@@ -0,0 +1,448 @@
+package re
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Time returns an output argument for Scan that parses the sub-match
+// with time.Parse and stores the result into *t.  The layouts are tried
+// in order and the first successful parse wins; if none succeeds, the
+// error from the last layout is returned.  If no layout is supplied,
+// time.RFC3339 is used.
+//
+//	var ts time.Time
+//	err := re.Scan(reg, line, re.Time(&ts, time.RFC3339, time.Stamp))
+func Time(t *time.Time, layout ...string) func([]byte) error {
+	if len(layout) == 0 {
+		layout = []string{time.RFC3339}
+	}
+	return func(b []byte) error {
+		var err error
+		for _, l := range layout {
+			var x time.Time
+			if x, err = time.Parse(l, string(b)); err == nil {
+				*t = x
+				return nil
+			}
+		}
+		return err
+	}
+}
+
+// OrZero returns an output argument for Scan that stores the zero value
+// into the variable pointed to by output if the sub-match is empty,
+// including when its group did not participate in the match, and
+// otherwise parses the sub-match into output as Scan would.  This
+// allows optional numeric fields without custom parsing functions:
+//
+//	var user string
+//	var uid int
+//	reg := regexp.MustCompile(`user=(\w+)(?: uid=(\d+))?`)
+//	err := re.Scan(reg, line, &user, re.OrZero(&uid))
+//
+// output must be a pointer of a type supported by Scan.
+func OrZero(output interface{}) func([]byte) error {
+	return func(b []byte) error {
+		if len(b) > 0 {
+			return assign(output, b, Span{})
+		}
+		v := reflect.ValueOf(output)
+		if v.Kind() != reflect.Ptr || v.IsNil() {
+			return fmt.Errorf("re.OrZero: output has type %T; need a non-nil pointer", output)
+		}
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+		return nil
+	}
+}
+
+// OneOf returns an output argument for Scan that stores the sub-match
+// into *s if it equals one of values, and returns an error otherwise:
+//
+//	var method string
+//	err := re.Scan(reg, line, re.OneOf(&method, "GET", "POST", "PUT"))
+//
+// See Enum for translating keywords into typed constants.
+func OneOf(s *string, values ...string) func([]byte) error {
+	return func(b []byte) error {
+		for _, v := range values {
+			if string(b) == v {
+				*s = v
+				return nil
+			}
+		}
+		return parseError("not one of "+strings.Join(values, ", "), b)
+	}
+}
+
+// Trimmed returns an output argument for Scan that removes leading and
+// trailing white space from the sub-match and stores the result into
+// output as Scan would.  It suits space-padded columns:
+//
+//	var size int
+//	var name string
+//	reg := regexp.MustCompile(`^(.{8}) (.*)$`)
+//	err := re.Scan(reg, line, re.Trimmed(&size), re.Trimmed(&name))
+//
+// output may itself be a func([]byte) error, so that text transformations
+// compose, e.g., re.Lowered(re.Trimmed(&s)).
+func Trimmed(output interface{}) func([]byte) error {
+	return transformed(output, bytes.TrimSpace)
+}
+
+// Lowered is like Trimmed, but maps the sub-match to lower case.
+func Lowered(output interface{}) func([]byte) error {
+	return transformed(output, bytes.ToLower)
+}
+
+// Uppered is like Trimmed, but maps the sub-match to upper case.
+func Uppered(output interface{}) func([]byte) error {
+	return transformed(output, bytes.ToUpper)
+}
+
+// transformed returns an output argument that applies f to the sub-match
+// before storing it into output.  A nil sub-match is passed through with
+// a span marking it absent, so that outputs such as OrZero, **T and
+// sql.NullString still see non-participating groups.
+func transformed(output interface{}, f func([]byte) []byte) func([]byte) error {
+	return func(b []byte) error {
+		span := wrappedSpan(b)
+		if b != nil {
+			b = f(b)
+		}
+		return assign(output, b, span)
+	}
+}
+
+// Unquoted returns an output argument for Scan that unquotes the
+// sub-match and stores the result into output as Scan would.  The
+// sub-match may be a double-quoted Go or JSON string, a back-quoted Go
+// raw string, or a single-quoted Go character literal:
+//
+//	var msg string
+//	reg := regexp.MustCompile(`msg=("(?:[^"\\]|\\.)*")`)
+//	err := re.Scan(reg, line, re.Unquoted(&msg))
+//
+// An error is returned if the sub-match is not validly quoted.  A group
+// that did not participate in the match is passed on as absent.
+func Unquoted(output interface{}) func([]byte) error {
+	return func(b []byte) error {
+		if b == nil {
+			return assign(output, nil, wrappedSpan(nil))
+		}
+		s, err := strconv.Unquote(string(b))
+		if err != nil {
+			// JSON also allows \/, which Go does not.
+			var ok bool
+			if s, ok = jsonUnquote(b); !ok {
+				return parseError("invalid quoted string", b)
+			}
+		}
+		return assign(output, []byte(s), wrappedSpan(b))
+	}
+}
+
+// HexBytes returns an output argument for Scan that hex-decodes the
+// sub-match into *b.  An optional "0x" prefix is skipped, and white space
+// and colons are ignored, so hashes, MAC-style "de:ad:be:ef" strings and
+// packet dumps such as "4500 0054" are all accepted:
+//
+//	var sum []byte
+//	reg := regexp.MustCompile(`^([0-9a-f]{64})  `)
+//	err := re.Scan(reg, sha256sumLine, re.HexBytes(&sum))
+func HexBytes(b *[]byte) func([]byte) error {
+	return func(text []byte) error {
+		t := bytes.TrimPrefix(bytes.TrimPrefix(text, []byte("0x")), []byte("0X"))
+		t = bytes.Map(func(r rune) rune {
+			if r == ':' || unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, t)
+		v := make([]byte, hex.DecodedLen(len(t)))
+		if _, err := hex.Decode(v, t); err != nil {
+			return parseError(err.Error(), text)
+		}
+		*b = v
+		return nil
+	}
+}
+
+// Base64 returns an output argument for Scan that decodes the sub-match
+// with enc and stores the result into *b.  If enc is nil,
+// base64.StdEncoding is used:
+//
+//	var payload []byte
+//	reg := regexp.MustCompile(`token=([\w-]+)`)
+//	err := re.Scan(reg, line, re.Base64(&payload, base64.RawURLEncoding))
+func Base64(b *[]byte, enc *base64.Encoding) func([]byte) error {
+	if enc == nil {
+		enc = base64.StdEncoding
+	}
+	return func(text []byte) error {
+		v := make([]byte, enc.DecodedLen(len(text)))
+		n, err := enc.Decode(v, text)
+		if err != nil {
+			return parseError(err.Error(), text)
+		}
+		*b = v[:n]
+		return nil
+	}
+}
+
+// URLDecoded returns an output argument for Scan that decodes the
+// percent-encoded sub-match with url.QueryUnescape, which also maps "+"
+// to a space, and stores the result into output as Scan would:
+//
+//	var q string
+//	reg := regexp.MustCompile(`[?&]q=([^&\s]*)`)
+//	err := re.Scan(reg, accessLogLine, re.URLDecoded(&q))
+//
+// Use PathDecoded for URL paths, in which "+" stands for itself.
+func URLDecoded(output interface{}) func([]byte) error {
+	return unescaped(output, url.QueryUnescape)
+}
+
+// PathDecoded is like URLDecoded, but uses url.PathUnescape.
+func PathDecoded(output interface{}) func([]byte) error {
+	return unescaped(output, url.PathUnescape)
+}
+
+// unescaped returns an output argument that decodes the sub-match with
+// unescape before storing it into output.  A nil sub-match is passed on
+// as absent.
+func unescaped(output interface{}, unescape func(string) (string, error)) func([]byte) error {
+	return func(b []byte) error {
+		if b == nil {
+			return assign(output, nil, wrappedSpan(nil))
+		}
+		s, err := unescape(string(b))
+		if err != nil {
+			return parseError(err.Error(), b)
+		}
+		return assign(output, []byte(s), wrappedSpan(b))
+	}
+}
+
+// List returns an output argument for Scan that splits the sub-match at
+// each occurrence of sep, trims white space around the pieces, and
+// parses each piece as Scan would parse a sub-match into an element of
+// the slice pointed to by output.  It makes up for Go regular
+// expressions capturing only the last repetition of a group:
+//
+//	var ports []int
+//	reg := regexp.MustCompile(`ports=([\d,]*)`)
+//	err := re.Scan(reg, line, re.List(&ports, ","))
+//
+// An empty sub-match yields an empty slice.  output must be a pointer to
+// a slice whose element type is supported by Scan; on error, the slice
+// is left unchanged.
+func List(output interface{}, sep string) func([]byte) error {
+	return func(b []byte) error {
+		v := reflect.ValueOf(output)
+		if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+			return fmt.Errorf("re.List: output has type %T; need a non-nil pointer to a slice", output)
+		}
+		list := reflect.MakeSlice(v.Elem().Type(), 0, 0)
+		if len(b) > 0 {
+			for _, piece := range bytes.Split(b, []byte(sep)) {
+				elem := reflect.New(list.Type().Elem())
+				if err := assign(elem.Interface(), bytes.TrimSpace(piece), Span{}); err != nil {
+					return err
+				}
+				list = reflect.Append(list, elem.Elem())
+			}
+		}
+		v.Elem().Set(list)
+		return nil
+	}
+}
+
+// KV returns an output argument for Scan that splits the sub-match into
+// items at each occurrence of itemSep, splits each item at the first
+// occurrence of kvSep, and stores the pairs into the map pointed to by
+// output.  Keys and values are trimmed of white space, and values are
+// parsed as Scan would parse a sub-match into the map's element type:
+//
+//	var attrs map[string]string
+//	reg := regexp.MustCompile(`^\S+ \S+ (.*)$`)
+//	err := re.Scan(reg, line, re.KV(&attrs, " ", "="))
+//
+// If itemSep is " ", items are separated by runs of white space.  Empty
+// items are skipped, and an item without kvSep is an error.  output must
+// be a pointer to a map with string keys; the map is replaced, and on
+// error, left unchanged.
+func KV(output interface{}, itemSep, kvSep string) func([]byte) error {
+	return func(b []byte) error {
+		v := reflect.ValueOf(output)
+		if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Map ||
+			v.Elem().Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("re.KV: output has type %T; need a non-nil pointer to a map with string keys", output)
+		}
+		var items [][]byte
+		if itemSep == " " {
+			items = bytes.Fields(b)
+		} else {
+			items = bytes.Split(b, []byte(itemSep))
+		}
+		t := v.Elem().Type()
+		m := reflect.MakeMap(t)
+		for _, item := range items {
+			if len(bytes.TrimSpace(item)) == 0 {
+				continue
+			}
+			i := bytes.Index(item, []byte(kvSep))
+			if i < 0 {
+				return parseError(fmt.Sprintf("item %q has no %q", item, kvSep), b)
+			}
+			key := reflect.New(t.Key()).Elem()
+			key.SetString(string(bytes.TrimSpace(item[:i])))
+			val := reflect.New(t.Elem())
+			if err := assign(val.Interface(), bytes.TrimSpace(item[i+len(kvSep):]), Span{}); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, val.Elem())
+		}
+		v.Elem().Set(m)
+		return nil
+	}
+}
+
+// Dec returns an output argument for Scan that parses the sub-match as a
+// decimal integer and stores it into output, which must point to a
+// signed or unsigned integer type.  Unlike Scan, which follows Go syntax
+// and so parses "010" as octal 8, Dec parses it as 10, which suits
+// identifiers and dates with leading zeros:
+//
+//	var month, day int
+//	reg := regexp.MustCompile(`^\d{4}-(\d\d)-(\d\d)`)
+//	err := re.Scan(reg, line, re.Dec(&month), re.Dec(&day))
+func Dec(output interface{}) func([]byte) error {
+	return intBase("re.Dec", output, 10, "")
+}
+
+// Hex is like Dec, but parses the sub-match as a hexadecimal integer,
+// e.g., "deadbeef".  A "0x" or "0X" prefix after the optional sign is
+// accepted but not required.
+func Hex(output interface{}) func([]byte) error {
+	return intBase("re.Hex", output, 16, "x")
+}
+
+// Oct is like Dec, but parses the sub-match as an octal integer, e.g.,
+// the permissions "0755".  A "0o" or "0O" prefix is accepted.
+func Oct(output interface{}) func([]byte) error {
+	return intBase("re.Oct", output, 8, "o")
+}
+
+// Bin is like Dec, but parses the sub-match as a binary integer, e.g.,
+// "1001".  A "0b" or "0B" prefix is accepted.
+func Bin(output interface{}) func([]byte) error {
+	return intBase("re.Bin", output, 2, "b")
+}
+
+// intBase returns an output argument that parses the sub-match as an
+// integer in base and stores it into output, which must point to an
+// integer type.  If prefix is not empty, a "0" followed by prefix in
+// either case is skipped after the sign.  name is used in error messages.
+func intBase(name string, output interface{}, base int, prefix string) func([]byte) error {
+	return func(b []byte) error {
+		text := string(b)
+		if prefix != "" {
+			sign, digits := splitSign(b)
+			if len(digits) > 2 && digits[0] == '0' && strings.EqualFold(digits[1:2], prefix) {
+				text = sign + digits[2:]
+			}
+		}
+		v := reflect.ValueOf(output)
+		if v.Kind() != reflect.Ptr || v.IsNil() {
+			return fmt.Errorf("%s: output has type %T; need a non-nil pointer to an integer", name, output)
+		}
+		e := v.Elem()
+		switch e.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(text, base, e.Type().Bits())
+			if err != nil {
+				return err
+			}
+			e.SetInt(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u, err := strconv.ParseUint(text, base, e.Type().Bits())
+			if err != nil {
+				return err
+			}
+			e.SetUint(u)
+		default:
+			return fmt.Errorf("%s: output has type %T; need a non-nil pointer to an integer", name, output)
+		}
+		return nil
+	}
+}
+
+// Grouped returns an output argument for Scan that removes the commas
+// grouping the digits of a number, as in "1,234,567", and stores the
+// result into output as Scan would.  output usually points to an integer
+// or floating-point type.
+func Grouped(output interface{}) func([]byte) error {
+	return GroupedBy(output, ",")
+}
+
+// GroupedBy is like Grouped, but removes occurrences of sep, e.g., "_",
+// "'" or a thin space, instead of commas.
+func GroupedBy(output interface{}, sep string) func([]byte) error {
+	return transformed(output, func(b []byte) []byte {
+		return bytes.Replace(b, []byte(sep), nil, -1)
+	})
+}
+
+// DecimalComma returns an output argument for Scan that parses numbers
+// written with a decimal comma, as is customary in much of Europe: the
+// periods grouping the digits are removed and the comma is replaced by
+// a period before the result is stored into output as Scan would.  So
+// "3,14" is stored as 3.14 and "1.234.567,8" as 1234567.8 into a
+// *float64.
+func DecimalComma(output interface{}) func([]byte) error {
+	return transformed(output, func(b []byte) []byte {
+		b = bytes.Replace(b, []byte("."), nil, -1)
+		return bytes.Replace(b, []byte(","), []byte("."), -1)
+	})
+}
+
+// Char returns an output argument for Scan that stores the sub-match,
+// which must consist of exactly one UTF-8 encoded character, into *r.
+// Without it, Scan parses the sub-match into a *rune as a number, since
+// rune is an alias of int32:
+//
+//	var sep rune
+//	reg := regexp.MustCompile(`^delimiter=(.)$`)
+//	err := re.Scan(reg, line, re.Char(&sep))
+func Char(r *rune) func([]byte) error {
+	return func(b []byte) error {
+		c, n := utf8.DecodeRune(b)
+		if len(b) == 0 || n != len(b) || (c == utf8.RuneError && n == 1) {
+			return parseError("not a single character", b)
+		}
+		*r = c
+		return nil
+	}
+}
+
+// Byte is like Char, but stores the sub-match, which must consist of
+// exactly one byte, into *c.
+func Byte(c *byte) func([]byte) error {
+	return func(b []byte) error {
+		if len(b) != 1 {
+			return parseError("not a single byte", b)
+		}
+		*c = b[0]
+		return nil
+	}
+}
@@ -0,0 +1,375 @@
+package re_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ghemawat/re"
+)
+
+func TestTime(t *testing.T) {
+	all := regexp.MustCompile(`^(.*)$`)
+	for _, c := range []struct {
+		input   string
+		layouts []string
+		result  bool
+		want    time.Time
+	}{
+		{"2020-01-02T03:04:05Z", nil, true, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"2020-01-02", nil, false, time.Time{}},
+		{"2020-01-02", []string{time.RFC3339, "2006-01-02"}, true, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Jan  2 15:04:05", []string{time.RFC3339, time.Stamp}, true, time.Date(0, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"yesterday", []string{time.RFC3339, time.Stamp}, false, time.Time{}},
+	} {
+		var got time.Time
+		err := re.Scan(all, []byte(c.input), re.Time(&got, c.layouts...))
+		if !c.result {
+			if err == nil {
+				t.Errorf("Time(%q, %q) succeeded unexpectedly", c.input, c.layouts)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Time(%q, %q): unexpected error: %s", c.input, c.layouts, err)
+			continue
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("Time(%q, %q) = %v; expected %v", c.input, c.layouts, got, c.want)
+		}
+	}
+}
+
+func TestOrZero(t *testing.T) {
+	reg := regexp.MustCompile(`user=(\w+)(?: uid=(\d*))?(?: ratio=(\S+))?`)
+	for _, c := range []struct {
+		input string
+		uid   int
+		ratio float64
+	}{
+		{"user=a uid=12 ratio=0.5", 12, 0.5},
+		{"user=a", 0, 0},
+		{"user=a uid= ratio=2", 0, 2},
+	} {
+		uid, ratio := -1, -1.0
+		if err := re.Scan(reg, []byte(c.input), nil, re.OrZero(&uid), re.OrZero(&ratio)); err != nil {
+			t.Errorf("Scan(%q): unexpected error: %s", c.input, err)
+			continue
+		}
+		if uid != c.uid || ratio != c.ratio {
+			t.Errorf("Scan(%q) = %d, %v; expected %d, %v", c.input, uid, ratio, c.uid, c.ratio)
+		}
+	}
+
+	var small int8
+	if err := re.Scan(reg, []byte("user=a uid=300"), nil, re.OrZero(&small)); err == nil {
+		t.Errorf("OrZero of out of range number succeeded unexpectedly")
+	}
+	if err := re.Scan(reg, []byte("user=a"), nil, re.OrZero(3)); err == nil {
+		t.Errorf("OrZero of a non-pointer succeeded unexpectedly")
+	}
+}
+
+func TestOneOf(t *testing.T) {
+	reg := regexp.MustCompile(`^(\w+) `)
+	var method string
+	if err := re.Scan(reg, []byte("POST /x"), re.OneOf(&method, "GET", "POST")); err != nil || method != "POST" {
+		t.Errorf("OneOf = %q, %v; expected POST", method, err)
+	}
+	err := re.Scan(reg, []byte("post /x"), re.OneOf(&method, "GET", "POST"))
+	if err == nil || err.Error() != `re.Scan: parsing "post": not one of GET, POST` {
+		t.Errorf("OneOf(post) error = %v", err)
+	}
+	if method != "POST" {
+		t.Errorf("failed OneOf changed output to %q", method)
+	}
+}
+
+func TestTransformed(t *testing.T) {
+	reg := regexp.MustCompile(`^(.{6})\|(.*)$`)
+	var n int
+	var s string
+	if err := re.Scan(reg, []byte("   42 |  Hello World "), re.Trimmed(&n), re.Lowered(re.Trimmed(&s))); err != nil {
+		t.Fatal(err)
+	}
+	if n != 42 || s != "hello world" {
+		t.Errorf("Scan = %d, %q; expected 42, %q", n, s, "hello world")
+	}
+	if err := re.Scan(reg, []byte("get   |x"), re.Uppered(re.Trimmed(&s)), nil); err != nil || s != "GET" {
+		t.Errorf("Uppered = %q, %v; expected GET", s, err)
+	}
+	if err := re.Scan(reg, []byte("      |x"), re.Trimmed(re.OrZero(&n)), nil); err != nil || n != 0 {
+		t.Errorf("Trimmed(OrZero) = %d, %v; expected 0", n, err)
+	}
+
+	// Outputs that distinguish absent groups.
+	opt := regexp.MustCompile(`^(\w+)(?: (.*))?$`)
+	p := new(int)
+	if err := re.Scan(opt, []byte("x"), nil, re.Trimmed(&p)); err != nil || p != nil {
+		t.Errorf("Trimmed into **int of absent group = %v, %v; expected nil", p, err)
+	}
+	if err := re.Scan(opt, []byte("x  7 "), nil, re.Trimmed(&p)); err != nil || p == nil || *p != 7 {
+		t.Errorf("Trimmed into **int = %v, %v; expected 7", p, err)
+	}
+}
+
+func TestUnquoted(t *testing.T) {
+	reg := regexp.MustCompile(`^v=(.*)$`)
+	for _, c := range []struct {
+		input, expect string
+	}{
+		{`v="a\tb\"c"`, "a\tb\"c"},
+		{"v=`raw\\n`", `raw\n`},
+		{`v='x'`, "x"},
+	} {
+		var s string
+		if err := re.Scan(reg, []byte(c.input), re.Unquoted(&s)); err != nil || s != c.expect {
+			t.Errorf("Unquoted(%s) = %q, %v; expected %q", c.input, s, err, c.expect)
+		}
+	}
+	var n int
+	if err := re.Scan(reg, []byte(`v="17"`), re.Unquoted(&n)); err != nil || n != 17 {
+		t.Errorf("Unquoted into int = %d, %v; expected 17", n, err)
+	}
+	var s string
+	err := re.Scan(reg, []byte(`v="abc`), re.Unquoted(&s))
+	if err == nil || err.Error() != `re.Scan: parsing ""abc": invalid quoted string` {
+		t.Errorf("Unquoted(unterminated) error = %v", err)
+	}
+
+	opt := regexp.MustCompile(`^(\w+)(?: (.*))?$`)
+	ps := new(string)
+	if err := re.Scan(opt, []byte("x"), nil, re.Unquoted(&ps)); err != nil || ps != nil {
+		t.Errorf("Unquoted into **string of absent group = %v, %v; expected nil", ps, err)
+	}
+}
+
+func TestHexBytes(t *testing.T) {
+	reg := regexp.MustCompile(`^h=(.*)$`)
+	for _, c := range []struct {
+		input  string
+		expect []byte
+	}{
+		{"h=deadBEEF", []byte{0xde, 0xad, 0xbe, 0xef}},
+		{"h=0x0102", []byte{1, 2}},
+		{"h=de:ad", []byte{0xde, 0xad}},
+		{"h=4500 0054", []byte{0x45, 0, 0, 0x54}},
+		{"h=", []byte{}},
+	} {
+		var b []byte
+		if err := re.Scan(reg, []byte(c.input), re.HexBytes(&b)); err != nil || string(b) != string(c.expect) {
+			t.Errorf("HexBytes(%q) = %x, %v; expected %x", c.input, b, err, c.expect)
+		}
+	}
+	for _, input := range []string{"h=abc", "h=zz"} {
+		b := []byte("unchanged")
+		if err := re.Scan(reg, []byte(input), re.HexBytes(&b)); err == nil || string(b) != "unchanged" {
+			t.Errorf("HexBytes(%q) = %q, %v; expected error", input, b, err)
+		}
+	}
+}
+
+func TestBase64(t *testing.T) {
+	reg := regexp.MustCompile(`^b=(.*)$`)
+	for _, c := range []struct {
+		input  string
+		enc    *base64.Encoding
+		expect string
+	}{
+		{"b=aGk/Pz4=", nil, "hi??>"},
+		{"b=aGk_Pz4", base64.RawURLEncoding, "hi??>"},
+		{"b=", nil, ""},
+	} {
+		var b []byte
+		if err := re.Scan(reg, []byte(c.input), re.Base64(&b, c.enc)); err != nil || string(b) != c.expect {
+			t.Errorf("Base64(%q) = %q, %v; expected %q", c.input, b, err, c.expect)
+		}
+	}
+	b := []byte("unchanged")
+	if err := re.Scan(reg, []byte("b=aGk_Pz4"), re.Base64(&b, nil)); err == nil || string(b) != "unchanged" {
+		t.Errorf("Base64 of URL encoding with StdEncoding = %q, %v; expected error", b, err)
+	}
+}
+
+func TestURLDecoded(t *testing.T) {
+	reg := regexp.MustCompile(`^GET (\S*)\?q=(\S*)`)
+	var path, q string
+	if err := re.Scan(reg, []byte("GET /a+b%2Fc?q=x+y%26z"), re.PathDecoded(&path), re.URLDecoded(&q)); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/a+b/c" || q != "x y&z" {
+		t.Errorf("Scan = %q, %q; expected %q, %q", path, q, "/a+b/c", "x y&z")
+	}
+	var n int
+	if err := re.Scan(reg, []byte("GET /?q=%34%32"), nil, re.URLDecoded(&n)); err != nil || n != 42 {
+		t.Errorf("URLDecoded into int = %d, %v; expected 42", n, err)
+	}
+	if err := re.Scan(reg, []byte("GET /?q=%zz"), nil, re.URLDecoded(&q)); err == nil {
+		t.Errorf("URLDecoded(%%zz) succeeded; expected error")
+	}
+
+	opt := regexp.MustCompile(`^GET ([^?]*)(?:\?q=(\S*))?$`)
+	pq := new(string)
+	if err := re.Scan(opt, []byte("GET /"), nil, re.URLDecoded(&pq)); err != nil || pq != nil {
+		t.Errorf("URLDecoded into **string of absent group = %v, %v; expected nil", pq, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	reg := regexp.MustCompile(`^ports=(.*?) hosts=(.*)$`)
+	var ports []int
+	var hosts []string
+	if err := re.Scan(reg, []byte("ports=80, 443,8080 hosts=a|b"), re.List(&ports, ","), re.List(&hosts, "|")); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ports) != "[80 443 8080]" || fmt.Sprint(hosts) != "[a b]" {
+		t.Errorf("List = %v, %v; expected [80 443 8080], [a b]", ports, hosts)
+	}
+	if err := re.Scan(reg, []byte("ports= hosts=a"), re.List(&ports, ","), nil); err != nil || len(ports) != 0 {
+		t.Errorf("List of empty sub-match = %v, %v; expected []", ports, err)
+	}
+	ports = []int{1}
+	if err := re.Scan(reg, []byte("ports=80,x hosts=a"), re.List(&ports, ","), nil); err == nil || fmt.Sprint(ports) != "[1]" {
+		t.Errorf("List with bad element = %v, %v; expected error and [1]", ports, err)
+	}
+	var n int
+	if err := re.Scan(reg, []byte("ports=80 hosts=a"), re.List(&n, ","), nil); err == nil {
+		t.Errorf("List into *int succeeded; expected error")
+	}
+}
+
+func TestKV(t *testing.T) {
+	reg := regexp.MustCompile(`^\S+ (.*)$`)
+	var attrs map[string]string
+	if err := re.Scan(reg, []byte("12:00 user=ann  path=/x=y "), re.KV(&attrs, " ", "=")); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(attrs) != "map[path:/x=y user:ann]" {
+		t.Errorf("KV = %v; expected map[path:/x=y user:ann]", attrs)
+	}
+	var counts map[string]int
+	if err := re.Scan(reg, []byte("x a: 1; b: 2;"), re.KV(&counts, ";", ":")); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(counts) != "map[a:1 b:2]" {
+		t.Errorf("KV = %v; expected map[a:1 b:2]", counts)
+	}
+	for _, input := range []string{"x a=1 b", "x a=z"} {
+		counts = nil
+		if err := re.Scan(reg, []byte(input), re.KV(&counts, " ", "=")); err == nil || counts != nil {
+			t.Errorf("KV(%q) = %v, %v; expected error", input, counts, err)
+		}
+	}
+	var list []string
+	if err := re.Scan(reg, []byte("x a=1"), re.KV(&list, " ", "=")); err == nil {
+		t.Errorf("KV into *[]string succeeded; expected error")
+	}
+}
+
+func TestDec(t *testing.T) {
+	reg := regexp.MustCompile(`^(\S+) (\S+)$`)
+	var i int
+	var u uint8
+	if err := re.Scan(reg, []byte("010 -0"), re.Dec(&u), re.Dec(&i)); err != nil || u != 10 || i != 0 {
+		t.Errorf("Dec = %d, %d, %v; expected 10, 0", u, i, err)
+	}
+	for _, input := range []string{"0x10 1", "1_0 1", "256 1", "-1 1"} {
+		if err := re.Scan(reg, []byte(input), re.Dec(&u), re.Dec(&i)); err == nil {
+			t.Errorf("Dec(%q) succeeded; expected error", input)
+		}
+	}
+	var s string
+	if err := re.Scan(reg, []byte("1 2"), re.Dec(&s), nil); err == nil {
+		t.Errorf("Dec into *string succeeded; expected error")
+	}
+}
+
+func TestIntBases(t *testing.T) {
+	reg := regexp.MustCompile(`^(\S+)$`)
+	for _, c := range []struct {
+		input  string
+		wrap   func(interface{}) func([]byte) error
+		expect int64
+	}{
+		{"deadbeef", re.Hex, 0xdeadbeef},
+		{"-0XFF", re.Hex, -255},
+		{"0755", re.Oct, 0755},
+		{"0o17", re.Oct, 15},
+		{"1001", re.Bin, 9},
+		{"0b11", re.Bin, 3},
+		{"0", re.Bin, 0},
+	} {
+		var n int64
+		if err := re.Scan(reg, []byte(c.input), c.wrap(&n)); err != nil || n != c.expect {
+			t.Errorf("Scan(%q) = %d, %v; expected %d", c.input, n, err, c.expect)
+		}
+	}
+	var u uint16
+	for _, c := range []struct {
+		input string
+		wrap  func(interface{}) func([]byte) error
+	}{
+		{"0x", re.Hex},
+		{"10000", re.Hex},
+		{"8", re.Oct},
+		{"12", re.Bin},
+		{"0x1", re.Bin},
+	} {
+		if err := re.Scan(reg, []byte(c.input), c.wrap(&u)); err == nil {
+			t.Errorf("Scan(%q) = %d; expected error", c.input, u)
+		}
+	}
+}
+
+func TestGrouped(t *testing.T) {
+	reg := regexp.MustCompile(`^total ([\d,.]+) in (\S+)s$`)
+	var n int
+	var f float64
+	if err := re.Scan(reg, []byte("total 1,234,567 in 1,000.5s"), re.Grouped(&n), re.Grouped(&f)); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1234567 || f != 1000.5 {
+		t.Errorf("Grouped = %d, %v; expected 1234567, 1000.5", n, f)
+	}
+	if err := re.Scan(reg, []byte("total 1 in 12'345s"), nil, re.GroupedBy(&n, "'")); err != nil || n != 12345 {
+		t.Errorf("GroupedBy = %d, %v; expected 12345", n, err)
+	}
+}
+
+func TestDecimalComma(t *testing.T) {
+	reg := regexp.MustCompile(`^(\S+)$`)
+	for _, c := range []struct {
+		input  string
+		expect float64
+	}{
+		{"3,14", 3.14},
+		{"-1.234.567,8", -1234567.8},
+		{"42", 42},
+	} {
+		var f float64
+		if err := re.Scan(reg, []byte(c.input), re.DecimalComma(&f)); err != nil || f != c.expect {
+			t.Errorf("DecimalComma(%q) = %v, %v; expected %v", c.input, f, err, c.expect)
+		}
+	}
+	var f float32
+	if err := re.Scan(reg, []byte("1,2,3"), re.DecimalComma(&f)); err == nil {
+		t.Errorf("DecimalComma(1,2,3) = %v; expected error", f)
+	}
+}
+
+func TestChar(t *testing.T) {
+	reg := regexp.MustCompile(`^(.*)\|(.*)$`)
+	var r rune
+	var c byte
+	if err := re.Scan(reg, []byte("é|7"), re.Char(&r), re.Byte(&c)); err != nil || r != 'é' || c != '7' {
+		t.Errorf("Scan = %q, %q, %v; expected 'é', '7'", r, c, err)
+	}
+	for _, input := range []string{"|x", "ab|x", "\xff|x", "x|", "x|é", "x|ab"} {
+		r, c = 0, 0
+		if err := re.Scan(reg, []byte(input), re.Char(&r), re.Byte(&c)); err == nil {
+			t.Errorf("Scan(%q) = %q, %q; expected error", input, r, c)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package zone
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// IP returns the address of an A or AAAA record.
+func (r Record) IP() (net.IP, error) {
+	if err := r.check(1, "A", "AAAA"); err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(r.Data[0])
+	if ip == nil || (r.Type == "A") != (ip.To4() != nil) {
+		return nil, fmt.Errorf("zone: bad %s address %q", r.Type, r.Data[0])
+	}
+	return ip, nil
+}
+
+// Target returns the domain name of an NS, CNAME, DNAME or PTR record.
+func (r Record) Target() (string, error) {
+	if err := r.check(1, "NS", "CNAME", "DNAME", "PTR"); err != nil {
+		return "", err
+	}
+	return r.Data[0], nil
+}
+
+// Text returns the strings of a TXT or SPF record, concatenated as DNS
+// clients do.
+func (r Record) Text() (string, error) {
+	if err := r.check(1, "TXT", "SPF"); err != nil {
+		return "", err
+	}
+	return strings.Join(r.Data, ""), nil
+}
+
+// MX is the data of an MX record.
+type MX struct {
+	Preference uint16
+	Host       string
+}
+
+// MX returns the data of an MX record.
+func (r Record) MX() (MX, error) {
+	if err := r.check(2, "MX"); err != nil {
+		return MX{}, err
+	}
+	p, err := strconv.ParseUint(r.Data[0], 10, 16)
+	if err != nil {
+		return MX{}, fmt.Errorf("zone: bad MX preference %q", r.Data[0])
+	}
+	return MX{uint16(p), r.Data[1]}, nil
+}
+
+// SRV is the data of an SRV record.
+type SRV struct {
+	Priority, Weight, Port uint16
+	Target                 string
+}
+
+// SRV returns the data of an SRV record.
+func (r Record) SRV() (SRV, error) {
+	if err := r.check(4, "SRV"); err != nil {
+		return SRV{}, err
+	}
+	var v [3]uint16
+	for i := range v {
+		n, err := strconv.ParseUint(r.Data[i], 10, 16)
+		if err != nil {
+			return SRV{}, fmt.Errorf("zone: bad SRV field %q", r.Data[i])
+		}
+		v[i] = uint16(n)
+	}
+	return SRV{v[0], v[1], v[2], r.Data[3]}, nil
+}
+
+// SOA is the data of an SOA record.  Times are in seconds.
+type SOA struct {
+	MName, RName                    string
+	Serial                          uint32
+	Refresh, Retry, Expire, Minimum uint32
+}
+
+// SOA returns the data of an SOA record.  The timer fields may use TTL
+// units, e.g., "1d".
+func (r Record) SOA() (SOA, error) {
+	if err := r.check(7, "SOA"); err != nil {
+		return SOA{}, err
+	}
+	var v [5]uint32
+	for i := range v {
+		n, err := ParseTTL(r.Data[2+i])
+		if err != nil {
+			return SOA{}, fmt.Errorf("zone: bad SOA field %q", r.Data[2+i])
+		}
+		v[i] = n
+	}
+	return SOA{r.Data[0], r.Data[1], v[0], v[1], v[2], v[3], v[4]}, nil
+}
+
+// check returns an error unless r has one of the given types and at
+// least n data fields.
+func (r Record) check(n int, types ...string) error {
+	for _, t := range types {
+		if r.Type == t {
+			if len(r.Data) < n {
+				return fmt.Errorf("zone: %s record for %s has %d data fields; need %d", r.Type, r.Name, len(r.Data), n)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("zone: %s record for %s is not %s", r.Type, r.Name, strings.Join(types, " or "))
+}
@@ -0,0 +1,50 @@
+package zone_test
+
+import (
+	"testing"
+
+	"github.com/ghemawat/re/zone"
+)
+
+func TestRData(t *testing.T) {
+	records := readAll(t, example)
+	soa, err := records[0].SOA()
+	if err != nil || soa != (zone.SOA{"ns1.example.com.", "hostmaster.example.com.", 2024011501, 86400, 7200, 2419200, 3600}) {
+		t.Errorf("SOA() = %+v, %v", soa, err)
+	}
+	if ns, err := records[1].Target(); err != nil || ns != "ns1.example.com." {
+		t.Errorf("Target() = %q, %v", ns, err)
+	}
+	if mx, err := records[2].MX(); err != nil || mx != (zone.MX{10, "mail.example.com."}) {
+		t.Errorf("MX() = %+v, %v", mx, err)
+	}
+	if ip, err := records[4].IP(); err != nil || ip.String() != "192.0.2.1" {
+		t.Errorf("IP() = %v, %v", ip, err)
+	}
+	if ip, err := records[5].IP(); err != nil || ip.String() != "2001:db8::1" {
+		t.Errorf("IP() = %v, %v", ip, err)
+	}
+	if txt, err := records[6].Text(); err != nil || txt != "v=spf1 -all; not a comment" {
+		t.Errorf("Text() = %q, %v", txt, err)
+	}
+	if srv, err := records[7].SRV(); err != nil || srv != (zone.SRV{10, 60, 5060, "sip.example.com."}) {
+		t.Errorf("SRV() = %+v, %v", srv, err)
+	}
+
+	for _, c := range []struct {
+		rec zone.Record
+		f   func(zone.Record) error
+	}{
+		{zone.Record{Type: "MX"}, func(r zone.Record) error { _, err := r.IP(); return err }},
+		{zone.Record{Type: "A", Data: []string{"2001:db8::1"}}, func(r zone.Record) error { _, err := r.IP(); return err }},
+		{zone.Record{Type: "MX", Data: []string{"10"}}, func(r zone.Record) error { _, err := r.MX(); return err }},
+		{zone.Record{Type: "MX", Data: []string{"ten", "x."}}, func(r zone.Record) error { _, err := r.MX(); return err }},
+		{zone.Record{Type: "SRV", Data: []string{"1", "2", "99999", "x."}}, func(r zone.Record) error { _, err := r.SRV(); return err }},
+		{zone.Record{Type: "SOA", Data: []string{"a.", "b.", "1", "2", "3", "4", "soon"}}, func(r zone.Record) error { _, err := r.SOA(); return err }},
+		{zone.Record{Type: "A", Data: []string{"x"}}, func(r zone.Record) error { _, err := r.Target(); return err }},
+	} {
+		if err := c.f(c.rec); err == nil {
+			t.Errorf("accessor on %+v succeeded unexpectedly", c.rec)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Package zone reads DNS zone files in the format of RFC 1035, as used by
+BIND and most other name servers:
+
+	$ORIGIN example.com.
+	$TTL 1h
+	@       IN SOA ns1 hostmaster (2024011501 1d 2h 4w 1h)
+	        IN NS  ns1
+	www  5m    A   192.0.2.1
+	             AAAA 2001:db8::1
+
+Fields omitted from a record are inherited as the format specifies: a
+line starting with white space belongs to the previous owner name, and
+a missing TTL or class is taken from $TTL or the previous record.
+Relative names, in owner names and in the data of common record types,
+are qualified with the current origin.
+*/
+package zone
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A Record is one resource record.
+type Record struct {
+	Name  string   // Fully qualified owner name, e.g., "www.example.com."
+	TTL   uint32   // In seconds
+	Class string   // Usually "IN"
+	Type  string   // Upper case, e.g., "MX"
+	Data  []string // Fields of the record data, without quotes
+}
+
+// A Reader reads records from a zone file.
+type Reader struct {
+	s      *bufio.Scanner
+	line   int
+	origin string
+	ttl    uint32 // From $TTL
+	hasTTL bool
+
+	// Values inherited by the next record.
+	name    string
+	lastTTL uint32
+	hasLast bool
+	class   string
+}
+
+// NewReader returns a Reader that reads from r.  origin, which should be
+// fully qualified (end with "."), is used until a $ORIGIN directive is
+// seen; it may be empty if the file sets its own.
+func NewReader(r io.Reader, origin string) *Reader {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	return &Reader{s: s, origin: origin}
+}
+
+// Next returns the next record.  It returns io.EOF at the end of the
+// input.  Errors report the number of the offending line.  $INCLUDE
+// directives are not supported.
+func (r *Reader) Next() (Record, error) {
+	for {
+		tokens, blankStart, err := r.logicalLine()
+		if err != nil {
+			return Record{}, err
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		if !blankStart && strings.HasPrefix(tokens[0], "$") {
+			if err := r.directive(tokens); err != nil {
+				return Record{}, r.errorf("%s", err)
+			}
+			continue
+		}
+		rec, err := r.record(tokens, blankStart)
+		if err != nil {
+			return Record{}, r.errorf("%s", err)
+		}
+		return rec, nil
+	}
+}
+
+func (r *Reader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("zone: line %d: "+format, append([]interface{}{r.line}, args...)...)
+}
+
+// directive handles a $ORIGIN or $TTL line.
+func (r *Reader) directive(tokens []string) error {
+	switch strings.ToUpper(tokens[0]) {
+	case "$ORIGIN":
+		if len(tokens) != 2 {
+			return errors.New("$ORIGIN needs one name")
+		}
+		r.origin = r.qualify(tokens[1])
+	case "$TTL":
+		if len(tokens) != 2 {
+			return errors.New("$TTL needs one value")
+		}
+		ttl, err := ParseTTL(tokens[1])
+		if err != nil {
+			return err
+		}
+		r.ttl, r.hasTTL = ttl, true
+	default:
+		return fmt.Errorf("unsupported directive %s", tokens[0])
+	}
+	return nil
+}
+
+// classes lists the record classes.
+var classes = map[string]bool{"IN": true, "CH": true, "HS": true, "CS": true}
+
+// record parses the tokens of a record line.
+func (r *Reader) record(tokens []string, blankStart bool) (Record, error) {
+	var rec Record
+	if blankStart {
+		if r.name == "" {
+			return Record{}, errors.New("no previous owner name")
+		}
+		rec.Name = r.name
+	} else {
+		rec.Name = r.qualify(tokens[0])
+		tokens = tokens[1:]
+	}
+
+	// TTL and class may appear in either order before the type.
+	hasTTL := false
+	for i := 0; i < 2 && len(tokens) > 0; i++ {
+		if c := strings.ToUpper(tokens[0]); classes[c] && rec.Class == "" {
+			rec.Class = c
+			tokens = tokens[1:]
+		} else if ttl, err := ParseTTL(tokens[0]); err == nil && !hasTTL {
+			rec.TTL, hasTTL = ttl, true
+			tokens = tokens[1:]
+		}
+	}
+	if len(tokens) == 0 {
+		return Record{}, errors.New("missing record type")
+	}
+	rec.Type = strings.ToUpper(tokens[0])
+	rec.Data = tokens[1:]
+
+	switch {
+	case hasTTL:
+	case r.hasTTL:
+		rec.TTL = r.ttl
+	case r.hasLast:
+		rec.TTL = r.lastTTL
+	default:
+		return Record{}, errors.New("no TTL and no $TTL or previous record")
+	}
+	if rec.Class == "" {
+		rec.Class = r.class
+		if rec.Class == "" {
+			rec.Class = "IN"
+		}
+	}
+	for _, i := range nameFields[rec.Type] {
+		if i < len(rec.Data) {
+			rec.Data[i] = r.qualify(rec.Data[i])
+		}
+	}
+	r.name, r.class = rec.Name, rec.Class
+	r.lastTTL, r.hasLast = rec.TTL, true
+	return rec, nil
+}
+
+// nameFields lists the indexes of the domain names in the data of
+// common record types.
+var nameFields = map[string][]int{
+	"NS":    {0},
+	"CNAME": {0},
+	"DNAME": {0},
+	"PTR":   {0},
+	"MX":    {1},
+	"SRV":   {3},
+	"SOA":   {0, 1},
+}
+
+// qualify makes name fully qualified relative to the origin.
+func (r *Reader) qualify(name string) string {
+	switch {
+	case name == "@":
+		return r.origin
+	case strings.HasSuffix(name, ".") || r.origin == "":
+		return name
+	case r.origin == ".":
+		return name + "."
+	}
+	return name + "." + r.origin
+}
+
+// logicalLine returns the tokens of the next logical line, which spans
+// several physical lines if it has parentheses, and reports whether the
+// line started with white space.  Comments are removed.
+func (r *Reader) logicalLine() ([]string, bool, error) {
+	var tokens []string
+	blankStart, depth, first := false, 0, true
+	for {
+		if !r.s.Scan() {
+			if err := r.s.Err(); err != nil {
+				return nil, false, err
+			}
+			if depth > 0 {
+				return nil, false, r.errorf("unterminated parenthesis")
+			}
+			if first {
+				return nil, false, io.EOF
+			}
+			return tokens, blankStart, nil
+		}
+		r.line++
+		line := r.s.Text()
+		if first {
+			blankStart = line != "" && (line[0] == ' ' || line[0] == '\t')
+			first = false
+		}
+		var err error
+		if tokens, depth, err = tokenize(line, tokens, depth); err != nil {
+			return nil, false, r.errorf("%s", err)
+		}
+		if depth == 0 {
+			return tokens, blankStart, nil
+		}
+	}
+}
+
+// tokenize appends the tokens of line to tokens, tracking the depth of
+// parentheses.  Quoted strings become single tokens without quotes.
+func tokenize(line string, tokens []string, depth int) ([]string, int, error) {
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == ';':
+			return tokens, depth, nil
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(':
+			depth++
+			i++
+		case c == ')':
+			if depth--; depth < 0 {
+				return nil, 0, errors.New("unbalanced parenthesis")
+			}
+			i++
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(line) && line[j] != '"'; j++ {
+				if line[j] == '\\' && j+1 < len(line) {
+					j++
+				}
+				b.WriteByte(line[j])
+			}
+			if j == len(line) {
+				return nil, 0, errors.New("unterminated string")
+			}
+			tokens = append(tokens, b.String())
+			i = j + 1
+		default:
+			j := i
+			for j < len(line) && !unicode.IsSpace(rune(line[j])) && strings.IndexByte(`;()"`, line[j]) < 0 {
+				j++
+			}
+			tokens = append(tokens, line[i:j])
+			i = j
+		}
+	}
+	return tokens, depth, nil
+}
+
+// ttlUnits maps the unit letters accepted in TTLs to seconds.
+var ttlUnits = map[byte]uint64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+
+// ParseTTL parses a TTL given in seconds (e.g., "3600") or, as BIND
+// allows, as a sequence of numbers with units s, m, h, d and w (e.g.,
+// "1h30m"), ignoring case.
+func ParseTTL(s string) (uint32, error) {
+	if s == "" {
+		return 0, errors.New("empty TTL")
+	}
+	if v, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return uint32(v), nil
+	}
+	var total, n uint64
+	digits := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= '0' && c <= '9' {
+			n = n*10 + uint64(c-'0')
+			digits = true
+			if n > 1<<32 {
+				return 0, fmt.Errorf("TTL %q out of range", s)
+			}
+			continue
+		}
+		unit, ok := ttlUnits[byte(unicode.ToLower(rune(c)))]
+		if !ok || !digits {
+			return 0, fmt.Errorf("bad TTL %q", s)
+		}
+		total += n * unit
+		n, digits = 0, false
+	}
+	if digits {
+		return 0, fmt.Errorf("bad TTL %q", s)
+	}
+	if total > 1<<32-1 {
+		return 0, fmt.Errorf("TTL %q out of range", s)
+	}
+	return uint32(total), nil
+}
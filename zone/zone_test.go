@@ -0,0 +1,106 @@
+package zone_test
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghemawat/re/zone"
+)
+
+const example = `$ORIGIN example.com.
+$TTL 1h
+@       IN SOA ns1 hostmaster.example.com. ( 2024011501 ; serial
+                1d 2h 4w 1h )
+        IN NS  ns1
+        IN MX  10 mail
+ns1     IN A   192.0.2.53
+www  5m    A   192.0.2.1   ; comment
+             AAAA 2001:db8::1
+text 300 IN TXT "v=spf1 -all" "; not a comment"
+_sip._tcp SRV 10 60 5060 sip
+$ORIGIN sub.example.com.
+alias CH 60 CNAME www.example.com.
+host    A 192.0.2.7
+`
+
+func readAll(t *testing.T, input string) []zone.Record {
+	r := zone.NewReader(strings.NewReader(input), "")
+	var records []zone.Record
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return records
+		} else if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestReader(t *testing.T) {
+	got := readAll(t, example)
+	want := []zone.Record{
+		{"example.com.", 3600, "IN", "SOA", []string{"ns1.example.com.", "hostmaster.example.com.", "2024011501", "1d", "2h", "4w", "1h"}},
+		{"example.com.", 3600, "IN", "NS", []string{"ns1.example.com."}},
+		{"example.com.", 3600, "IN", "MX", []string{"10", "mail.example.com."}},
+		{"ns1.example.com.", 3600, "IN", "A", []string{"192.0.2.53"}},
+		{"www.example.com.", 300, "IN", "A", []string{"192.0.2.1"}},
+		{"www.example.com.", 3600, "IN", "AAAA", []string{"2001:db8::1"}},
+		{"text.example.com.", 300, "IN", "TXT", []string{"v=spf1 -all", "; not a comment"}},
+		{"_sip._tcp.example.com.", 3600, "IN", "SRV", []string{"10", "60", "5060", "sip.example.com."}},
+		{"alias.sub.example.com.", 60, "CH", "CNAME", []string{"www.example.com."}},
+		{"host.sub.example.com.", 3600, "CH", "A", []string{"192.0.2.7"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d records; expected %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("record %d = %+v; expected %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestInheritedTTL(t *testing.T) {
+	// Without $TTL, a missing TTL is inherited from the previous record.
+	got := readAll(t, "a.example. 120 A 192.0.2.1\nb.example. A 192.0.2.2\n")
+	if len(got) != 2 || got[1].TTL != 120 {
+		t.Errorf("records = %+v", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  string
+	}{
+		{"  A 192.0.2.1\n", "line 1: no previous owner name"},
+		{"a.example. A 192.0.2.1\n", "no TTL"},
+		{"$TTL 1h\na.example. IN\n", "line 2: missing record type"},
+		{"$TTL soon\n", "bad TTL"},
+		{"$INCLUDE other\n", "unsupported directive"},
+		{"$TTL 1h\na. SOA ( x\n", "unterminated parenthesis"},
+		{"$TTL 1h\na. TXT \"x\n", "unterminated string"},
+		{"$TTL 1h\na. A ) x\n", "unbalanced parenthesis"},
+	} {
+		_, err := zone.NewReader(strings.NewReader(c.input), "example.").Next()
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("Next(%q) error = %v; expected one containing %q", c.input, err, c.want)
+		}
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	for s, want := range map[string]uint32{"0": 0, "3600": 3600, "1h": 3600, "1H30m": 5400, "1w2d": 777600, "4294967295": 4294967295} {
+		if got, err := zone.ParseTTL(s); err != nil || got != want {
+			t.Errorf("ParseTTL(%q) = %d, %v; expected %d", s, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "h", "1x", "1h30", "4294967296", "10000000w"} {
+		if got, err := zone.ParseTTL(bad); err == nil {
+			t.Errorf("ParseTTL(%q) = %d; expected an error", bad, got)
+		}
+	}
+}